@@ -1,15 +1,29 @@
 package client
 
 import (
+	"context"
 	"github.com/leighmacdonald/mika/config"
 	pb "github.com/leighmacdonald/mika/proto"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 )
 
+// apiKey sends the api key with every request, the tracker records the name of the key
+// as the actor in the audit log
+type apiKey string
+
+func (k apiKey) GetRequestMetadata(_ context.Context, _ ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(k)}, nil
+}
+
+func (k apiKey) RequireTransportSecurity() bool {
+	return false
+}
+
 func New() (pb.MikaClient, error) {
 	var opts []grpc.DialOption
 	opts = append(opts, grpc.WithInsecure())
+	opts = append(opts, grpc.WithPerRPCCredentials(apiKey(config.API.Key)))
 	c, err := grpc.Dial(config.API.Listen, opts...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to dial tracker")
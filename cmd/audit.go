@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jedib0t/go-pretty/v6/table"
+	pb "github.com/leighmacdonald/mika/proto"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	auditActor  string
+	auditAction string
+	auditTarget string
+	auditSince  string
+	auditLimit  uint32
+	auditJSON   bool
+)
+
+// parseSince accepts either a duration before now, eg: 24h, or a RFC3339 timestamp
+func parseSince(value string) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return t, errors.Errorf("invalid since value: %s (eg: 24h or 2020-01-02T15:04:05Z)", value)
+	}
+	return t, nil
+}
+
+// auditChanges summarises the fields which differ between the before and after values
+func auditChanges(before string, after string) string {
+	switch {
+	case before == "" && after == "":
+		return ""
+	case before == "":
+		return "created"
+	case after == "":
+		return "deleted"
+	}
+	var b, a map[string]interface{}
+	if json.Unmarshal([]byte(before), &b) != nil || json.Unmarshal([]byte(after), &a) != nil {
+		return "modified"
+	}
+	var changes []string
+	for k, v := range a {
+		if !reflect.DeepEqual(b[k], v) {
+			changes = append(changes, fmt.Sprintf("%s: %v -> %v", k, b[k], v))
+		}
+	}
+	sort.Strings(changes)
+	return strings.Join(changes, "\n")
+}
+
+func renderAudit(entries []*pb.AuditEntry) {
+	t := defaultTable("Audit Log")
+	t.AppendHeader(table.Row{"id", "time", "actor", "address", "action", "target", "changes"})
+	for _, e := range entries {
+		t.AppendRow(table.Row{e.AuditId, e.CreatedOn.AsTime().Format(time.RFC3339), e.Actor, e.Address,
+			e.Action, e.Target, auditChanges(e.Before, e.After)})
+	}
+	t.Render()
+}
+
+// auditCmd shows the administrative changes made through the API
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show the audit log of changes made through the API",
+	Long: `Show the audit log of changes made through the API, newest first. Each entry records the
+name of the api key used, the address of the caller and the values before and after the change.
+
+The action filter matches either the full action, eg: user.save, or every action of an
+object, eg: user.`,
+	Args:              cobra.NoArgs,
+	PersistentPreRunE: connectRPC,
+	Run: func(cmd *cobra.Command, args []string) {
+		params := &pb.AuditListParams{
+			Actor:  auditActor,
+			Action: auditAction,
+			Target: auditTarget,
+			Limit:  auditLimit,
+		}
+		if auditSince != "" {
+			since, err := parseSince(auditSince)
+			if err != nil {
+				log.Fatal(err.Error())
+				return
+			}
+			params.Since = timestamppb.New(since)
+		}
+		resp, err := cl.AuditList(context.Background(), params)
+		if err != nil {
+			log.Fatalf("Failed to fetch audit log: %v", err)
+			return
+		}
+		if auditJSON {
+			for _, e := range resp.Entries {
+				b, errMarshal := protojson.MarshalOptions{UseProtoNames: true}.Marshal(e)
+				if errMarshal != nil {
+					log.Fatalf("Failed to encode audit entry: %v", errMarshal)
+					return
+				}
+				_, _ = fmt.Fprintln(os.Stdout, string(b))
+			}
+			return
+		}
+		renderAudit(resp.Entries)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.Flags().StringVarP(&auditActor, "actor", "a", "", "Only show changes made with the named api key")
+	auditCmd.Flags().StringVarP(&auditAction, "action", "A", "", "Only show the action, eg: user.save or user")
+	auditCmd.Flags().StringVarP(&auditTarget, "target", "t", "", "Only show changes to the target, eg: user:10")
+	auditCmd.Flags().StringVarP(&auditSince, "since", "s", "",
+		"Only show changes after the time, as a duration eg: 24h or a RFC3339 timestamp")
+	auditCmd.Flags().Uint32VarP(&auditLimit, "limit", "l", 50, "Maximum number of entries shown, 0 for all")
+	auditCmd.Flags().BoolVarP(&auditJSON, "json", "j", false, "Print the entries as json lines")
+}
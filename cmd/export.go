@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"io"
+	"os"
+)
+
+var exportFormat string
+
+// exportCmd writes users, torrents or roles to a csv or jsonl file
+var exportCmd = &cobra.Command{
+	Use:   "export <users|torrents|roles> [file]",
+	Short: "Bulk export users, torrents or roles",
+	Long: `Bulk export users, torrents or roles to a csv or jsonl file which can be loaded with
+mika import. The output is written to stdout if no file is given.`,
+	Args:              cobra.RangeArgs(1, 2),
+	PersistentPreRunE: connectRPC,
+	Run: func(cmd *cobra.Command, args []string) {
+		path := "-"
+		if len(args) == 2 {
+			path = args[1]
+		}
+		format, err := detectFormat(exportFormat, path)
+		if err != nil {
+			log.Fatal(err.Error())
+			return
+		}
+		out := os.Stdout
+		if path != "-" {
+			f, err := os.Create(path)
+			if err != nil {
+				log.Fatalf("Failed to create export file: %v", err)
+				return
+			}
+			defer func() { _ = f.Close() }()
+			out = f
+		}
+		writer := newRecordWriter(out, format)
+		var count int
+		switch args[0] {
+		case "users":
+			count, err = exportUsers(writer)
+		case "torrents":
+			count, err = exportTorrents(writer)
+		case "roles":
+			count, err = exportRoles(writer)
+		default:
+			log.Fatalf("Unknown export type: %s", args[0])
+			return
+		}
+		if err == nil {
+			err = writer.Flush()
+		}
+		if err != nil {
+			log.Fatalf("Failed to export %s: %v", args[0], err)
+			return
+		}
+		// Logging is sent to stdout so avoid mixing it with the exported records
+		if path != "-" {
+			log.Infof("Exported %d %s", count, args[0])
+		}
+	},
+}
+
+func exportUsers(w recordWriter) (int, error) {
+	stream, err := cl.UserAll(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to fetch users")
+	}
+	count := 0
+	for {
+		u, err := stream.Recv()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, errors.Wrap(err, "Failed to fetch users")
+		}
+		if err := w.Write(&userRecord{
+			UserID:          u.UserId,
+			RoleID:          u.RoleId,
+			RemoteID:        u.RemoteId,
+			UserName:        u.UserName,
+			Passkey:         u.Passkey,
+			DownloadEnabled: u.DownloadEnabled,
+			Downloaded:      u.Downloaded,
+			Uploaded:        u.Uploaded,
+			Announces:       u.Announces,
+		}); err != nil {
+			return count, err
+		}
+		count++
+	}
+}
+
+func exportTorrents(w recordWriter) (int, error) {
+	stream, err := cl.TorrentAll(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to fetch torrents")
+	}
+	count := 0
+	for {
+		t, err := stream.Recv()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, errors.Wrap(err, "Failed to fetch torrents")
+		}
+		if t.IsDeleted {
+			continue
+		}
+		if err := w.Write(&torrentRecord{
+			InfoHash:   encodeHex(t.InfoHash),
+			InfoHashV2: encodeHex(t.InfoHashV2),
+			Title:      t.Title,
+			IsEnabled:  t.IsEnabled,
+			Reason:     t.Reason,
+			MultiUp:    t.MultiUp,
+			MultiDn:    t.MultiDn,
+			Snatches:   t.Snatches,
+			Uploaded:   t.Uploaded,
+			Downloaded: t.Downloaded,
+			Announces:  t.Announces,
+		}); err != nil {
+			return count, err
+		}
+		count++
+	}
+}
+
+func exportRoles(w recordWriter) (int, error) {
+	stream, err := cl.RoleAll(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to fetch roles")
+	}
+	count := 0
+	for {
+		r, err := stream.Recv()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, errors.Wrap(err, "Failed to fetch roles")
+		}
+		if err := w.Write(&roleRecord{
+			RoleID:          r.RoleId,
+			RoleName:        r.RoleName,
+			RemoteID:        r.RemoteId,
+			Priority:        r.Priority,
+			DownloadEnabled: r.DownloadEnabled,
+			UploadEnabled:   r.UploadEnabled,
+			MultiUp:         r.MultiUp,
+			MultiDown:       r.MultiDown,
+			MaxPeers:        r.MaxPeers,
+		}); err != nil {
+			return count, err
+		}
+		count++
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "",
+		"File format, csv or jsonl (default: from file extension, otherwise jsonl)")
+}
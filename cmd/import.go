@@ -0,0 +1,304 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/jedib0t/go-pretty/v6/table"
+	pb "github.com/leighmacdonald/mika/proto"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+var (
+	importFormat  string
+	importOptions = &pb.ImportOptions{}
+)
+
+// importCmd loads users, torrents or roles from a csv or jsonl file
+var importCmd = &cobra.Command{
+	Use:   "import <users|torrents|roles> <file>",
+	Short: "Bulk import users, torrents or roles",
+	Long: `Bulk import users, torrents or roles from a csv or jsonl file, use - to read from stdin.
+
+CSV files must have a header row naming the columns, JSONL files contain one JSON object per
+line. The column and key names match those written by mika export. Existing users (by passkey),
+torrents (by info_hash) and roles (by role_name) are reported as errors unless --upsert is used.`,
+	Args:              cobra.ExactArgs(2),
+	PersistentPreRunE: connectRPC,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, err := detectFormat(importFormat, args[1])
+		if err != nil {
+			log.Fatal(err.Error())
+			return
+		}
+		in := os.Stdin
+		if args[1] != "-" {
+			f, err := os.Open(args[1])
+			if err != nil {
+				log.Fatalf("Failed to open import file: %v", err)
+				return
+			}
+			defer func() { _ = f.Close() }()
+			in = f
+		}
+		reader := newRecordReader(in, format)
+		var resp *pb.ImportResponse
+		switch args[0] {
+		case "users":
+			resp, err = importUsers(reader, importOptions)
+		case "torrents":
+			resp, err = importTorrents(reader, importOptions)
+		case "roles":
+			resp, err = importRoles(reader, importOptions)
+		default:
+			log.Fatalf("Unknown import type: %s", args[0])
+			return
+		}
+		if err != nil {
+			log.Fatalf("Failed to import %s: %v", args[0], err)
+			return
+		}
+		renderImportResponse(resp, args[0])
+	},
+}
+
+func renderImportResponse(resp *pb.ImportResponse, kind string) {
+	if len(resp.Errors) > 0 {
+		t := defaultTable(fmt.Sprintf("Failed %s", kind))
+		t.AppendHeader(table.Row{"row", "error"})
+		for _, e := range resp.Errors {
+			t.AppendRow(table.Row{e.Row, e.Error})
+		}
+		t.Render()
+	}
+	prefix := ""
+	if resp.DryRun {
+		prefix = "Dry run: "
+	}
+	log.Infof("%s%d %s created, %d updated, %d failed", prefix, resp.Created, kind, resp.Updated, resp.Failed)
+}
+
+// readRecords calls fn with each record read successfully. Records which could not be decoded
+// are returned as import errors.
+func readRecords(reader recordReader, newRecord func() interface{}, fn func(row int, rec interface{}) error) ([]*pb.ImportError, error) {
+	var failed []*pb.ImportError
+	for {
+		rec := newRecord()
+		row, err := reader.Next(rec)
+		if err == io.EOF {
+			return failed, nil
+		}
+		if err != nil {
+			if _, ok := err.(recordError); ok {
+				failed = append(failed, &pb.ImportError{Row: uint32(row), Error: err.Error()})
+				continue
+			}
+			return nil, err
+		}
+		if err := fn(row, rec); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// mergeImportErrors adds the rows which failed to decode locally to the response
+func mergeImportErrors(resp *pb.ImportResponse, failed []*pb.ImportError) *pb.ImportResponse {
+	resp.Errors = append(resp.Errors, failed...)
+	sort.Slice(resp.Errors, func(i, j int) bool {
+		return resp.Errors[i].Row < resp.Errors[j].Row
+	})
+	resp.Failed = uint32(len(resp.Errors))
+	return resp
+}
+
+func importUsers(reader recordReader, opts *pb.ImportOptions) (*pb.ImportResponse, error) {
+	stream, err := cl.UserImport(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	first := true
+	failed, err := readRecords(reader, newUserRecord, func(row int, rec interface{}) error {
+		r := rec.(*userRecord)
+		p := &pb.UserImportParams{Row: uint32(row), User: &pb.User{
+			UserId:          r.UserID,
+			RoleId:          r.RoleID,
+			RemoteId:        r.RemoteID,
+			UserName:        r.UserName,
+			Passkey:         r.Passkey,
+			DownloadEnabled: r.DownloadEnabled,
+			Downloaded:      r.Downloaded,
+			Uploaded:        r.Uploaded,
+			Announces:       r.Announces,
+		}}
+		if first {
+			p.Options = opts
+			first = false
+		}
+		return stream.Send(p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if first {
+		_ = stream.CloseSend()
+		return mergeImportErrors(&pb.ImportResponse{DryRun: opts.DryRun}, failed), nil
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, err
+	}
+	return mergeImportErrors(resp, failed), nil
+}
+
+func importTorrents(reader recordReader, opts *pb.ImportOptions) (*pb.ImportResponse, error) {
+	stream, err := cl.TorrentImport(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	var failedHex []*pb.ImportError
+	first := true
+	failed, err := readRecords(reader, newTorrentRecord, func(row int, rec interface{}) error {
+		r := rec.(*torrentRecord)
+		ih, err := decodeHex(r.InfoHash)
+		if err != nil {
+			failedHex = append(failedHex, &pb.ImportError{Row: uint32(row), Error: "invalid info_hash"})
+			return nil
+		}
+		ihV2, err := decodeHex(r.InfoHashV2)
+		if err != nil {
+			failedHex = append(failedHex, &pb.ImportError{Row: uint32(row), Error: "invalid info_hash_v2"})
+			return nil
+		}
+		p := &pb.TorrentImportParams{Row: uint32(row), Torrent: &pb.Torrent{
+			InfoHash:   ih,
+			InfoHashV2: ihV2,
+			Title:      r.Title,
+			IsEnabled:  r.IsEnabled,
+			Reason:     r.Reason,
+			MultiUp:    r.MultiUp,
+			MultiDn:    r.MultiDn,
+			Snatches:   r.Snatches,
+			Uploaded:   r.Uploaded,
+			Downloaded: r.Downloaded,
+			Announces:  r.Announces,
+		}}
+		if first {
+			p.Options = opts
+			first = false
+		}
+		return stream.Send(p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	failed = append(failed, failedHex...)
+	if first {
+		_ = stream.CloseSend()
+		return mergeImportErrors(&pb.ImportResponse{DryRun: opts.DryRun}, failed), nil
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, err
+	}
+	return mergeImportErrors(resp, failed), nil
+}
+
+// importRoles adds the roles one at a time as there are normally only a handful of them
+func importRoles(reader recordReader, opts *pb.ImportOptions) (*pb.ImportResponse, error) {
+	ctx := context.Background()
+	rc, err := cl.RoleAll(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get roles list")
+	}
+	known := make(map[string]*pb.Role)
+	for {
+		in, err := rc.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to get roles list")
+		}
+		known[strings.ToLower(in.RoleName)] = in
+	}
+	resp := &pb.ImportResponse{DryRun: opts.DryRun}
+	failed, err := readRecords(reader, newRoleRecord, func(row int, rec interface{}) error {
+		r := rec.(*roleRecord)
+		fail := func(msg string) {
+			resp.Errors = append(resp.Errors, &pb.ImportError{Row: uint32(row), Error: msg})
+		}
+		if r.RoleName == "" {
+			fail("role_name required")
+			return nil
+		}
+		existing, found := known[strings.ToLower(r.RoleName)]
+		if found && !opts.Upsert {
+			fail("role_name already exists")
+			return nil
+		}
+		if opts.DryRun {
+			if found {
+				resp.Updated++
+			} else {
+				resp.Created++
+			}
+			return nil
+		}
+		if found {
+			_, err := cl.RoleSave(ctx, &pb.Role{
+				RoleId:          existing.RoleId,
+				RoleName:        existing.RoleName,
+				RemoteId:        r.RemoteID,
+				Priority:        r.Priority,
+				DownloadEnabled: r.DownloadEnabled,
+				UploadEnabled:   r.UploadEnabled,
+				MultiUp:         r.MultiUp,
+				MultiDown:       r.MultiDown,
+				MaxPeers:        r.MaxPeers,
+			})
+			if err != nil {
+				fail(err.Error())
+				return nil
+			}
+			resp.Updated++
+			return nil
+		}
+		role, err := cl.RoleAdd(ctx, &pb.RoleAddParams{
+			RoleName:        r.RoleName,
+			RemoteId:        r.RemoteID,
+			Priority:        r.Priority,
+			DownloadEnabled: r.DownloadEnabled,
+			UploadEnabled:   r.UploadEnabled,
+			MultiUp:         r.MultiUp,
+			MultiDown:       r.MultiDown,
+			MaxPeers:        r.MaxPeers,
+		})
+		if err != nil {
+			fail(err.Error())
+			return nil
+		}
+		known[strings.ToLower(role.RoleName)] = role
+		resp.Created++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mergeImportErrors(resp, failed), nil
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().StringVarP(&importFormat, "format", "f", "",
+		"File format, csv or jsonl (default: from file extension, otherwise jsonl)")
+	importCmd.Flags().BoolVarP(&importOptions.Upsert, "upsert", "u", false,
+		"Update existing entries instead of reporting them as errors")
+	importCmd.Flags().BoolVarP(&importOptions.DryRun, "dry-run", "n", false,
+		"Validate every row without making any changes")
+}
@@ -12,6 +12,7 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 	"io"
 	"os"
+	"strconv"
 )
 
 var (
@@ -26,7 +27,10 @@ var (
 		UploadEnabled:   false,
 		MultiUp:         0,
 		MultiDown:       0,
+		MaxPeers:        0,
 	}
+	// roleReassignStr is either the role_id or role name users of a deleted role are moved to
+	roleReassignStr = ""
 )
 
 func defaultTable(title string) table.Writer {
@@ -41,10 +45,10 @@ func defaultTable(title string) table.Writer {
 
 func renderRoles(roles []*store.Role, title string) {
 	t := defaultTable(title)
-	t.AppendHeader(table.Row{"role_id", "name", "priority", "xup", "xdn", "dl_enabled"})
+	t.AppendHeader(table.Row{"role_id", "name", "priority", "xup", "xdn", "dl_enabled", "max_peers"})
 	for _, role := range roles {
 		t.AppendRow(table.Row{role.RoleID, role.RoleName, role.Priority, role.MultiDown,
-			role.MultiUp, role.DownloadEnabled})
+			role.MultiUp, role.DownloadEnabled, role.MaxPeers})
 	}
 	t.SortBy([]table.SortBy{{
 		Name: "priority",
@@ -120,28 +124,37 @@ var roleListCmd = &cobra.Command{
 var roleDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete a role from the tracker",
-	Long:  `Delete a role from the tracker`,
+	Long: `Delete a role from the tracker. If any users are still assigned the role, a role to
+reassign them to must be provided with --reassign-to`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if roleDelParam.RoleId <= 0 && roleDelParam.RoleName == "" {
 			log.Fatalf("Must supply one of: role name, role id")
 			return
 		}
-		rid := &pb.RoleID{}
+		params := &pb.RoleDeleteParams{Role: &pb.RoleID{}}
 		if roleDelParam.RoleName != "" {
-			rid.RoleName = roleDelParam.RoleName
+			params.Role.RoleName = roleDelParam.RoleName
 		} else {
-			rid.RoleId = roleDelParam.RoleId
+			params.Role.RoleId = roleDelParam.RoleId
+		}
+		if roleReassignStr != "" {
+			params.ReassignTo = &pb.RoleID{}
+			if rid, err := strconv.ParseUint(roleReassignStr, 10, 32); err == nil {
+				params.ReassignTo.RoleId = uint32(rid)
+			} else {
+				params.ReassignTo.RoleName = roleReassignStr
+			}
 		}
 		client, err := client.New()
 		if err != nil {
 			log.Fatalf("Failed to connect to tracker")
 			return
 		}
-		_, err2 := client.RoleDelete(context.Background(), rid)
+		resp, err2 := client.RoleDelete(context.Background(), params)
 		if err2 != nil {
-			log.Fatalf("Failed to add new role: %v", err2)
+			log.Fatalf("Failed to delete role: %v", err2)
 		}
-		log.Infof("Role deleted successfully")
+		log.Infof("Role deleted successfully (users moved: %d)", resp.UsersMoved)
 	},
 }
 
@@ -178,9 +191,13 @@ func init() {
 	roleSetCmd.Flags().BoolVarP(&roleSetParams.UploadEnabled, "upload_enabled", "U", true, "Uploading enabled")
 	roleSetCmd.Flags().Float64VarP(&roleSetParams.MultiDown, "multi_down", "d", 1.0, "Download multiplier")
 	roleSetCmd.Flags().Float64VarP(&roleSetParams.MultiUp, "multi_up", "u", 1.0, "Upload multiplier")
+	roleSetCmd.Flags().Int32VarP(&roleSetParams.MaxPeers, "max_peers", "m", 0,
+		"Maximum peers sent to users of the role, 0 uses the tracker max_peers")
 
 	roleDeleteCmd.Flags().StringVarP(&roleDelParam.RoleName, "name", "n", "", "Name of the role")
 	roleDeleteCmd.Flags().Uint32VarP(&roleDelParam.RoleId, "id", "i", 0, "Role ID")
+	roleDeleteCmd.Flags().StringVarP(&roleReassignStr, "reassign-to", "r", "",
+		"Role ID or name to move users of the deleted role to")
 
 	roleAddCmd.Flags().StringVarP(&roleAddParam.RoleName, "name", "n", "", "Name of the role")
 	roleAddCmd.Flags().Int32VarP(&roleAddParam.Priority, "priority", "p", 0, "Role Priority")
@@ -188,4 +205,6 @@ func init() {
 	roleAddCmd.Flags().BoolVarP(&roleAddParam.UploadEnabled, "upload_enabled", "U", true, "Uploading enabled")
 	roleAddCmd.Flags().Float64VarP(&roleAddParam.MultiDown, "multi_down", "d", 1.0, "Download multiplier")
 	roleAddCmd.Flags().Float64VarP(&roleAddParam.MultiUp, "multi_up", "u", 1.0, "Upload multiplier")
+	roleAddCmd.Flags().Int32VarP(&roleAddParam.MaxPeers, "max_peers", "m", 0,
+		"Maximum peers sent to users of the role, 0 uses the tracker max_peers")
 }
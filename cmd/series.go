@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"github.com/jedib0t/go-pretty/v6/table"
+	pb "github.com/leighmacdonald/mika/proto"
+	"github.com/pkg/errors"
+	"strings"
+	"time"
+)
+
+var (
+	// seriesResolution is the name of the bucket width shown by the series commands
+	seriesResolution = "hour"
+	seriesLimit      uint32
+)
+
+func parseResolution(name string) (pb.SeriesResolution, error) {
+	res, found := pb.SeriesResolution_value[strings.ToUpper(name)]
+	if !found {
+		return 0, errors.Errorf("unknown resolution: %s (valid: minute, hour, day)", name)
+	}
+	return pb.SeriesResolution(res), nil
+}
+
+func renderSeries(series *pb.Series, title string) {
+	t := defaultTable(title)
+	t.AppendHeader(table.Row{"time", "announces", "snatches", "uploaded", "downloaded", "seeders", "leechers"})
+	for _, p := range series.Points {
+		t.AppendRow(table.Row{p.Time.AsTime().Format(time.RFC3339), p.Announces, p.Snatches, p.Uploaded,
+			p.Downloaded, p.Seeders, p.Leechers})
+	}
+	t.Render()
+}
@@ -3,14 +3,17 @@ package cmd
 import (
 	"context"
 	"github.com/leighmacdonald/mika/config"
+	"github.com/leighmacdonald/mika/metrics"
 	pb "github.com/leighmacdonald/mika/proto"
 	"github.com/leighmacdonald/mika/rpc"
 	"github.com/leighmacdonald/mika/tracker"
 	"github.com/leighmacdonald/mika/util"
+	"github.com/leighmacdonald/mika/webhook"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 	"net"
+	"net/http"
 )
 
 // serveCmd represents the serve command
@@ -30,6 +33,21 @@ var serveCmd = &cobra.Command{
 
 		go tracker.PeerReaper(ctx)
 		go tracker.StatWorker(ctx)
+		go tracker.ConnectableWorker(ctx)
+		if config.Webhooks.Enabled {
+			dispatcher, errWh := webhook.New(config.Webhooks)
+			if errWh != nil {
+				log.Fatalf("Failed to setup webhooks: %v", errWh)
+			}
+			go dispatcher.Start(ctx)
+		}
+		if len(config.Metrics.Exporters) > 0 {
+			exporters, errExp := metrics.NewExporters(config.Metrics)
+			if errExp != nil {
+				log.Fatalf("Failed to setup metrics exporters: %v", errExp)
+			}
+			go metrics.Push(ctx, config.Metrics.IntervalParsed, exporters)
+		}
 
 		lis, err := net.Listen("tcp", config.API.Listen)
 		if err != nil {
@@ -49,12 +67,15 @@ var serveCmd = &cobra.Command{
 		//	}
 		//	opts = []grpc.ServerOption{grpc.Creds(creds)}
 		//}
-		grpcServer := grpc.NewServer(rpcOpts...)
-		pb.RegisterMikaServer(grpcServer, &rpc.MikaService{})
+		svc := &rpc.MikaService{}
+		keys := rpc.NewAPIKeys(config.API.Key, config.API.Keys)
+		grpcServer := grpc.NewServer(append(rpcOpts, keys.ServerOptions()...)...)
+		pb.RegisterMikaServer(grpcServer, svc)
+		apiServer := &http.Server{Handler: rpc.NewAPIHandler(grpcServer, rpc.NewGateway(svc, keys))}
 		go func() {
-			log.Infof("Starting gRPC service")
-			if errRpc := grpcServer.Serve(lis); errRpc != nil {
-				log.Errorf("gRPC error: %v", errRpc)
+			log.Infof("Starting gRPC and REST API service")
+			if errRpc := apiServer.Serve(lis); errRpc != nil && errRpc != http.ErrServerClosed {
+				log.Errorf("API error: %v", errRpc)
 			}
 		}()
 
@@ -69,6 +90,9 @@ var serveCmd = &cobra.Command{
 			if err := btServer.Shutdown(ctx); err != nil {
 				log.Fatalf("Error closing servers gracefully; %s", err)
 			}
+			if err := apiServer.Shutdown(ctx); err != nil {
+				log.Fatalf("Error closing servers gracefully; %s", err)
+			}
 			return nil
 		})
 	},
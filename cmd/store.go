@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/leighmacdonald/mika/config"
+	"github.com/leighmacdonald/mika/store"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	storeCopyFrom   string
+	storeCopyTo     string
+	storeCopyVerify bool
+)
+
+// storeCmd groups the commands operating directly on the backing stores
+var storeCmd = &cobra.Command{
+	Use:   "store",
+	Short: "store commands",
+	Long:  `store commands`,
+}
+
+func openStore(path string) store.Store {
+	sc, err := config.ReadStoreConfig(path)
+	if err != nil {
+		log.Fatalf("Failed to read store config: %v", err)
+	}
+	s, err := store.NewStore(sc)
+	if err != nil {
+		log.Fatalf("Failed to open %s store: %v", sc.Type, err)
+	}
+	return s
+}
+
+func renderVerify(results []store.TableVerify) bool {
+	ok := true
+	t := defaultTable("Verification")
+	t.AppendHeader(table.Row{"table", "src_rows", "dst_rows", "src_sum", "dst_sum", "ok"})
+	for _, r := range results {
+		t.AppendRow(table.Row{r.Table, r.SrcCount, r.DstCount, r.SrcSum[:12], r.DstSum[:12], r.OK()})
+		ok = ok && r.OK()
+	}
+	t.Render()
+	return ok
+}
+
+// storeCopyCmd copies all the data from one store to another
+var storeCopyCmd = &cobra.Command{
+	Use:   "copy",
+	Short: "Copy all data from one store to another",
+	Long: `Copy the roles, users, torrents, whitelist, time series and audit log from one store to another, eg: from
+redis to mysql.
+
+The --from and --to arguments are paths to config files, either full mika configs or files containing just
+the store settings. The destination schema is migrated and it must not contain any roles, users or torrents.
+New role and user IDs are assigned by the destination. The tracker should be stopped while copying.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if storeCopyFrom == "" || storeCopyTo == "" {
+			log.Fatalf("Both --from and --to are required")
+			return
+		}
+		src := openStore(storeCopyFrom)
+		defer func() { _ = src.Close() }()
+		dst := openStore(storeCopyTo)
+		defer func() { _ = dst.Close() }()
+		log.Infof("Copying %s store to %s store", src.Name(), dst.Name())
+		res, err := store.Copy(src, dst, func(p store.CopyProgress) {
+			log.Infof("Copied %s: %d/%d", p.Table, p.Done, p.Total)
+		})
+		if err != nil {
+			log.Fatalf("Failed to copy store: %v", err)
+			return
+		}
+		for _, name := range store.Tables {
+			log.Infof("Copied %d %s", res.Counts[name], name)
+		}
+		if !storeCopyVerify {
+			return
+		}
+		results, err := store.Verify(src, dst)
+		if err != nil {
+			log.Fatalf("Failed to verify store copy: %v", err)
+			return
+		}
+		if !renderVerify(results) {
+			log.Fatalf("Verification failed, the destination does not match the source")
+			return
+		}
+		fmt.Println("Verification successful")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(storeCmd)
+	storeCmd.AddCommand(storeCopyCmd)
+	storeCopyCmd.Flags().StringVar(&storeCopyFrom, "from", "", "Config file of the source store")
+	storeCopyCmd.Flags().StringVar(&storeCopyTo, "to", "", "Config file of the destination store")
+	storeCopyCmd.Flags().BoolVar(&storeCopyVerify, "verify", true,
+		"Compare row counts and checksums of the source and destination after copying")
+}
@@ -1,32 +1,455 @@
 package cmd
 
 import (
+	"context"
+	"encoding/hex"
+	"fmt"
 	ui "github.com/gizak/termui/v3"
 	"github.com/gizak/termui/v3/widgets"
+	pb "github.com/leighmacdonald/mika/proto"
+	"github.com/leighmacdonald/mika/rpc"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"io"
+	"sort"
+	"sync"
+	"time"
 )
 
+const (
+	// topHistory is the number of metric samples kept for the sparklines
+	topHistory = 300
+	// topMaxEvents is the number of recent events kept
+	topMaxEvents = 200
+	// topMaxSwarms is the number of swarms shown
+	topMaxSwarms = 100
+	// topSwarmRefresh is the number of metric refreshes between fetching the swarms, which
+	// requires streaming every torrent
+	topSwarmRefresh = 5
+)
+
+var topInterval time.Duration
+
+var topStatusLabels = []string{"ok", "unauth", "bad_ih", "malformed", "throttled", "error", "spoofed"}
+
+// dashboard holds the widgets and the state shown by mika top
+type dashboard struct {
+	interval time.Duration
+	grid     *ui.Grid
+
+	annRate  *widgets.Sparkline
+	annGroup *widgets.SparklineGroup
+	latency  *widgets.Sparkline
+	latGroup *widgets.SparklineGroup
+	cache    *widgets.Paragraph
+	status   *widgets.BarChart
+	clients  *widgets.List
+	swarms   *widgets.List
+	events   *widgets.List
+	help     *widgets.Paragraph
+
+	// panes are the widgets which can be focused and scrolled
+	panes []*widgets.List
+	focus int
+
+	// first and prev are the first and previous samples, the counters are totals since the
+	// tracker started so the rates and session totals are the differences between samples
+	first      *pb.RuntimeMetrics
+	prev       *pb.RuntimeMetrics
+	lastSample time.Time
+	lastUpdate time.Time
+	lastErr    error
+
+	eventsMu  *sync.Mutex
+	eventRows []string
+}
+
+func newDashboard(interval time.Duration) *dashboard {
+	d := &dashboard{
+		interval: interval,
+		eventsMu: &sync.Mutex{},
+	}
+	d.annRate = widgets.NewSparkline()
+	d.annRate.LineColor = ui.ColorGreen
+	d.annGroup = widgets.NewSparklineGroup(d.annRate)
+	d.annGroup.Title = "Announces/sec"
+
+	d.latency = widgets.NewSparkline()
+	d.latency.Title = "p95"
+	d.latency.LineColor = ui.ColorMagenta
+	d.latGroup = widgets.NewSparklineGroup(d.latency)
+	d.latGroup.Title = "Announce latency"
+
+	d.cache = widgets.NewParagraph()
+	d.cache.Title = "Cache"
+
+	d.status = widgets.NewBarChart()
+	d.status.Title = "Announce status (session)"
+	d.status.Labels = topStatusLabels
+	d.status.BarWidth = 9
+	d.status.BarColors = []ui.Color{ui.ColorGreen, ui.ColorRed, ui.ColorRed, ui.ColorRed, ui.ColorYellow, ui.ColorRed,
+		ui.ColorMagenta}
+	d.status.NumFormatter = func(v float64) string { return fmt.Sprintf("%.0f", v) }
+
+	d.clients = widgets.NewList()
+	d.clients.Title = "Clients (session)"
+	d.swarms = widgets.NewList()
+	d.swarms.Title = "Busiest swarms: info_hash seeders leechers snatches title"
+	d.events = widgets.NewList()
+	d.events.Title = "Recent events"
+	d.panes = []*widgets.List{d.swarms, d.events, d.clients}
+	d.setFocus(0)
+
+	d.help = widgets.NewParagraph()
+	d.help.Border = false
+
+	d.grid = ui.NewGrid()
+	d.grid.Set(
+		ui.NewRow(0.25,
+			ui.NewCol(0.4, d.annGroup),
+			ui.NewCol(0.3, d.latGroup),
+			ui.NewCol(0.3, d.cache),
+		),
+		ui.NewRow(0.25,
+			ui.NewCol(0.6, d.status),
+			ui.NewCol(0.4, d.clients),
+		),
+		ui.NewRow(0.45,
+			ui.NewCol(0.6, d.swarms),
+			ui.NewCol(0.4, d.events),
+		),
+		ui.NewRow(0.05, d.help),
+	)
+	return d
+}
+
+func (d *dashboard) resize(width int, height int) {
+	d.grid.SetRect(0, 0, width, height)
+}
+
+// setFocus highlights the pane which receives the scroll keys
+func (d *dashboard) setFocus(idx int) {
+	d.focus = (idx + len(d.panes)) % len(d.panes)
+	for i, p := range d.panes {
+		if i == d.focus {
+			p.BorderStyle = ui.NewStyle(ui.ColorYellow)
+			p.SelectedRowStyle = ui.NewStyle(ui.ColorBlack, ui.ColorYellow)
+		} else {
+			p.BorderStyle = ui.NewStyle(ui.ColorWhite)
+			p.SelectedRowStyle = p.TextStyle
+		}
+	}
+}
+
+// handleKey applies a keyboard event, returning false when the dashboard should exit
+func (d *dashboard) handleKey(id string) bool {
+	pane := d.panes[d.focus]
+	switch id {
+	case "q", "<C-c>", "<Escape>":
+		return false
+	case "<Tab>", "<Right>", "l":
+		d.setFocus(d.focus + 1)
+	case "<Left>", "h":
+		d.setFocus(d.focus - 1)
+	case "<Down>", "j":
+		if len(pane.Rows) > 0 {
+			pane.ScrollDown()
+		}
+	case "<Up>", "k":
+		if len(pane.Rows) > 0 {
+			pane.ScrollUp()
+		}
+	case "<PageDown>", "<C-d>":
+		if len(pane.Rows) > 0 {
+			pane.ScrollPageDown()
+		}
+	case "<PageUp>", "<C-u>":
+		if len(pane.Rows) > 0 {
+			pane.ScrollPageUp()
+		}
+	case "<Home>", "g":
+		pane.ScrollTop()
+	case "<End>", "G":
+		if len(pane.Rows) > 0 {
+			pane.ScrollBottom()
+		}
+	}
+	return true
+}
+
+func appendSample(data []float64, v float64) []float64 {
+	data = append(data, v)
+	if len(data) > topHistory {
+		data = data[len(data)-topHistory:]
+	}
+	return data
+}
+
+func statusCounts(m *pb.RuntimeMetrics) []int64 {
+	return []int64{m.AnnounceStatusOk, m.AnnounceStatusUnauthorized, m.AnnounceStatusInvalidInfoHash,
+		m.AnnounceStatusMalformed, m.AnnounceStatusThrottled, m.AnnounceStatusError, m.AnnounceClientSpoofed}
+}
+
+// latencyPercentiles returns the p50, p95 and p99 announce latency, in milliseconds, of the
+// announces made between the samples
+func latencyPercentiles(m *pb.RuntimeMetrics, prev *pb.RuntimeMetrics) (p50 float64, p95 float64, p99 float64) {
+	h := rpc.PBToHistogram(m.AnnounceLatency).Sub(rpc.PBToHistogram(prev.AnnounceLatency))
+	return h.Quantile(0.5) * 1000, h.Quantile(0.95) * 1000, h.Quantile(0.99) * 1000
+}
+
+// updateMetrics adds a new sample taken elapsed after the previous sample
+func (d *dashboard) updateMetrics(m *pb.RuntimeMetrics, elapsed time.Duration) {
+	if d.prev != nil && m.AnnounceTotal < d.prev.AnnounceTotal {
+		// The tracker was restarted
+		d.first, d.prev = nil, nil
+	}
+	if d.first == nil {
+		d.first = m
+	}
+	if prev := d.prev; prev != nil && elapsed > 0 {
+		d.annRate.Data = appendSample(d.annRate.Data, float64(m.AnnounceTotal-prev.AnnounceTotal)/elapsed.Seconds())
+		p50, p95, p99 := latencyPercentiles(m, prev)
+		d.latency.Data = appendSample(d.latency.Data, p95)
+		d.latGroup.Title = fmt.Sprintf("Latency p50 %.2f p95 %.2f p99 %.2f ms", p50, p95, p99)
+	}
+	d.prev = m
+	if n := len(d.annRate.Data); n > 0 {
+		d.annGroup.Title = fmt.Sprintf("Announces/sec: %.1f", d.annRate.Data[n-1])
+	}
+	current, initial := statusCounts(m), statusCounts(d.first)
+	status := make([]float64, len(current))
+	for i := range current {
+		status[i] = float64(current[i] - initial[i])
+	}
+	d.status.Data = status
+	d.cache.Text = fmt.Sprintf("Torrents:   %d\nUsers:      %d\nPeers:      %d\n"+
+		"Goroutines: %d\nHeap:       %.1f MB\nGC:         %d (%.2f%% cpu)\nDropped:    %d events",
+		m.TorrentsTotalCached, m.UsersTotalCached, m.PeersTotalCached, m.GoRoutines,
+		float64(m.AllocHeap)/1024/1024, m.NumGc, m.GcCpuFraction*100, m.EventsDropped)
+
+	type clientCount struct {
+		name  string
+		count int64
+	}
+	var clients []clientCount
+	for name, count := range m.AnnounceClients {
+		if c := count - d.first.AnnounceClients[name]; c > 0 {
+			clients = append(clients, clientCount{name, c})
+		}
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		if clients[i].count == clients[j].count {
+			return clients[i].name < clients[j].name
+		}
+		return clients[i].count > clients[j].count
+	})
+	rows := make([]string, len(clients))
+	for i, c := range clients {
+		rows[i] = fmt.Sprintf("%-24s %d", c.name, c.count)
+	}
+	d.clients.Rows = rows
+}
+
+// updateSwarms shows the torrents with the most peers
+func (d *dashboard) updateSwarms(torrents []*pb.Torrent) {
+	sort.Slice(torrents, func(i, j int) bool {
+		pi := torrents[i].Seeders + torrents[i].Leechers
+		pj := torrents[j].Seeders + torrents[j].Leechers
+		if pi == pj {
+			return torrents[i].Snatches > torrents[j].Snatches
+		}
+		return pi > pj
+	})
+	if len(torrents) > topMaxSwarms {
+		torrents = torrents[:topMaxSwarms]
+	}
+	rows := make([]string, len(torrents))
+	for i, t := range torrents {
+		rows[i] = fmt.Sprintf("%s %6d %6d %7d %s", hex.EncodeToString(t.InfoHash),
+			t.Seeders, t.Leechers, t.Snatches, t.Title)
+	}
+	d.swarms.Rows = rows
+	if d.swarms.SelectedRow >= len(rows) {
+		d.swarms.SelectedRow = 0
+	}
+}
+
+// addEvent is called from the subscription goroutine
+func (d *dashboard) addEvent(row string) {
+	d.eventsMu.Lock()
+	d.eventRows = append([]string{row}, d.eventRows...)
+	if len(d.eventRows) > topMaxEvents {
+		d.eventRows = d.eventRows[:topMaxEvents]
+	}
+	d.eventsMu.Unlock()
+}
+
+func formatEvent(e *pb.Event) string {
+	row := fmt.Sprintf("%s %-16s", e.Time.AsTime().Local().Format("15:04:05"), e.Type.String())
+	if len(e.InfoHash) > 0 {
+		row += " " + hex.EncodeToString(e.InfoHash)[:12]
+	}
+	if e.UserId > 0 {
+		row += fmt.Sprintf(" user:%d", e.UserId)
+	}
+	if e.Reason != "" {
+		row += " " + e.Reason
+	}
+	if e.Dropped > 0 {
+		row += fmt.Sprintf(" (%d dropped)", e.Dropped)
+	}
+	return row
+}
+
+// subscribe feeds the events pane until the context is cancelled or the stream fails
+func (d *dashboard) subscribe(ctx context.Context) {
+	stream, err := cl.Subscribe(ctx, &pb.EventFilter{})
+	if err != nil {
+		d.addEvent(fmt.Sprintf("Failed to subscribe to events: %v", err))
+		return
+	}
+	for {
+		e, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() == nil {
+				d.addEvent(fmt.Sprintf("Event stream closed: %v", err))
+			}
+			return
+		}
+		d.addEvent(formatEvent(e))
+	}
+}
+
+func fetchTorrents(ctx context.Context) ([]*pb.Torrent, error) {
+	stream, err := cl.TorrentAll(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	var torrents []*pb.Torrent
+	for {
+		t, err := stream.Recv()
+		if err == io.EOF {
+			return torrents, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !t.IsDeleted {
+			torrents = append(torrents, t)
+		}
+	}
+}
+
+// refresh fetches the latest metrics and, if requested, the swarms
+func (d *dashboard) refresh(ctx context.Context, swarms bool) {
+	cctx, cancel := context.WithTimeout(ctx, d.interval)
+	defer cancel()
+	m, err := cl.Metrics(cctx, &emptypb.Empty{})
+	if err != nil {
+		d.lastErr = err
+		return
+	}
+	now := time.Now()
+	d.updateMetrics(m, now.Sub(d.lastSample))
+	d.lastSample = now
+	if swarms {
+		torrents, err := fetchTorrents(cctx)
+		if err != nil {
+			d.lastErr = err
+			return
+		}
+		d.updateSwarms(torrents)
+	}
+	d.lastErr = nil
+	d.lastUpdate = time.Now()
+}
+
+func (d *dashboard) render() {
+	// Only show as much history as fits in each sparkline
+	for _, g := range []*widgets.SparklineGroup{d.annGroup, d.latGroup} {
+		sl := g.Sparklines[0]
+		if w := g.Inner.Dx(); w > 0 && len(sl.Data) > w {
+			sl.Data = sl.Data[len(sl.Data)-w:]
+		}
+		sl.MaxVal = 1
+		for _, v := range sl.Data {
+			if v > sl.MaxVal {
+				sl.MaxVal = v
+			}
+		}
+	}
+	d.status.MaxVal = 1
+	for _, v := range d.status.Data {
+		if v > d.status.MaxVal {
+			d.status.MaxVal = v
+		}
+	}
+	d.eventsMu.Lock()
+	d.events.Rows = append(d.events.Rows[:0], d.eventRows...)
+	d.eventsMu.Unlock()
+
+	status := "waiting for metrics"
+	if d.lastErr != nil {
+		status = fmt.Sprintf("[error: %v](fg:red)", d.lastErr)
+	} else if !d.lastUpdate.IsZero() {
+		status = "updated " + d.lastUpdate.Format("15:04:05")
+	}
+	d.help.Text = fmt.Sprintf("tab/←/→: switch pane  ↑/↓/pgup/pgdn: scroll  q: quit  | %s", status)
+	ui.Render(d.grid)
+}
+
 // topCmd rune a top like status display of the running tracker
 var topCmd = &cobra.Command{
 	Use:   "top",
 	Short: "A top like status display of the running tracker",
-	Long:  `A top like status display of the running tracker`,
+	Long: `A top like status display of the running tracker showing announce rates, status codes and latency,
+the cache sizes, the busiest swarms and recent events.
+
+Use tab or the left/right arrows to switch between the swarms, events and clients panes and the up/down
+arrows to scroll the selected pane. The status and client counts are totals since top was started.`,
+	PersistentPreRunE: connectRPC,
 	Run: func(cmd *cobra.Command, args []string) {
+		if topInterval < time.Second {
+			log.Fatalf("Interval must be at least 1s")
+			return
+		}
 		if err := ui.Init(); err != nil {
 			log.Fatalf("failed to initialize termui: %v", err)
 		}
 		defer ui.Close()
 
-		p := widgets.NewParagraph()
-		p.Text = "Hello World!"
-		p.SetRect(0, 0, 25, 5)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
 
-		ui.Render(p)
+		d := newDashboard(topInterval)
+		d.resize(ui.TerminalDimensions())
+		go d.subscribe(ctx)
+		d.refresh(ctx, true)
+		d.render()
 
-		for e := range ui.PollEvents() {
-			if e.Type == ui.KeyboardEvent {
-				break
+		ticker := time.NewTicker(topInterval)
+		defer ticker.Stop()
+		uiEvents := ui.PollEvents()
+		ticks := 0
+		for {
+			select {
+			case e := <-uiEvents:
+				switch e.Type {
+				case ui.ResizeEvent:
+					payload := e.Payload.(ui.Resize)
+					d.resize(payload.Width, payload.Height)
+					ui.Clear()
+				case ui.KeyboardEvent:
+					if !d.handleKey(e.ID) {
+						return
+					}
+				}
+				d.render()
+			case <-ticker.C:
+				ticks++
+				d.refresh(ctx, ticks%topSwarmRefresh == 0)
+				d.render()
 			}
 		}
 	},
@@ -34,4 +457,5 @@ var topCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(topCmd)
+	topCmd.Flags().DurationVarP(&topInterval, "interval", "i", 2*time.Second, "Refresh interval")
 }
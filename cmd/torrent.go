@@ -2,6 +2,9 @@ package cmd
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"github.com/anacrolix/torrent/bencode"
 	"github.com/anacrolix/torrent/metainfo"
 	"github.com/jedib0t/go-pretty/v6/table"
@@ -15,7 +18,8 @@ import (
 	"github.com/spf13/cobra"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"io"
-	"io/ioutil"
+	"net"
+	"strconv"
 )
 
 var (
@@ -36,15 +40,70 @@ func connectRPC(cmd *cobra.Command, args []string) error {
 
 func renderTorrents(torrents []*store.Torrent, title string) {
 	t := defaultTable(title)
-	t.AppendHeader(table.Row{"info_hash", "sn", "up_tot", "dn_tot", "en", "reason", "x_up", "x_dn"})
+	t.AppendHeader(table.Row{"info_hash", "info_hash_v2", "sn", "up_tot", "dn_tot", "en", "reason", "x_up", "x_dn"})
 	for _, tor := range torrents {
+		ihV2 := ""
+		if !tor.InfoHashV2.IsZero() {
+			ihV2 = tor.InfoHashV2.String()
+		}
 		t.AppendRow(table.Row{
-			tor.InfoHash, tor.Snatches, tor.Uploaded, tor.Downloaded,
+			tor.InfoHash, ihV2, tor.Snatches, tor.Uploaded, tor.Downloaded,
 			tor.IsEnabled, tor.Reason, tor.MultiUp, tor.MultiDn})
 	}
 	t.Render()
 }
 
+// peerInfo pairs a peer with the infohash of the swarm it belongs to
+type peerInfo struct {
+	infoHash store.InfoHash
+	peer     *store.Peer
+}
+
+// recvPeers reads the peer stream until completion
+func recvPeers(recv func() (*pb.Peer, error)) ([]peerInfo, error) {
+	var peers []peerInfo
+	for {
+		in, err := recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		ih, peer := rpc.PBToPeer(in)
+		peers = append(peers, peerInfo{infoHash: ih, peer: peer})
+	}
+	return peers, nil
+}
+
+func connectableString(state uint32) string {
+	switch state {
+	case store.ConnectableYes:
+		return "yes"
+	case store.ConnectableNo:
+		return "no"
+	default:
+		return "?"
+	}
+}
+
+func renderPeers(peers []peerInfo, title string) {
+	t := defaultTable(title)
+	t.AppendHeader(table.Row{"info_hash", "peer_id", "user_id", "addr", "client", "up_tot", "dn_tot", "left",
+		"speed_up", "speed_dn", "cc", "asn", "conn", "crypto", "spoofed", "announces", "announce_last"})
+	for _, p := range peers {
+		t.AppendRow(table.Row{
+			p.infoHash, p.peer.PeerID, p.peer.UserID, net.JoinHostPort(p.peer.IP.String(), strconv.Itoa(int(p.peer.Port))),
+			p.peer.Client, p.peer.Uploaded, p.peer.Downloaded, p.peer.Left, p.peer.SpeedUP, p.peer.SpeedDN,
+			p.peer.CountryCode, p.peer.ASN, connectableString(p.peer.Connectable), p.peer.CryptoLevel,
+			p.peer.Spoofed, p.peer.Announces, p.peer.AnnounceLast})
+	}
+	t.SortBy([]table.SortBy{{
+		Name: "user_id",
+	}})
+	t.Render()
+}
+
 // torrentCmd represents torrent admin commands
 var torrentCmd = &cobra.Command{
 	Use:               "torrent",
@@ -64,18 +123,19 @@ var torrentAddCmd = &cobra.Command{
 		if err != nil {
 			return errors.Wrapf(err, "Failed to read torrent meta info")
 		}
-		b, err := ioutil.ReadFile(torrentFile)
+		info, err := f.UnmarshalInfo()
 		if err != nil {
-			return err
-		}
-		var info metainfo.Info
-		if err := bencode.Unmarshal(b, &info); err != nil {
-			return err
+			return errors.Wrapf(err, "Failed to read torrent info dict")
 		}
 		if torrentAddParams.Title == "" {
 			torrentAddParams.Title = info.Name
 		}
-		torrentAddParams.InfoHash = f.HashInfoBytes().Bytes()
+		ih, ihV2, err := metaInfoHashes(f)
+		if err != nil {
+			return err
+		}
+		torrentAddParams.InfoHash = ih
+		torrentAddParams.InfoHashV2 = ihV2
 		r, err2 := cl.TorrentAdd(context.Background(), torrentAddParams)
 		if err2 != nil {
 			log.Fatalf("Failed to add new torrent: %v", err2)
@@ -86,6 +146,30 @@ var torrentAddCmd = &cobra.Command{
 	},
 }
 
+// metaInfoHashes returns the v1 and v2 infohashes of the torrent. v1 torrents have no v2 infohash and
+// v2 only torrents have no v1 infohash. Hybrid torrents have both.
+//
+// The v2 infohash is the SHA-256 of the info dict, which is only valid when the info dict
+// declares itself as "meta version" 2 as per BEP52.
+func metaInfoHashes(mi *metainfo.MetaInfo) ([]byte, []byte, error) {
+	var info struct {
+		MetaVersion int64  `bencode:"meta version"`
+		Pieces      []byte `bencode:"pieces"`
+	}
+	if err := bencode.Unmarshal(mi.InfoBytes, &info); err != nil {
+		return nil, nil, errors.Wrapf(err, "Failed to read torrent info dict")
+	}
+	if info.MetaVersion != 2 {
+		return mi.HashInfoBytes().Bytes(), nil, nil
+	}
+	ihV2 := sha256.Sum256(mi.InfoBytes)
+	if len(info.Pieces) == 0 {
+		// v2 only torrent
+		return nil, ihV2[:], nil
+	}
+	return mi.HashInfoBytes().Bytes(), ihV2[:], nil
+}
+
 // torrentGetCmd can be used to add torrents
 var torrentGetCmd = &cobra.Command{
 	Use:   "get",
@@ -167,8 +251,89 @@ var torrentDeleteCmd = &cobra.Command{
 	},
 }
 
+// torrentPeersCmd lists the peers in a torrents swarm
+var torrentPeersCmd = &cobra.Command{
+	Use:   "peers <info_hash>",
+	Short: "List the peers in a torrents swarm",
+	Long:  `List the peers in a torrents swarm`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var ih store.InfoHash
+		if err := store.InfoHashFromHex(&ih, args[0]); err != nil {
+			log.Fatalf("Invalid infohash: %v", err)
+			return
+		}
+		stream, err := cl.SwarmGet(context.Background(), &pb.InfoHashParam{InfoHash: ih.Bytes()})
+		if err != nil {
+			log.Fatalf("Failed to fetch peers: %v", err)
+			return
+		}
+		peers, err := recvPeers(stream.Recv)
+		if err != nil {
+			log.Fatalf("Failed to fetch peers: %v", err)
+			return
+		}
+		renderPeers(peers, fmt.Sprintf("Swarm of %s", ih.String()))
+	},
+}
+
+// torrentKickCmd removes a peer from a torrents swarm
+var torrentKickCmd = &cobra.Command{
+	Use:   "kick <info_hash> <peer_id>",
+	Short: "Remove a peer from a torrents swarm",
+	Long:  `Remove a peer from a torrents swarm. The peer_id is hex encoded as shown by the peers command.`,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		var ih store.InfoHash
+		if err := store.InfoHashFromHex(&ih, args[0]); err != nil {
+			log.Fatalf("Invalid infohash: %v", err)
+			return
+		}
+		peerID, err := hex.DecodeString(args[1])
+		if err != nil || len(peerID) != 20 {
+			log.Fatalf("Invalid peer_id")
+			return
+		}
+		if _, err := cl.PeerKick(context.Background(), &pb.PeerKickParams{InfoHash: ih.Bytes(), PeerId: peerID}); err != nil {
+			log.Fatalf("Failed to kick peer: %v", err)
+			return
+		}
+		log.Infof("Peer kicked successfully")
+	},
+}
+
+// torrentSeriesCmd shows the activity of a torrent over time
+var torrentSeriesCmd = &cobra.Command{
+	Use:   "series <info_hash>",
+	Short: "Show the activity of a torrent over time",
+	Long:  `Show the announces, snatches, transfer and swarm size of a torrent for each minute, hour or day`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var ih store.InfoHash
+		if err := store.InfoHashFromHex(&ih, args[0]); err != nil {
+			log.Fatalf("Invalid infohash: %v", err)
+			return
+		}
+		res, err := parseResolution(seriesResolution)
+		if err != nil {
+			log.Fatalf("Invalid resolution: %v", err)
+			return
+		}
+		series, err := cl.TorrentSeries(context.Background(), &pb.TorrentSeriesParams{
+			InfoHash: ih.Bytes(), Resolution: res, Limit: seriesLimit})
+		if err != nil {
+			log.Fatalf("Failed to fetch series: %v", err)
+			return
+		}
+		renderSeries(series, fmt.Sprintf("Activity of %s per %s", ih.String(), seriesResolution))
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(torrentCmd)
+	torrentCmd.AddCommand(torrentSeriesCmd)
+	torrentCmd.AddCommand(torrentPeersCmd)
+	torrentCmd.AddCommand(torrentKickCmd)
 	torrentCmd.AddCommand(torrentListCmd)
 	torrentCmd.AddCommand(torrentAddCmd)
 	torrentCmd.AddCommand(torrentGetCmd)
@@ -178,6 +343,9 @@ func init() {
 
 	torrentDeleteCmd.Flags().StringVarP(&torrentInfoHashParams.InfoHashHex, "infohash", "i", "", "infohash of the torrent")
 
+	torrentSeriesCmd.Flags().StringVarP(&seriesResolution, "resolution", "r", seriesResolution, "Bucket width: minute, hour or day")
+	torrentSeriesCmd.Flags().Uint32VarP(&seriesLimit, "limit", "l", 0, "Number of buckets to show (default: all)")
+
 	torrentAddCmd.Flags().StringVarP(&torrentFile, "file", "f", "", "Torrent file to add")
 	torrentAddCmd.Flags().StringVarP(&torrentAddParams.Title, "name", "n", "", "Name of the torrent")
 	torrentAddCmd.Flags().Float64VarP(&torrentAddParams.MultiUp, "multi_up", "U", 1.0, "Upload multiplier")
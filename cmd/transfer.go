@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Formats supported by the import and export commands
+const (
+	formatCSV   = "csv"
+	formatJSONL = "jsonl"
+)
+
+// userRecord is the import/export form of a user. Fields not present in the source are left at
+// their defaults.
+type userRecord struct {
+	UserID          uint32 `json:"user_id"`
+	RoleID          uint32 `json:"role_id"`
+	RemoteID        uint64 `json:"remote_id"`
+	UserName        string `json:"user_name"`
+	Passkey         string `json:"passkey"`
+	DownloadEnabled bool   `json:"download_enabled"`
+	Downloaded      uint64 `json:"downloaded"`
+	Uploaded        uint64 `json:"uploaded"`
+	Announces       uint32 `json:"announces"`
+}
+
+// torrentRecord is the import/export form of a torrent. The infohashes are hex encoded.
+type torrentRecord struct {
+	InfoHash   string  `json:"info_hash"`
+	InfoHashV2 string  `json:"info_hash_v2"`
+	Title      string  `json:"title"`
+	IsEnabled  bool    `json:"is_enabled"`
+	Reason     string  `json:"reason"`
+	MultiUp    float64 `json:"multi_up"`
+	MultiDn    float64 `json:"multi_dn"`
+	Snatches   uint32  `json:"snatches"`
+	Uploaded   uint64  `json:"uploaded"`
+	Downloaded uint64  `json:"downloaded"`
+	Announces  uint64  `json:"announces"`
+}
+
+// roleRecord is the import/export form of a role
+type roleRecord struct {
+	RoleID          uint32  `json:"role_id"`
+	RoleName        string  `json:"role_name"`
+	RemoteID        uint64  `json:"remote_id"`
+	Priority        int32   `json:"priority"`
+	DownloadEnabled bool    `json:"download_enabled"`
+	UploadEnabled   bool    `json:"upload_enabled"`
+	MultiUp         float64 `json:"multi_up"`
+	MultiDown       float64 `json:"multi_down"`
+	MaxPeers        int32   `json:"max_peers"`
+}
+
+func newUserRecord() interface{} {
+	return &userRecord{DownloadEnabled: true}
+}
+
+func newTorrentRecord() interface{} {
+	return &torrentRecord{IsEnabled: true, MultiUp: 1, MultiDn: 1}
+}
+
+func newRoleRecord() interface{} {
+	return &roleRecord{DownloadEnabled: true, UploadEnabled: true, MultiUp: 1, MultiDown: 1}
+}
+
+// decodeHex decodes an optional hex value, an empty string returns nil
+func decodeHex(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	return hex.DecodeString(s)
+}
+
+func encodeHex(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// detectFormat returns the format explicitly requested or guesses it from the file extension,
+// defaulting to jsonl
+func detectFormat(format string, path string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			format = formatCSV
+		default:
+			format = formatJSONL
+		}
+	}
+	switch format {
+	case formatCSV, formatJSONL:
+		return format, nil
+	default:
+		return "", errors.Errorf("Unsupported format: %s", format)
+	}
+}
+
+// recordFields returns the json tag names of the record struct fields which are also used as
+// the csv column names
+func recordFields(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		names = append(names, t.Field(i).Tag.Get("json"))
+	}
+	return names
+}
+
+// recordError is returned by a recordReader when a single record could not be decoded. The
+// remaining records can still be read.
+type recordError struct {
+	err error
+}
+
+func (e recordError) Error() string {
+	return e.err.Error()
+}
+
+// recordReader reads records from a csv or jsonl source
+type recordReader interface {
+	// Next decodes the next record into v and returns its row number, starting at 1 for the
+	// first record. io.EOF is returned once there are no more records.
+	Next(v interface{}) (int, error)
+}
+
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	row     int
+}
+
+func (r *jsonlReader) Next(v interface{}) (int, error) {
+	for r.scanner.Scan() {
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" {
+			continue
+		}
+		r.row++
+		if err := json.Unmarshal([]byte(line), v); err != nil {
+			return r.row, recordError{err}
+		}
+		return r.row, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return r.row, err
+	}
+	return r.row, io.EOF
+}
+
+type csvReader struct {
+	reader *csv.Reader
+	header []string
+	row    int
+}
+
+func (r *csvReader) Next(v interface{}) (int, error) {
+	if r.header == nil {
+		header, err := r.reader.Read()
+		if err != nil {
+			return 0, err
+		}
+		r.header = header
+	}
+	values, err := r.reader.Read()
+	if err != nil {
+		if _, ok := err.(*csv.ParseError); ok {
+			r.row++
+			return r.row, recordError{err}
+		}
+		return r.row, err
+	}
+	r.row++
+	rv := reflect.ValueOf(v).Elem()
+	fields := recordFields(rv.Type())
+	for i, column := range r.header {
+		idx := -1
+		for fi, name := range fields {
+			if name == strings.TrimSpace(column) {
+				idx = fi
+				break
+			}
+		}
+		if idx < 0 {
+			return r.row, errors.Errorf("Unknown column: %s", column)
+		}
+		if err := setRecordField(rv.Field(idx), values[i]); err != nil {
+			return r.row, recordError{errors.Wrapf(err, "Invalid value for %s", column)}
+		}
+	}
+	return r.row, nil
+}
+
+// setRecordField parses the csv value into the field. Empty values leave the field unchanged.
+func setRecordField(f reflect.Value, value string) error {
+	if value == "" {
+		return nil
+	}
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(value)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		f.SetBool(v)
+	case reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(value, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetInt(v)
+	case reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(value, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetUint(v)
+	case reflect.Float64:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		f.SetFloat(v)
+	default:
+		return errors.Errorf("Unsupported field type: %s", f.Kind())
+	}
+	return nil
+}
+
+func newRecordReader(r io.Reader, format string) recordReader {
+	if format == formatCSV {
+		cr := csv.NewReader(r)
+		cr.TrimLeadingSpace = true
+		return &csvReader{reader: cr}
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	return &jsonlReader{scanner: scanner}
+}
+
+// recordWriter writes records to a csv or jsonl destination
+type recordWriter interface {
+	Write(v interface{}) error
+	// Flush must be called once all records have been written
+	Flush() error
+}
+
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func (w *jsonlWriter) Write(v interface{}) error {
+	return w.enc.Encode(v)
+}
+
+func (w *jsonlWriter) Flush() error {
+	return nil
+}
+
+type csvWriter struct {
+	writer      *csv.Writer
+	wroteHeader bool
+}
+
+func (w *csvWriter) Write(v interface{}) error {
+	rv := reflect.ValueOf(v).Elem()
+	if !w.wroteHeader {
+		if err := w.writer.Write(recordFields(rv.Type())); err != nil {
+			return err
+		}
+		w.wroteHeader = true
+	}
+	values := make([]string, rv.NumField())
+	for i := 0; i < rv.NumField(); i++ {
+		f := rv.Field(i)
+		switch f.Kind() {
+		case reflect.Float64:
+			values[i] = strconv.FormatFloat(f.Float(), 'f', -1, 64)
+		default:
+			values[i] = fmt.Sprint(f.Interface())
+		}
+	}
+	return w.writer.Write(values)
+}
+
+func (w *csvWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func newRecordWriter(w io.Writer, format string) recordWriter {
+	if format == formatCSV {
+		return &csvWriter{writer: csv.NewWriter(w)}
+	}
+	return &jsonlWriter{enc: json.NewEncoder(w)}
+}
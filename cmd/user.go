@@ -120,10 +120,68 @@ var userAddCmd = &cobra.Command{
 	},
 }
 
+// userPeersCmd lists the active peers of a user across all swarms
+var userPeersCmd = &cobra.Command{
+	Use:   "peers <user_id>",
+	Short: "List the active peers of a user",
+	Long:  `List the active peers of a user across all swarms`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		userID, err := strconv.ParseUint(args[0], 10, 32)
+		if err != nil {
+			log.Fatalf("Invalid user_id: %v", err)
+			return
+		}
+		stream, err := cl.PeersByUser(context.Background(), &pb.UserID{UserId: uint32(userID)})
+		if err != nil {
+			log.Fatalf("Failed to fetch peers: %v", err)
+			return
+		}
+		peers, err := recvPeers(stream.Recv)
+		if err != nil {
+			log.Fatalf("Failed to fetch peers: %v", err)
+			return
+		}
+		renderPeers(peers, fmt.Sprintf("Peers of user %d", userID))
+	},
+}
+
+// userSeriesCmd shows the transfer of a user over time
+var userSeriesCmd = &cobra.Command{
+	Use:   "series <user_id>",
+	Short: "Show the transfer of a user over time",
+	Long:  `Show the announces, snatches and credited transfer of a user for each minute, hour or day`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		userID, err := strconv.ParseUint(args[0], 10, 32)
+		if err != nil {
+			log.Fatalf("Invalid user_id: %v", err)
+			return
+		}
+		res, err := parseResolution(seriesResolution)
+		if err != nil {
+			log.Fatalf("Invalid resolution: %v", err)
+			return
+		}
+		series, err := cl.UserSeries(context.Background(), &pb.UserSeriesParams{
+			UserId: uint32(userID), Resolution: res, Limit: seriesLimit})
+		if err != nil {
+			log.Fatalf("Failed to fetch series: %v", err)
+			return
+		}
+		renderSeries(series, fmt.Sprintf("Transfer of user %d per %s", userID, seriesResolution))
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(userCmd)
+	userCmd.AddCommand(userSeriesCmd)
 	userCmd.AddCommand(userAddCmd)
 	userCmd.AddCommand(userGetCmd)
+	userCmd.AddCommand(userPeersCmd)
+
+	userSeriesCmd.Flags().StringVarP(&seriesResolution, "resolution", "r", seriesResolution, "Bucket width: minute, hour or day")
+	userSeriesCmd.Flags().Uint32VarP(&seriesLimit, "limit", "l", 0, "Number of buckets to show (default: all)")
 
 	userGetCmd.Flags().StringVarP(&userGetParam.Passkey, "passkey", "p", "", "User passkey")
 	userGetCmd.Flags().Uint32VarP(&userGetParam.UserId, "user_id", "u", 0, "Internal tracker user ID")
@@ -12,14 +12,15 @@ import (
 )
 
 var (
-	wlParams = &pb.WhiteList{}
+	wlParams       = &pb.WhiteList{}
+	wlDeleteParams = &pb.WhiteListDeleteParams{}
 )
 
 func renderWhitelist(wl []*store.WhiteListClient, title string) {
 	t := defaultTable(title)
-	t.AppendHeader(table.Row{"name", "prefix"})
+	t.AppendHeader(table.Row{"name", "code", "min_version", "max_version", "blacklist"})
 	for _, w := range wl {
-		t.AppendRow(table.Row{w.ClientName, w.ClientPrefix})
+		t.AppendRow(table.Row{w.ClientName, w.ClientCode, w.MinVersion, w.MaxVersion, w.Blacklist})
 	}
 	t.SortBy([]table.SortBy{{
 		Name: "name",
@@ -42,12 +43,12 @@ var whiteListAddCmd = &cobra.Command{
 	Short: "Add a client whitelist to the tracker",
 	Long:  `Add a client whitelist to the tracker`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if wlParams.Name == "" || wlParams.Prefix == "" {
-			log.Fatalf("Must supply non-empty name and prefix")
+		if wlParams.Name == "" || wlParams.Code == "" {
+			log.Fatalf("Must supply non-empty name and code")
 			return
 		}
 		if _, err := cl.WhiteListAdd(context.Background(), wlParams); err != nil {
-			log.Fatalf("Failed to add whitelist entry")
+			log.Fatalf("Failed to add whitelist entry: %v", err)
 			return
 		}
 		log.Infof("Client whitelist added: %s", wlParams.Name)
@@ -79,6 +80,15 @@ var whiteListDeleteCmd = &cobra.Command{
 	Short: "Delete a client whitelist from the tracker",
 	Long:  `Delete a client whitelist from the tracker`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if wlDeleteParams.Code == "" {
+			log.Fatalf("Must supply non-empty code")
+			return
+		}
+		if _, err := cl.WhiteListDelete(context.Background(), wlDeleteParams); err != nil {
+			log.Fatalf("Failed to delete whitelist entry: %v", err)
+			return
+		}
+		log.Infof("Client whitelist deleted: %s", wlDeleteParams.Code)
 	},
 }
 
@@ -88,8 +98,19 @@ func init() {
 	whiteListCmd.AddCommand(whiteListAddCmd)
 	whiteListCmd.AddCommand(whiteListDeleteCmd)
 
-	whiteListAddCmd.Flags().StringVarP(&wlParams.Prefix, "prefix", "p", "", "Prefix to match the client")
+	whiteListAddCmd.Flags().StringVarP(&wlParams.Code, "code", "c", "",
+		"Client code from the peer_id to match, eg: qB for qBittorrent")
 	whiteListAddCmd.Flags().StringVarP(&wlParams.Name, "name", "n", "", "Name of the client")
+	whiteListAddCmd.Flags().StringVarP(&wlParams.MinVersion, "min_version", "m", "",
+		"Minimum client version to match, eg: 4.1.0")
+	whiteListAddCmd.Flags().StringVarP(&wlParams.MaxVersion, "max_version", "x", "",
+		"Maximum client version to match, eg: 4.3.3")
+	whiteListAddCmd.Flags().BoolVarP(&wlParams.Blacklist, "blacklist", "b", false,
+		"Deny matching clients instead of allowing them")
 
-	whiteListDeleteCmd.Flags().StringVarP(&wlParams.Prefix, "prefix", "p", "", "Prefix to match the client")
+	whiteListDeleteCmd.Flags().StringVarP(&wlDeleteParams.Code, "code", "c", "", "Client code of the entry")
+	whiteListDeleteCmd.Flags().StringVarP(&wlDeleteParams.MinVersion, "min_version", "m", "",
+		"Minimum client version of the entry")
+	whiteListDeleteCmd.Flags().StringVarP(&wlDeleteParams.MaxVersion, "max_version", "x", "",
+		"Maximum client version of the entry")
 }
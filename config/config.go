@@ -15,6 +15,14 @@ import (
 	"time"
 )
 
+// Whitelist modes which decide if clients not matching any whitelist entry are allowed
+const (
+	// WhitelistAllowAll allows all clients unless they match a blacklist entry
+	WhitelistAllowAll = "allow_all"
+	// WhitelistDenyAll denies all clients unless they match a whitelist entry
+	WhitelistDenyAll = "deny_all"
+)
+
 var (
 	General = generalConfig{
 		RunMode:   "",
@@ -41,6 +49,28 @@ var (
 		AllowNonRoutable:              true,
 		AllowClientIP:                 false,
 		MaxPeers:                      50,
+		ForceCompact:                  false,
+		AnnounceThrottleStrip:         false,
+		TrackerID:                     "",
+		ExternalIP:                    false,
+		HNRWarning:                    false,
+		DisabledTorrentWarning:        false,
+		ClientSpoofCheck:              false,
+		ClientSpoofReject:             false,
+		WhitelistMode:                 WhitelistDenyAll,
+		ConnectableCheck:              false,
+		ConnectableWorkers:            10,
+		ConnectableTimeout:            "5s",
+		ConnectableTimeoutParsed:      5 * time.Second,
+		ConnectableTTL:                "1h",
+		ConnectableTTLParsed:          time.Hour,
+		ScrapeMaxInfoHashes:           50,
+		FullScrape:                    false,
+		ScrapeInterval:                "30s",
+		ScrapeIntervalParsed:          30 * time.Second,
+		SeriesMinutes:                 60,
+		SeriesHours:                   48,
+		SeriesDays:                    90,
 	}
 	API = rpcConfig{
 		Listen: "localhost:34001",
@@ -61,14 +91,42 @@ var (
 		APIKey:  "",
 		Enabled: false,
 	}
+	Webhooks = WebhookConfig{
+		Enabled:               false,
+		QueuePath:             "./webhook_queue",
+		BatchSize:             100,
+		BatchInterval:         "5s",
+		BatchIntervalParsed:   5 * time.Second,
+		Timeout:               "10s",
+		TimeoutParsed:         10 * time.Second,
+		RetryMax:              10,
+		RetryBackoff:          "10s",
+		RetryBackoffParsed:    10 * time.Second,
+		RetryBackoffMax:       "1h",
+		RetryBackoffMaxParsed: time.Hour,
+		Endpoints:             nil,
+	}
+	Metrics = MetricsConfig{
+		Interval:       "10s",
+		IntervalParsed: 10 * time.Second,
+		Tags:           nil,
+		Exporters:      nil,
+	}
+	Audit = AuditConfig{
+		Store: true,
+		File:  "",
+	}
 )
 
 type fullConfig struct {
-	General generalConfig `mapstructure:"general"`
-	Tracker trackerConfig `mapstructure:"tracker"`
-	API     rpcConfig     `mapstructure:"api"`
-	Store   StoreConfig   `mapstructure:"store"`
-	GeoDB   geoDBConfig   `mapstructure:"geodb"`
+	General  generalConfig `mapstructure:"general"`
+	Tracker  trackerConfig `mapstructure:"tracker"`
+	API      rpcConfig     `mapstructure:"api"`
+	Store    StoreConfig   `mapstructure:"store"`
+	GeoDB    geoDBConfig   `mapstructure:"geodb"`
+	Webhooks WebhookConfig `mapstructure:"webhooks"`
+	Metrics  MetricsConfig `mapstructure:"metrics"`
+	Audit    AuditConfig   `mapstructure:"audit"`
 }
 
 type generalConfig struct {
@@ -115,10 +173,19 @@ type trackerConfig struct {
 	// 60s|1m
 	AnnounceIntervalMinimum       string `mapstructure:"announce_interval_minimum"`
 	AnnounceIntervalMinimumParsed time.Duration
+	// AnnounceThrottleStrip changes how announces made before the minimum interval are handled. By
+	// default they are rejected, when enabled they are processed but no peers are returned.
+	// Announces with an event (started/stopped/completed) are never throttled.
+	// true|false
+	AnnounceThrottleStrip bool `mapstructure:"announce_throttle_strip"`
 	// TrackerHNRThreshold is how much time must pass before we mark a peer as Hit-N-Run
 	// 1d|12h|60m
 	HNRThreshold       string `mapstructure:"hnr_threshold"`
 	HNRThresholdParsed time.Duration
+	// HNRWarning sends a warning message to peers who stop seeding a torrent they downloaded before
+	// the HNRThreshold has passed
+	// true|false
+	HNRWarning bool `mapstructure:"hnr_warning"`
 	// TrackerBatchUpdateInterval defines how often we sync user stats to the back store
 	BatchUpdateInterval       string `mapstructure:"batch_update_interval"`
 	BatchUpdateIntervalParsed time.Duration
@@ -127,6 +194,68 @@ type trackerConfig struct {
 	AllowClientIP    bool `mapstructure:"allow_client_ip"`
 
 	MaxPeers int `mapstructure:"max_peers"`
+	// ForceCompact will always send compact peer lists (BEP23), ignoring a clients request
+	// for the non-compact dictionary model
+	// true|false
+	ForceCompact bool `mapstructure:"force_compact"`
+	// TrackerID is sent to clients as the tracker id, which they will send back on subsequent announces
+	// Empty disables sending it
+	// mika
+	TrackerID string `mapstructure:"tracker_id"`
+	// ExternalIP sends the address the tracker saw the client connecting from (BEP24)
+	// true|false
+	ExternalIP bool `mapstructure:"external_ip"`
+	// DisabledTorrentWarning sends the reason a torrent was disabled, eg: trumped by a newer torrent, as
+	// a warning message instead of failing the announce. No peers are returned for disabled torrents.
+	// true|false
+	DisabledTorrentWarning bool `mapstructure:"disabled_torrent_warning"`
+	// ClientSpoofCheck compares the client decoded from the peer_id with the User-Agent header
+	// sent by the client. Mismatches are logged, counted and the peer is flagged as spoofed.
+	// true|false
+	ClientSpoofCheck bool `mapstructure:"client_spoof_check"`
+	// ClientSpoofReject rejects announces from clients detected by ClientSpoofCheck
+	// true|false
+	ClientSpoofReject bool `mapstructure:"client_spoof_reject"`
+	// WhitelistMode decides if clients which do not match any whitelist entry are allowed.
+	// Blacklist entries are always denied.
+	// allow_all|deny_all
+	WhitelistMode string `mapstructure:"whitelist_mode"`
+	// ConnectableCheck enables checking if new peers accept incoming connections by attempting a
+	// handshake with them. Peers which cannot be connected to are only sent to connectable peers.
+	// true|false
+	ConnectableCheck bool `mapstructure:"connectable_check"`
+	// ConnectableWorkers is the maximum number of concurrent connectable checks
+	// 10
+	ConnectableWorkers int `mapstructure:"connectable_workers"`
+	// ConnectableTimeout is how long to wait for a peer to complete the handshake
+	// 5s
+	ConnectableTimeout       string `mapstructure:"connectable_timeout"`
+	ConnectableTimeoutParsed time.Duration
+	// ConnectableTTL is how long the result of a check is cached for each ip:port
+	// 1h
+	ConnectableTTL       string `mapstructure:"connectable_ttl"`
+	ConnectableTTLParsed time.Duration
+	// ScrapeMaxInfoHashes is the maximum number of info hashes a client can request
+	// within a single scrape request, and within each scrape_interval. 0 removes the limit
+	// on the number of info hashes and allows a single scrape within each scrape_interval.
+	// 50
+	ScrapeMaxInfoHashes int `mapstructure:"scrape_max_info_hashes"`
+	// FullScrape allows scrape requests without any info hashes to return stats for every
+	// known torrent. This is only honoured when running in public mode.
+	// true|false
+	FullScrape bool `mapstructure:"full_scrape"`
+	// ScrapeInterval is the period in which a user, or an ip address in public mode, may scrape
+	// up to scrape_max_info_hashes info hashes. A full scrape uses the whole budget.
+	// This is also sent to clients as the BEP48 min_request_interval flag.
+	// 30s|1m
+	ScrapeInterval       string `mapstructure:"scrape_interval"`
+	ScrapeIntervalParsed time.Duration
+	// SeriesMinutes, SeriesHours and SeriesDays are the number of buckets of each resolution
+	// kept in the per torrent and per user time series, 0 disables the resolution
+	// 60, 48, 90
+	SeriesMinutes int `mapstructure:"series_minutes"`
+	SeriesHours   int `mapstructure:"series_hours"`
+	SeriesDays    int `mapstructure:"series_days"`
 }
 
 type rpcConfig struct {
@@ -138,6 +267,19 @@ type rpcConfig struct {
 	TLS bool `mapstructure:"tls"`
 	// APIKey Basic key authentication token for API calls
 	Key string `mapstructure:"key"`
+	// Keys are additional api keys by name, the name of the key used is recorded in the audit log
+	// so each caller can be given their own key. The api.key is named default.
+	Keys map[string]string `mapstructure:"keys"`
+}
+
+// AuditConfig sets where the audit log of changes made through the API is written
+type AuditConfig struct {
+	// Store records entries in the tracker store, AuditList reads from the store when enabled
+	Store bool `mapstructure:"store"`
+	// File is the path of an append only JSONL file entries are also written to when set.
+	// AuditList reads from the file when the store is disabled.
+	// ./audit.jsonl
+	File string `mapstructure:"file"`
 }
 
 type StoreConfig struct {
@@ -177,6 +319,91 @@ type geoDBConfig struct {
 	Enabled bool `mapstructure:"enabled"`
 }
 
+// WebhookConfig configures delivery of tracker events to HTTP endpoints
+type WebhookConfig struct {
+	// Enabled toggles the webhook dispatcher
+	// true|false
+	Enabled bool `mapstructure:"enabled"`
+	// QueuePath is the directory batches are stored in until they are delivered so that
+	// they survive restarts
+	// ./webhook_queue
+	QueuePath string `mapstructure:"queue_path"`
+	// BatchSize is the maximum number of events sent in a single request
+	// 100
+	BatchSize int `mapstructure:"batch_size"`
+	// BatchInterval is how long events are collected for before being sent
+	// 5s
+	BatchInterval       string `mapstructure:"batch_interval"`
+	BatchIntervalParsed time.Duration
+	// Timeout is how long to wait for an endpoint to respond
+	// 10s
+	Timeout       string `mapstructure:"timeout"`
+	TimeoutParsed time.Duration
+	// RetryMax is the number of delivery attempts made before a batch is discarded, 0 retries forever
+	// 10
+	RetryMax int `mapstructure:"retry_max"`
+	// RetryBackoff is the delay before the first retry, it is doubled for each subsequent attempt
+	// 10s
+	RetryBackoff       string `mapstructure:"retry_backoff"`
+	RetryBackoffParsed time.Duration
+	// RetryBackoffMax is the upper limit of the delay between retries
+	// 1h
+	RetryBackoffMax       string `mapstructure:"retry_backoff_max"`
+	RetryBackoffMaxParsed time.Duration
+	// Endpoints are the URLs which receive events
+	Endpoints []WebhookEndpoint `mapstructure:"endpoints"`
+}
+
+// WebhookEndpoint is a single URL which is sent batches of events
+type WebhookEndpoint struct {
+	// URL events are POSTed to
+	// https://example.com/tracker/events
+	URL string `mapstructure:"url"`
+	// Secret is used to sign the request body with HMAC-SHA256
+	Secret string `mapstructure:"secret"`
+	// Events are the names of the event types sent to the endpoint. When empty the completed,
+	// auto registered, hit and run and cheat events are sent.
+	// peer_completed|torrent_auto_registered|hit_and_run|cheat
+	Events []string `mapstructure:"events"`
+}
+
+// MetricsConfig configures pushing metrics to collectors which cannot scrape the /metrics endpoint
+type MetricsConfig struct {
+	// Interval is how often the metrics are pushed to each exporter
+	// 10s
+	Interval       string `mapstructure:"interval"`
+	IntervalParsed time.Duration
+	// Tags are added to every metric sent by all exporters
+	// host: tracker1
+	Tags map[string]string `mapstructure:"tags"`
+	// Exporters are the destinations metrics are pushed to
+	Exporters []MetricsExporter `mapstructure:"exporters"`
+}
+
+// MetricsExporter is a single destination for metrics
+type MetricsExporter struct {
+	// Type is the protocol used to push the metrics
+	// statsd|influxdb
+	Type string `mapstructure:"type"`
+	// Address is the host:port of the statsd server
+	// localhost:8125
+	Address string `mapstructure:"address"`
+	// TagFormat sets how tags are sent to statsd, either as dogstatsd style |#key:value suffixes
+	// or influxdb style name,key=value names as used by telegraf
+	// dogstatsd|influxdb|none
+	TagFormat string `mapstructure:"tag_format"`
+	// URL is the write endpoint of the influxdb server, including the database or bucket
+	// http://localhost:8086/write?db=mika
+	URL string `mapstructure:"url"`
+	// Token is sent in the Authorization header of influxdb requests when set
+	Token string `mapstructure:"token"`
+	// Prefix is prepended to the name of each metric
+	// mika.
+	Prefix string `mapstructure:"prefix"`
+	// Tags are added to the metrics sent by this exporter, overriding global tags with the same key
+	Tags map[string]string `mapstructure:"tags"`
+}
+
 // DSN constructs a URI for database connection strings
 //
 // protocol//[user]:[password]@tcp([host]:[port])[/database][?properties]
@@ -210,6 +437,10 @@ func Read(cfgFile string) error {
 		viper.SetConfigName(cfgFile)
 	}
 	setDuration := func(target *time.Duration, value string) error {
+		if value == "" {
+			// Keep the default for keys missing from older configs
+			return nil
+		}
 		d, err := util.ParseDuration(value)
 		if err != nil {
 			return err
@@ -224,7 +455,9 @@ func Read(cfgFile string) error {
 		return errors.Wrap(err, consts.ErrInvalidConfig.Error())
 	}
 	log.Debugf("Using config file: %s", viper.ConfigFileUsed())
-	full := fullConfig{}
+	// Configs written for older versions are missing the newer tracker keys and the webhooks, metrics
+	// and audit sections are optional, so start with the defaults
+	full := fullConfig{Tracker: Tracker, Webhooks: Webhooks, Metrics: Metrics, Audit: Audit}
 	if err := viper.Unmarshal(&full); err != nil {
 		return errors.Wrapf(err, "Failed to parse config")
 	}
@@ -237,26 +470,89 @@ func Read(cfgFile string) error {
 		{&full.Tracker.BatchUpdateIntervalParsed, full.Tracker.BatchUpdateInterval},
 		{&full.Tracker.HNRThresholdParsed, full.Tracker.HNRThreshold},
 		{&full.Tracker.ReaperIntervalParsed, full.Tracker.ReaperInterval},
+		{&full.Tracker.ScrapeIntervalParsed, full.Tracker.ScrapeInterval},
+		{&full.Webhooks.BatchIntervalParsed, full.Webhooks.BatchInterval},
+		{&full.Webhooks.TimeoutParsed, full.Webhooks.Timeout},
+		{&full.Webhooks.RetryBackoffParsed, full.Webhooks.RetryBackoff},
+		{&full.Webhooks.RetryBackoffMaxParsed, full.Webhooks.RetryBackoffMax},
+		{&full.Metrics.IntervalParsed, full.Metrics.Interval},
 	}
 	for _, dur := range durations {
 		if err := setDuration(dur.target, dur.value); err != nil {
 			return errors.Wrapf(err, "Failed to parse time duration")
 		}
 	}
+	// The connectable settings are only used, and so only validated, when the check is enabled
+	if full.Tracker.ConnectableCheck {
+		if err := setDuration(&full.Tracker.ConnectableTimeoutParsed, full.Tracker.ConnectableTimeout); err != nil {
+			return errors.Wrapf(err, "Failed to parse tracker.connectable_timeout")
+		}
+		if err := setDuration(&full.Tracker.ConnectableTTLParsed, full.Tracker.ConnectableTTL); err != nil {
+			return errors.Wrapf(err, "Failed to parse tracker.connectable_ttl")
+		}
+		if full.Tracker.ConnectableTimeoutParsed <= 0 || full.Tracker.ConnectableTTLParsed <= 0 {
+			return errors.New("tracker.connectable_timeout and tracker.connectable_ttl must be greater than 0")
+		}
+		if full.Tracker.ConnectableWorkers <= 0 {
+			return errors.New("tracker.connectable_workers must be greater than 0")
+		}
+	}
 	if full.API.Key == "" {
 		return errors.New("api.key cannot be empty")
 	}
+	for name, key := range full.API.Keys {
+		if name == "default" {
+			return errors.New("api.keys cannot contain a key named default, it is used for api.key")
+		}
+		if key == "" {
+			return errors.Errorf("api.keys.%s cannot be empty", name)
+		}
+	}
+	switch full.Tracker.WhitelistMode {
+	case "":
+		full.Tracker.WhitelistMode = WhitelistDenyAll
+	case WhitelistAllowAll, WhitelistDenyAll:
+	default:
+		return errors.Errorf("tracker.whitelist_mode must be one of: %s, %s", WhitelistAllowAll, WhitelistDenyAll)
+	}
 	General = full.General
 	Tracker = full.Tracker
 	API = full.API
 	GeoDB = full.GeoDB
 	Store = full.Store
+	Webhooks = full.Webhooks
+	Metrics = full.Metrics
+	Audit = full.Audit
 
 	setupLogger(General.LogLevel, General.LogColour)
 	gin.SetMode(General.RunMode)
 	return nil
 }
 
+// ReadStoreConfig reads the store section of the config file at path without changing the
+// active config. The file may either be a full mika config or contain only the store settings.
+func ReadStoreConfig(path string) (StoreConfig, error) {
+	var sc StoreConfig
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return sc, errors.Wrap(err, consts.ErrInvalidConfig.Error())
+	}
+	var err error
+	if v.IsSet("store") {
+		err = v.UnmarshalKey("store", &sc)
+	} else {
+		err = v.Unmarshal(&sc)
+	}
+	if err != nil {
+		return sc, errors.Wrapf(err, "Failed to parse store config")
+	}
+	if sc.Type == "" {
+		return sc, errors.Errorf("No store type defined in: %s", path)
+	}
+	return sc, nil
+}
+
 func setupLogger(levelStr string, colour bool) {
 	log.SetFormatter(&log.TextFormatter{
 		ForceColors:      colour,
@@ -2,7 +2,12 @@ package config
 
 import (
 	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLogger(t *testing.T) {
@@ -25,3 +30,77 @@ func TestStoreConfig_DSN(t *testing.T) {
 		"test:pass@tcp(localhost:5432)/db?arg1=foo&arg2=bar",
 		c.DSN())
 }
+
+func TestReadStoreConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mika-config")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	full := filepath.Join(dir, "full.yaml")
+	require.NoError(t, ioutil.WriteFile(full, []byte("store:\n  type: mysql\n  host: db\n  port: 3306\n"), 0600))
+	sc, err := ReadStoreConfig(full)
+	require.NoError(t, err)
+	require.Equal(t, "mysql", sc.Type)
+	require.Equal(t, "db", sc.Host)
+	require.Equal(t, 3306, sc.Port)
+
+	storeOnly := filepath.Join(dir, "store.yaml")
+	require.NoError(t, ioutil.WriteFile(storeOnly, []byte("type: redis\nhost: cache\n"), 0600))
+	sc, err = ReadStoreConfig(storeOnly)
+	require.NoError(t, err)
+	require.Equal(t, "redis", sc.Type)
+
+	empty := filepath.Join(dir, "empty.yaml")
+	require.NoError(t, ioutil.WriteFile(empty, []byte("general:\n  log_level: info\n"), 0600))
+	_, err = ReadStoreConfig(empty)
+	require.Error(t, err)
+}
+
+func TestReadBaselineConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mika-config")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	// A config written before the scrape, connectable and series settings were added
+	baseline := `general:
+  run_mode: release
+  log_level: warn
+  log_colour: false
+tracker:
+  public: false
+  listen: "0.0.0.0:34000"
+  auto_register: true
+  reaper_interval: 90s
+  announce_interval: 30s
+  announce_interval_minimum: 10s
+  hnr_threshold: 1d
+  batch_update_interval: 30s
+  allow_non_routable: false
+  allow_client_ip: false
+  max_peers: 60
+api:
+  listen: ":34001"
+  key: baseline
+store:
+  type: memory
+`
+	path := filepath.Join(dir, "mika.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(baseline), 0600))
+	require.NoError(t, os.Setenv("MIKA_CONFIG", path))
+	defer func() { _ = os.Unsetenv("MIKA_CONFIG") }()
+	require.NoError(t, Read(""))
+	require.Equal(t, 60, Tracker.MaxPeers)
+	require.Equal(t, 50, Tracker.ScrapeMaxInfoHashes)
+	require.Equal(t, 30*time.Second, Tracker.ScrapeIntervalParsed)
+	require.Equal(t, 60, Tracker.SeriesMinutes)
+	require.Equal(t, WhitelistDenyAll, Tracker.WhitelistMode)
+	require.Equal(t, 5*time.Second, Tracker.ConnectableTimeoutParsed)
+	require.Equal(t, time.Hour, Tracker.ConnectableTTLParsed)
+
+	// Unused connectable settings are not validated
+	disabled := strings.Replace(baseline, "  max_peers: 60\n",
+		"  max_peers: 60\n  connectable_check: false\n  connectable_ttl: never\n", 1)
+	require.NoError(t, ioutil.WriteFile(path, []byte(disabled), 0600))
+	require.NoError(t, Read(""))
+	enabled := strings.Replace(disabled, "connectable_check: false", "connectable_check: true", 1)
+	require.NoError(t, ioutil.WriteFile(path, []byte(enabled), 0600))
+	require.Error(t, Read(""))
+}
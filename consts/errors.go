@@ -28,6 +28,8 @@ var (
 	// ErrInvalidUser is used when a user lookup fails
 	ErrInvalidUser = errors.New("invalid user")
 	ErrInvalidRole = errors.New("invalid role")
+	// ErrRoleInUse is used when trying to delete a role that is still assigned to users
+	ErrRoleInUse   = errors.New("role still assigned to users")
 	ErrInvalidPeer = errors.New("invalid peer")
 	// ErrInvalidClient is used when an invalid client is requested/used
 	ErrInvalidClient = errors.New("invalid torrent client")
@@ -35,4 +37,6 @@ var (
 	ErrBadResponseCode = errors.New("bad response code returned")
 
 	ErrCannotConnect = errors.New("cannot connect to server")
+	// ErrAuditDisabled is returned when listing the audit log while neither the store or file is enabled
+	ErrAuditDisabled = errors.New("audit log is disabled")
 )
@@ -9,7 +9,7 @@ import (
 	//_ "github.com/leighmacdonald/mika/store/http"
 	_ "github.com/leighmacdonald/mika/store/memory"
 	_ "github.com/leighmacdonald/mika/store/mysql"
-	//_ "github.com/leighmacdonald/mika/store/postgres"
+	_ "github.com/leighmacdonald/mika/store/postgres"
 	_ "github.com/leighmacdonald/mika/store/redis"
 )
 
@@ -0,0 +1,181 @@
+package metrics
+
+import (
+	"context"
+	"github.com/leighmacdonald/mika/config"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Exporter pushes snapshots of the metrics to a collector, for deployments which cannot
+// scrape the /metrics endpoint
+type Exporter interface {
+	// Export sends a single snapshot of the metrics
+	Export(ctx context.Context, m RuntimeMetrics) error
+	// Close releases any connections held by the exporter
+	Close() error
+}
+
+// Sample is a single value of a metric. Histograms are flattened into their _sum and _count
+// samples as the collectors keep their own distributions.
+type Sample struct {
+	Name string
+	// Label and LabelValue are set for samples of labelled metrics, eg: status=ok
+	Label      string
+	LabelValue string
+	Value      float64
+	// Counter is set for values which only increase
+	Counter bool
+}
+
+// key uniquely identifies the sample by its name and label
+func (s Sample) key() string {
+	return s.Name + "\x00" + s.LabelValue
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	default:
+		return 0
+	}
+}
+
+func histogramSamples(name string, label string, labelValue string, h HistogramSnapshot) []Sample {
+	return []Sample{
+		{Name: name + "_sum", Label: label, LabelValue: labelValue, Value: h.Sum, Counter: true},
+		{Name: name + "_count", Label: label, LabelValue: labelValue, Value: float64(h.Count), Counter: true},
+	}
+}
+
+// Samples flattens the metrics into a list of values using the same names and labels
+// as the prometheus format
+func (m RuntimeMetrics) Samples() []Sample {
+	var samples []Sample
+	v := reflect.ValueOf(m)
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get("prom")
+		if name == "" {
+			continue
+		}
+		label := field.Tag.Get("prom_label")
+		counter := field.Tag.Get("prom_type") == "counter"
+		switch value := v.Field(i).Interface().(type) {
+		case HistogramSnapshot:
+			samples = append(samples, histogramSamples(name, "", "", value)...)
+		case map[string]HistogramSnapshot:
+			keys := make([]string, 0, len(value))
+			for k := range value {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				samples = append(samples, histogramSamples(name, label, k, value[k])...)
+			}
+		case string:
+			if value != "" {
+				samples = append(samples, Sample{Name: name, Label: label, LabelValue: value, Value: 1})
+			}
+		default:
+			if field.Type.Kind() == reflect.Map {
+				keys := v.Field(i).MapKeys()
+				sort.Slice(keys, func(a, b int) bool {
+					return keys[a].String() < keys[b].String()
+				})
+				for _, k := range keys {
+					samples = append(samples, Sample{Name: name, Label: label, LabelValue: k.String(),
+						Value: toFloat(v.Field(i).MapIndex(k)), Counter: counter})
+				}
+				continue
+			}
+			samples = append(samples, Sample{Name: name, Label: label,
+				LabelValue: field.Tag.Get("prom_label_value"), Value: toFloat(v.Field(i)), Counter: counter})
+		}
+	}
+	return samples
+}
+
+// mergeTags returns the global tags overridden by the exporters own tags
+func mergeTags(global map[string]string, own map[string]string) map[string]string {
+	tags := make(map[string]string, len(global)+len(own))
+	for k, v := range global {
+		tags[k] = v
+	}
+	for k, v := range own {
+		tags[k] = v
+	}
+	return tags
+}
+
+// sortedKeys returns the keys of the tags in a stable order
+func sortedKeys(tags map[string]string) []string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// NewExporters creates the exporters defined in the config
+func NewExporters(cfg config.MetricsConfig) ([]Exporter, error) {
+	var exporters []Exporter
+	for _, ec := range cfg.Exporters {
+		tags := mergeTags(cfg.Tags, ec.Tags)
+		var (
+			e   Exporter
+			err error
+		)
+		switch ec.Type {
+		case "statsd":
+			e, err = NewStatsD(ec.Address, ec.Prefix, ec.TagFormat, tags)
+		case "influxdb":
+			e, err = NewInfluxDB(ec.URL, ec.Token, ec.Prefix, tags)
+		default:
+			err = errors.Errorf("unknown metrics exporter type: %s", ec.Type)
+		}
+		if err != nil {
+			for _, existing := range exporters {
+				_ = existing.Close()
+			}
+			return nil, err
+		}
+		exporters = append(exporters, e)
+	}
+	return exporters, nil
+}
+
+// Push sends a snapshot of the metrics to each exporter every interval until the context
+// is cancelled, after which the exporters are closed
+func Push(ctx context.Context, interval time.Duration, exporters []Exporter) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			m := Get()
+			for _, e := range exporters {
+				if err := e.Export(ctx, m); err != nil {
+					log.Errorf("Failed to export metrics: %v", err)
+				}
+			}
+		case <-ctx.Done():
+			for _, e := range exporters {
+				if err := e.Close(); err != nil {
+					log.Errorf("Failed to close metrics exporter: %v", err)
+				}
+			}
+			return
+		}
+	}
+}
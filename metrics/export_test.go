@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"context"
+	"github.com/leighmacdonald/mika/config"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSamples(t *testing.T) {
+	m := RuntimeMetrics{
+		AnnounceTotal:    10,
+		AnnounceStatusOK: 8,
+		AnnounceClients:  map[string]int64{"qBittorrent": 3},
+		AnnounceLatency:  NewHistogramSnapshot(LatencyBuckets, []float64{0.001, 0.002}),
+		StoreDriver:      "memory",
+		GoRoutines:       5,
+	}
+	found := map[string]Sample{}
+	for _, s := range m.Samples() {
+		found[s.Name+"/"+s.LabelValue] = s
+	}
+	require.Equal(t, Sample{Name: "t_ann_total", Value: 10, Counter: true}, found["t_ann_total/"])
+	require.Equal(t, Sample{Name: "t_ann_status_total", Label: "status", LabelValue: "ok", Value: 8, Counter: true},
+		found["t_ann_status_total/ok"])
+	require.EqualValues(t, 3, found["t_ann_client_total/qBittorrent"].Value)
+	require.EqualValues(t, 2, found["t_ann_latency_seconds_count/"].Value)
+	require.InDelta(t, 0.003, found["t_ann_latency_seconds_sum/"].Value, 0.0001)
+	require.Equal(t, "driver", found["t_store_info/memory"].Label)
+	require.False(t, found["go_routines/"].Counter)
+	require.NotContains(t, found, "t_ann_latency_seconds/")
+}
+
+func readPackets(t *testing.T, conn net.PacketConn) string {
+	var lines []string
+	buf := make([]byte, 65535)
+	for {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			break
+		}
+		require.LessOrEqual(t, n, statsdPacketSize)
+		lines = append(lines, string(buf[:n]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestStatsD(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	_, err = NewStatsD(conn.LocalAddr().String(), "", "invalid", nil)
+	require.Error(t, err)
+	s, err := NewStatsD(conn.LocalAddr().String(), "mika.", "", map[string]string{"host": "t1"})
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+
+	m := RuntimeMetrics{AnnounceTotal: 5, AnnounceStatusOK: 2, GoRoutines: 3, StoreDriver: "memory"}
+	require.NoError(t, s.Export(context.Background(), m))
+	out := readPackets(t, conn)
+	require.Contains(t, out, "mika.t_ann_total:5|c|#host:t1\n")
+	require.Contains(t, out, "mika.t_ann_status_total:2|c|#host:t1,status:ok\n")
+	require.Contains(t, out, "mika.go_routines:3|g|#host:t1\n")
+	require.Contains(t, out, "mika.t_store_info:1|g|#driver:memory,host:t1")
+
+	// Counters are sent as the change since the last export
+	m.AnnounceTotal = 8
+	require.NoError(t, s.Export(context.Background(), m))
+	out = readPackets(t, conn)
+	require.Contains(t, out, "mika.t_ann_total:3|c|#host:t1\n")
+	require.NotContains(t, out, "t_ann_status_total")
+
+	influx, err := NewStatsD(conn.LocalAddr().String(), "", TagFormatInfluxDB, map[string]string{"host": "t1"})
+	require.NoError(t, err)
+	defer func() { _ = influx.Close() }()
+	require.NoError(t, influx.Export(context.Background(), m))
+	require.Contains(t, readPackets(t, conn), "t_ann_status_total,host=t1,status=ok:2|c\n")
+
+	none, err := NewStatsD(conn.LocalAddr().String(), "", TagFormatNone, map[string]string{"host": "t1"})
+	require.NoError(t, err)
+	defer func() { _ = none.Close() }()
+	m.AnnounceClients = map[string]int64{"Transmission 3.0": 1}
+	require.NoError(t, none.Export(context.Background(), m))
+	out = readPackets(t, conn)
+	require.Contains(t, out, "t_ann_status_total.ok:2|c\n")
+	require.Contains(t, out, "t_ann_client_total.Transmission_3.0:1|c\n")
+}
+
+func TestInfluxDB(t *testing.T) {
+	var (
+		body  string
+		auth  string
+		calls int32
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		auth = r.Header.Get("Authorization")
+		if atomic.AddInt32(&calls, 1) > 1 {
+			http.Error(w, "database not found", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	_, err := NewInfluxDB("", "", "", nil)
+	require.Error(t, err)
+	i, err := NewInfluxDB(ts.URL+"/write?db=mika", "secret", "", map[string]string{"host": "t 1"})
+	require.NoError(t, err)
+	m := RuntimeMetrics{AnnounceTotal: 5, AnnounceClients: map[string]int64{"qBittorrent 4,2": 1}}
+	require.NoError(t, i.Export(context.Background(), m))
+	require.Equal(t, "Token secret", auth)
+	require.Regexp(t, `(?m)^t_ann_total,host=t\\ 1 value=5 \d+$`, body)
+	require.Contains(t, body, `t_ann_client_total,client=qBittorrent\ 4\,2,host=t\ 1 value=1 `)
+
+	err = i.Export(context.Background(), m)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "database not found")
+}
+
+func TestNewExporters(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	_, err = NewExporters(config.MetricsConfig{Exporters: []config.MetricsExporter{{Type: "graphite"}}})
+	require.Error(t, err)
+	_, err = NewExporters(config.MetricsConfig{Exporters: []config.MetricsExporter{{Type: "statsd"}}})
+	require.Error(t, err)
+
+	exporters, err := NewExporters(config.MetricsConfig{
+		Tags: map[string]string{"host": "t1", "env": "test"},
+		Exporters: []config.MetricsExporter{
+			{Type: "statsd", Address: conn.LocalAddr().String(), Tags: map[string]string{"host": "t2"}},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, exporters, 1)
+
+	// Per event counters are included with each push
+	AddEvent("peer_completed")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		Push(ctx, 10*time.Millisecond, exporters)
+		close(done)
+	}()
+	buf := make([]byte, 65535)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	cancel()
+	<-done
+	require.NoError(t, err)
+	require.Contains(t, string(buf[:n]), "t_events_total:1|c|#env:test,host:t2,type:peer_completed")
+}
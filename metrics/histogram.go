@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// LatencyBuckets are the upper bounds, in seconds, of the request latency histograms
+	LatencyBuckets = []float64{0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+	// SyncBuckets are the upper bounds, in seconds, of the store sync duration histograms
+	SyncBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+	// SwarmBuckets are the upper bounds, in peers, of the swarm size distribution
+	SwarmBuckets = []float64{0, 1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 5000}
+)
+
+// Histogram counts observations into a fixed set of buckets, as used by prometheus histograms
+type Histogram struct {
+	mu      *sync.Mutex
+	buckets []float64
+	// counts holds the non-cumulative count of each bucket followed by the +Inf bucket
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// HistogramSnapshot is a copy of the state of a Histogram
+type HistogramSnapshot struct {
+	// Buckets are the upper bounds of each bucket, not including +Inf
+	Buckets []float64
+	// Counts are the cumulative counts of observations less than or equal to each bucket
+	Counts []uint64
+	Sum    float64
+	Count  uint64
+}
+
+// NewHistogram creates a histogram using the sorted bucket upper bounds
+func NewHistogram(buckets []float64) *Histogram {
+	return &Histogram{
+		mu:      &sync.Mutex{},
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)+1),
+	}
+}
+
+// Observe adds a single value
+func (h *Histogram) Observe(v float64) {
+	idx := sort.SearchFloat64s(h.buckets, v)
+	h.mu.Lock()
+	h.counts[idx]++
+	h.sum += v
+	h.count++
+	h.mu.Unlock()
+}
+
+// ObserveSince adds the number of seconds elapsed since start
+func (h *Histogram) ObserveSince(start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+// Snapshot returns a copy of the current state
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := HistogramSnapshot{
+		Buckets: h.buckets,
+		Counts:  make([]uint64, len(h.buckets)),
+		Sum:     h.sum,
+		Count:   h.count,
+	}
+	var total uint64
+	for i := range h.buckets {
+		total += h.counts[i]
+		s.Counts[i] = total
+	}
+	return s
+}
+
+// NewHistogramSnapshot creates a snapshot of the distribution of values, used for values
+// which are computed on demand instead of observed over time, eg: the current swarm sizes
+func NewHistogramSnapshot(buckets []float64, values []float64) HistogramSnapshot {
+	h := NewHistogram(buckets)
+	for _, v := range values {
+		h.Observe(v)
+	}
+	return h.Snapshot()
+}
+
+// Sub returns the observations made since the earlier snapshot prev of the same histogram. The
+// snapshot is returned unchanged if the buckets of prev do not match.
+func (s HistogramSnapshot) Sub(prev HistogramSnapshot) HistogramSnapshot {
+	if len(prev.Counts) != len(s.Counts) || prev.Count > s.Count {
+		return s
+	}
+	d := HistogramSnapshot{
+		Buckets: s.Buckets,
+		Counts:  make([]uint64, len(s.Counts)),
+		Sum:     s.Sum - prev.Sum,
+		Count:   s.Count - prev.Count,
+	}
+	for i := range s.Counts {
+		d.Counts[i] = s.Counts[i] - prev.Counts[i]
+	}
+	return d
+}
+
+// Quantile estimates the value below which the fraction q of the observations fall, eg: 0.95,
+// by interpolating linearly within the bucket containing it. Observations above the largest
+// bucket are reported as the upper bound of the largest bucket. Returns 0 when empty.
+func (s HistogramSnapshot) Quantile(q float64) float64 {
+	if s.Count == 0 || len(s.Buckets) == 0 {
+		return 0
+	}
+	rank := q * float64(s.Count)
+	var lower float64
+	var below uint64
+	for i, upper := range s.Buckets {
+		if float64(s.Counts[i]) >= rank {
+			inBucket := s.Counts[i] - below
+			if inBucket == 0 {
+				return upper
+			}
+			return lower + (upper-lower)*(rank-float64(below))/float64(inBucket)
+		}
+		lower, below = upper, s.Counts[i]
+	}
+	return s.Buckets[len(s.Buckets)-1]
+}
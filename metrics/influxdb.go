@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// influxTimeout is how long to wait for the influxdb server to accept a write
+const influxTimeout = 10 * time.Second
+
+var (
+	influxMeasurementReplacer = strings.NewReplacer(",", `\,`, " ", `\ `, "\n", "")
+	influxTagReplacer         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `, "\n", "")
+)
+
+// InfluxDB writes metrics to an influxdb server using the line protocol over HTTP. Each sample
+// is written as a measurement with a single float value field, counters are sent as running totals.
+type InfluxDB struct {
+	url    string
+	token  string
+	prefix string
+	tags   map[string]string
+	client *http.Client
+}
+
+// NewInfluxDB creates an exporter writing to the write endpoint of an influxdb server, eg:
+// http://localhost:8086/write?db=mika for 1.x or http://localhost:8086/api/v2/write?org=x&bucket=y for 2.x
+func NewInfluxDB(writeURL string, token string, prefix string, tags map[string]string) (*InfluxDB, error) {
+	if writeURL == "" {
+		return nil, errors.New("influxdb url cannot be empty")
+	}
+	if _, err := url.ParseRequestURI(writeURL); err != nil {
+		return nil, errors.Wrap(err, "Invalid influxdb url")
+	}
+	return &InfluxDB{
+		url:    writeURL,
+		token:  token,
+		prefix: prefix,
+		tags:   tags,
+		client: &http.Client{Timeout: influxTimeout},
+	}, nil
+}
+
+// encode writes the samples in the line protocol
+func (i *InfluxDB) encode(w io.Writer, m RuntimeMetrics, ts time.Time) {
+	now := strconv.FormatInt(ts.UnixNano(), 10)
+	for _, sample := range m.Samples() {
+		tags := i.tags
+		if sample.Label != "" {
+			tags = mergeTags(i.tags, map[string]string{sample.Label: sample.LabelValue})
+		}
+		var b strings.Builder
+		b.WriteString(influxMeasurementReplacer.Replace(i.prefix + sample.Name))
+		for _, k := range sortedKeys(tags) {
+			if tags[k] == "" {
+				// Empty tag values are not allowed
+				continue
+			}
+			b.WriteString("," + influxTagReplacer.Replace(k) + "=" + influxTagReplacer.Replace(tags[k]))
+		}
+		b.WriteString(" value=" + strconv.FormatFloat(sample.Value, 'f', -1, 64) + " " + now + "\n")
+		_, _ = io.WriteString(w, b.String())
+	}
+}
+
+// Export writes the snapshot in a single request
+func (i *InfluxDB) Export(ctx context.Context, m RuntimeMetrics) error {
+	var body bytes.Buffer
+	i.encode(&body, m, time.Now())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, i.url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if i.token != "" {
+		req.Header.Set("Authorization", "Token "+i.token)
+	}
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Failed to write to influxdb")
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected influxdb status: %d %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// Close is a no-op as connections are managed by the http client
+func (i *InfluxDB) Close() error {
+	return nil
+}
@@ -2,12 +2,18 @@ package metrics
 
 import (
 	"fmt"
+	"github.com/leighmacdonald/mika/store"
+	"io"
+	"net/http"
 	"reflect"
 	"runtime"
 	"runtime/debug"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 var promHelp = map[string]string{
@@ -42,67 +48,177 @@ var promHelp = map[string]string{
 		"forced by the application calling the GC function.",
 	"gc_cpu_fraction": "gc_cpu_fraction is the fraction of this program's available " +
 		"CPU time used by the GC since the program started.",
-	"t_cache_torrents":              "t_cache_torrents is the total count of cached torrents",
-	"t_cache_users":                 "t_cache_users is the total count of cached users",
-	"t_cache_peers":                 "t_cache_peers is the total count of cached peers",
+	"t_cache_torrents":              "t_cache_torrents is the current count of cached torrents",
+	"t_cache_users":                 "t_cache_users is the current count of cached users",
+	"t_cache_peers":                 "t_cache_peers is the current count of cached peers",
 	"t_ann_total":                   "t_ann_total is the total count of announces",
-	"t_ann_status_ok":               "t_ann_status_ok is the total count of successful announces",
-	"t_ann_status_unauthorized":     "t_ann_status_unauthorized is the total count of unauthorized users requests",
-	"t_ann_status_invalid_infohash": "t_ann_status_invalid_infohash is the total count of invalid info hash requests",
-	"t_ann_status_malformed":        "t_ann_status_malformed is the total count of malformed queries",
-	"t_ann_time_ns":                 "t_ann_time_ns is the average time it takes to fulfill a successful announce in nanoseconds",
+	"t_ann_status_total":            "t_ann_status_total is the total count of announces by response status",
+	"t_ann_client_spoofed_total":    "t_ann_client_spoofed_total is the total count of announces where the peer_id client does not match the user agent",
+	"t_ann_client_total":            "t_ann_client_total is the total count of announces per decoded peer_id client",
+	"t_ann_latency_seconds":         "t_ann_latency_seconds is the time it takes to fulfill a successful announce",
+	"t_scrape_total":                "t_scrape_total is the total count of scrapes",
+	"t_scrape_latency_seconds":      "t_scrape_latency_seconds is the time it takes to fulfill a successful scrape",
+	"t_events_total":                "t_events_total is the total count of tracker events published by type",
+	"t_events_dropped_total":        "t_events_dropped_total is the total count of events dropped because a subscriber fell behind",
+	"t_store_sync_duration_seconds": "t_store_sync_duration_seconds is the time taken to write a batch of dirty users, torrents or series to the store",
+	"t_store_sync_errors_total":     "t_store_sync_errors_total is the total count of failed user, torrent or series store syncs",
+	"t_swarm_size":                  "t_swarm_size is the distribution of the current number of peers in each swarm",
+	"t_store_info":                  "t_store_info is always 1, labelled with the name of the store driver",
+	"t_store_stat":                  "t_store_stat holds the internal statistics reported by the store driver, such as connection pool sizes",
+	"pause_total":                   "pause_total is the cumulative milliseconds in GC stop-the-world pauses since the program started.",
+	"go_routines":                   "go_routines is the number of goroutines that currently exist.",
 }
 
 var (
-	TorrentsTotalCached int64
-	PeersTotalCached    int64
-	UsersTotalCached    int64
-
 	AnnounceTotal                 int64
 	AnnounceStatusOK              int64
 	AnnounceStatusUnauthorized    int64
 	AnnounceStatusInvalidInfoHash int64
 	AnnounceStatusMalformed       int64
-	execLock                      *sync.Mutex
-	AnnounceExecTimesNs           []int64
+	AnnounceStatusThrottled       int64
+	AnnounceStatusError           int64
+	AnnounceClientSpoofed         int64
+	ScrapeTotal                   int64
+	EventsDropped                 int64
+
+	// AnnounceLatency is the time taken by successful announces
+	AnnounceLatency = NewHistogram(LatencyBuckets)
+	// ScrapeLatency is the time taken by successful scrapes
+	ScrapeLatency = NewHistogram(LatencyBuckets)
+
+	clientLock      *sync.Mutex
+	announceClients map[string]int64
+
+	eventLock   *sync.Mutex
+	eventCounts map[string]int64
+
+	// StoreSyncKinds are the kinds of data synced to the store by the tracker, they are always
+	// reported even before their first sync
+	StoreSyncKinds = []string{"users", "torrents", "series"}
+
+	syncLock     *sync.Mutex
+	syncDuration map[string]*Histogram
+	syncErrors   map[string]int64
+
+	collectorsLock *sync.RWMutex
+	collectors     []Collector
 )
 
-func AddAnnounceTime(t int64) {
-	execLock.Lock()
-	AnnounceExecTimesNs = append(AnnounceExecTimesNs, t)
-	execLock.Unlock()
+// Collector is called by Get to fill in the values which are computed on demand, such as the
+// number of cached torrents
+type Collector func(m *RuntimeMetrics)
+
+// RegisterCollector adds a Collector which will be called each time the metrics are read
+func RegisterCollector(c Collector) {
+	collectorsLock.Lock()
+	collectors = append(collectors, c)
+	collectorsLock.Unlock()
+}
+
+// AddAnnounceClient increments the announce count for the decoded client name. Clients which
+// could not be decoded are all counted as Unknown, their names include the raw peer_id prefix
+// which would otherwise add a new label for every random peer_id.
+func AddAnnounceClient(name string) {
+	if strings.HasPrefix(name, store.ClientUnknown) {
+		name = store.ClientUnknown
+	}
+	clientLock.Lock()
+	announceClients[name]++
+	clientLock.Unlock()
 }
 
-func avgExecTime() int64 {
-	execLock.Lock()
-	var t int64
-	var avg int64
-	for _, v := range AnnounceExecTimesNs {
-		t += v
+func announceClientCounts() map[string]int64 {
+	clientLock.Lock()
+	clients := make(map[string]int64, len(announceClients))
+	for name, count := range announceClients {
+		clients[name] = count
 	}
-	s := int64(len(AnnounceExecTimesNs))
-	if s > 0 {
-		avg = t / s
-		AnnounceExecTimesNs = nil
+	clientLock.Unlock()
+	return clients
+}
+
+// AddEvent increments the count of the tracker event type, eg: peer_completed
+func AddEvent(name string) {
+	eventLock.Lock()
+	eventCounts[name]++
+	eventLock.Unlock()
+}
+
+func eventTypeCounts() map[string]int64 {
+	eventLock.Lock()
+	counts := make(map[string]int64, len(eventCounts))
+	for name, count := range eventCounts {
+		counts[name] = count
 	}
-	execLock.Unlock()
-	return avg
+	eventLock.Unlock()
+	return counts
 }
 
+// ObserveStoreSync records the duration and result of writing a batch of the kind, eg: users, to the store
+func ObserveStoreSync(kind string, start time.Time, err error) {
+	syncLock.Lock()
+	h, found := syncDuration[kind]
+	if !found {
+		h = NewHistogram(SyncBuckets)
+		syncDuration[kind] = h
+	}
+	if err != nil {
+		syncErrors[kind]++
+	}
+	syncLock.Unlock()
+	h.ObserveSince(start)
+}
+
+func storeSyncSnapshots() (map[string]HistogramSnapshot, map[string]int64) {
+	syncLock.Lock()
+	defer syncLock.Unlock()
+	durations := make(map[string]HistogramSnapshot, len(syncDuration))
+	errs := make(map[string]int64, len(syncDuration))
+	for kind, h := range syncDuration {
+		durations[kind] = h.Snapshot()
+		errs[kind] = syncErrors[kind]
+	}
+	return durations, errs
+}
+
+// RuntimeMetrics is a snapshot of the tracker and go runtime metrics. The counters are
+// totals since the tracker was started.
+//
+// The prom tags set the metric name and type used when rendering with String. Fields with
+// a prom_label and a prom_label_value are samples of the same metric, map fields use the
+// key as the label value.
 type RuntimeMetrics struct {
-	TorrentsTotalCached           int64 `prom:"t_cache_torrents" prom_type:"counter"`
-	UsersTotalCached              int64 `prom:"t_cache_users" prom_type:"counter"`
-	PeersTotalCached              int64 `prom:"t_cache_peers" prom_type:"counter"`
-	AnnounceTotal                 int64 `prom:"t_ann_total" prom_type:"gauge"`
-	AnnounceStatusOK              int64 `prom:"t_ann_status_ok" prom_type:"gauge"`
-	AnnounceStatusUnauthorized    int64 `prom:"t_ann_status_unauthorized" prom_type:"gauge"`
-	AnnounceStatusInvalidInfoHash int64 `prom:"t_ann_status_invalid_infohash" prom_type:"gauge"`
-	AnnounceStatusMalformed       int64 `prom:"t_ann_status_malformed" prom_type:"gauge"`
-	AnnounceExecTimesNsAvg        int64 `prom:"t_ann_time_ns" prom_type:"gauge"`
+	TorrentsTotalCached           int64 `prom:"t_cache_torrents" prom_type:"gauge"`
+	UsersTotalCached              int64 `prom:"t_cache_users" prom_type:"gauge"`
+	PeersTotalCached              int64 `prom:"t_cache_peers" prom_type:"gauge"`
+	AnnounceTotal                 int64 `prom:"t_ann_total" prom_type:"counter"`
+	AnnounceStatusOK              int64 `prom:"t_ann_status_total" prom_type:"counter" prom_label:"status" prom_label_value:"ok"`
+	AnnounceStatusUnauthorized    int64 `prom:"t_ann_status_total" prom_type:"counter" prom_label:"status" prom_label_value:"unauthorized"`
+	AnnounceStatusInvalidInfoHash int64 `prom:"t_ann_status_total" prom_type:"counter" prom_label:"status" prom_label_value:"invalid_infohash"`
+	AnnounceStatusMalformed       int64 `prom:"t_ann_status_total" prom_type:"counter" prom_label:"status" prom_label_value:"malformed"`
+	AnnounceStatusThrottled       int64 `prom:"t_ann_status_total" prom_type:"counter" prom_label:"status" prom_label_value:"throttled"`
+	AnnounceStatusError           int64 `prom:"t_ann_status_total" prom_type:"counter" prom_label:"status" prom_label_value:"error"`
+	AnnounceClientSpoofed         int64 `prom:"t_ann_client_spoofed_total" prom_type:"counter"`
+	// AnnounceClients is keyed by the client name decoded from the peer_id
+	AnnounceClients map[string]int64  `prom:"t_ann_client_total" prom_type:"counter" prom_label:"client"`
+	AnnounceLatency HistogramSnapshot `prom:"t_ann_latency_seconds" prom_type:"histogram"`
+	ScrapeTotal     int64             `prom:"t_scrape_total" prom_type:"counter"`
+	ScrapeLatency   HistogramSnapshot `prom:"t_scrape_latency_seconds" prom_type:"histogram"`
+	// Events is keyed by the event type name, eg: peer_completed
+	Events        map[string]int64 `prom:"t_events_total" prom_type:"counter" prom_label:"type"`
+	EventsDropped int64            `prom:"t_events_dropped_total" prom_type:"counter"`
+
+	// StoreSyncDuration and StoreSyncErrors are keyed by the kind of data synced, eg: users
+	StoreSyncDuration map[string]HistogramSnapshot `prom:"t_store_sync_duration_seconds" prom_type:"histogram" prom_label:"kind"`
+	StoreSyncErrors   map[string]int64             `prom:"t_store_sync_errors_total" prom_type:"counter" prom_label:"kind"`
+	SwarmSizes        HistogramSnapshot            `prom:"t_swarm_size" prom_type:"histogram"`
+	StoreDriver       string                       `prom:"t_store_info" prom_type:"gauge" prom_label:"driver"`
+	// StoreStats are reported by drivers implementing store.StatsProvider
+	StoreStats map[string]float64 `prom:"t_store_stat" prom_type:"gauge" prom_label:"stat"`
 
 	// GC stats
-	NumGC      int64 `prom:"num_gc" prom_type:"gauge"`
-	PauseTotal int64 `prom:"pause_total" prom_type:"gauge"`
+	NumGC      int64 `prom:"num_gc" prom_type:"counter"`
+	PauseTotal int64 `prom:"pause_total" prom_type:"counter"`
 
 	// Goro stats
 	GoRoutines int `prom:"go_routines" prom_type:"gauge"`
@@ -111,7 +227,7 @@ type RuntimeMetrics struct {
 	AllocHeap      uint64  `prom:"alloc_heap" prom_type:"gauge"`
 	AllocTotal     uint64  `prom:"alloc_total" prom_type:"counter"`
 	MemSys         uint64  `prom:"mem_sys" prom_type:"gauge"`
-	Mallocs        uint64  `prom:"mallocs" prom_type:"gauge"`
+	Mallocs        uint64  `prom:"mallocs" prom_type:"counter"`
 	Frees          uint64  `prom:"frees" prom_type:"counter"`
 	HeapSys        uint64  `prom:"heap_sys" prom_type:"gauge"`
 	HeapIdle       uint64  `prom:"heap_idle" prom_type:"gauge"`
@@ -129,28 +245,101 @@ type RuntimeMetrics struct {
 	OtherSys       uint64  `prom:"other_sys" prom_type:"gauge"`
 	GCNext         uint64  `prom:"gc_next" prom_type:"gauge"`
 	GCLast         uint64  `prom:"gc_last" prom_type:"gauge"`
-	GCPauseTotalNS uint64  `prom:"gc_pause_total_ns" prom_type:"gauge"`
-	GCPauseNS      uint64  `prom:"gc_pause_ns" prom_type:""`
-	GCPauseEnd     uint64  `prom:"gc_pause_end" prom_type:""`
-	GCNum          uint32  `prom:"gc_num" prom_type:""`
-	GCNumForced    uint32  `prom:"gc_num_forced" prom_type:""`
+	GCPauseTotalNS uint64  `prom:"gc_pause_total_ns" prom_type:"counter"`
+	GCPauseNS      uint64  `prom:"gc_pause_ns" prom_type:"gauge"`
+	GCPauseEnd     uint64  `prom:"gc_pause_end" prom_type:"gauge"`
+	GCNum          uint32  `prom:"gc_num" prom_type:"counter"`
+	GCNumForced    uint32  `prom:"gc_num_forced" prom_type:"counter"`
 	GCCPUFraction  float64 `prom:"gc_cpu_fraction" prom_type:"gauge"`
 }
 
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// writeHistogram writes the bucket, sum and count samples. labels are any additional
+// labels, eg: kind="users"
+func writeHistogram(out *strings.Builder, name string, labels string, h HistogramSnapshot) {
+	prefix := ""
+	if labels != "" {
+		prefix = labels + ","
+	}
+	for i, b := range h.Buckets {
+		out.WriteString(fmt.Sprintf("%s_bucket{%sle=\"%s\"} %d\n", name, prefix, formatFloat(b), h.Counts[i]))
+	}
+	out.WriteString(fmt.Sprintf("%s_bucket{%sle=\"+Inf\"} %d\n", name, prefix, h.Count))
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	out.WriteString(fmt.Sprintf("%s_sum%s %s\n", name, labels, formatFloat(h.Sum)))
+	out.WriteString(fmt.Sprintf("%s_count%s %d\n", name, labels, h.Count))
+}
+
+// String renders the metrics in the prometheus text exposition format
 func (m RuntimeMetrics) String() string {
 	var out strings.Builder
+	written := make(map[string]bool)
 	v := reflect.ValueOf(m)
 	t := v.Type()
 	for i := 0; i < v.NumField(); i++ {
 		field := t.Field(i)
 		tagKey := field.Tag.Get("prom")
-		out.WriteString(fmt.Sprintf("# HELP %s %s\n", tagKey, promHelp[tagKey]))
-		out.WriteString(fmt.Sprintf("# TYPE %s %s\n", tagKey, field.Tag.Get("prom_type")))
-		out.WriteString(fmt.Sprintf("%s %v\n", tagKey, v.Field(i).Interface()))
+		if tagKey == "" {
+			continue
+		}
+		label := field.Tag.Get("prom_label")
+		if !written[tagKey] {
+			// Samples sharing a name, such as the labelled statuses, only have a single header
+			written[tagKey] = true
+			promType := field.Tag.Get("prom_type")
+			if promType == "" {
+				promType = "untyped"
+			}
+			out.WriteString(fmt.Sprintf("# HELP %s %s\n", tagKey, promHelp[tagKey]))
+			out.WriteString(fmt.Sprintf("# TYPE %s %s\n", tagKey, promType))
+		}
+		switch value := v.Field(i).Interface().(type) {
+		case HistogramSnapshot:
+			writeHistogram(&out, tagKey, "", value)
+		case map[string]HistogramSnapshot:
+			keys := make([]string, 0, len(value))
+			for k := range value {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				writeHistogram(&out, tagKey, fmt.Sprintf("%s=%q", label, k), value[k])
+			}
+		case string:
+			// Info style metrics carry their value as a label
+			if value != "" {
+				out.WriteString(fmt.Sprintf("%s{%s=%q} 1\n", tagKey, label, value))
+			}
+		default:
+			if field.Type.Kind() == reflect.Map {
+				// Map fields are written as one labelled sample per key
+				keys := v.Field(i).MapKeys()
+				sort.Slice(keys, func(a, b int) bool {
+					return keys[a].String() < keys[b].String()
+				})
+				for _, k := range keys {
+					out.WriteString(fmt.Sprintf("%s{%s=%q} %v\n", tagKey, label, k.String(),
+						v.Field(i).MapIndex(k).Interface()))
+				}
+				continue
+			}
+			if labelValue := field.Tag.Get("prom_label_value"); labelValue != "" {
+				out.WriteString(fmt.Sprintf("%s{%s=%q} %v\n", tagKey, label, labelValue, value))
+				continue
+			}
+			out.WriteString(fmt.Sprintf("%s %v\n", tagKey, value))
+		}
 	}
 	return out.String()
 }
 
+// Get returns a snapshot of the current metrics. Reading the metrics does not reset any of
+// the counters so it is safe to call from multiple consumers.
 func Get() RuntimeMetrics {
 	var (
 		mem runtime.MemStats
@@ -160,15 +349,22 @@ func Get() RuntimeMetrics {
 	debug.ReadGCStats(&gc)
 	var m RuntimeMetrics
 
-	m.TorrentsTotalCached = atomic.LoadInt64(&TorrentsTotalCached)
-	m.UsersTotalCached = atomic.LoadInt64(&UsersTotalCached)
-	m.PeersTotalCached = atomic.LoadInt64(&PeersTotalCached)
-	m.AnnounceTotal = atomic.SwapInt64(&AnnounceTotal, 0)
-	m.AnnounceStatusOK = atomic.SwapInt64(&AnnounceStatusOK, 0)
-	m.AnnounceStatusUnauthorized = atomic.SwapInt64(&AnnounceStatusUnauthorized, 0)
-	m.AnnounceStatusInvalidInfoHash = atomic.SwapInt64(&AnnounceStatusInvalidInfoHash, 0)
-	m.AnnounceStatusMalformed = atomic.SwapInt64(&AnnounceStatusMalformed, 0)
-	m.AnnounceExecTimesNsAvg = avgExecTime()
+	m.AnnounceTotal = atomic.LoadInt64(&AnnounceTotal)
+	m.AnnounceStatusOK = atomic.LoadInt64(&AnnounceStatusOK)
+	m.AnnounceStatusUnauthorized = atomic.LoadInt64(&AnnounceStatusUnauthorized)
+	m.AnnounceStatusInvalidInfoHash = atomic.LoadInt64(&AnnounceStatusInvalidInfoHash)
+	m.AnnounceStatusMalformed = atomic.LoadInt64(&AnnounceStatusMalformed)
+	m.AnnounceStatusThrottled = atomic.LoadInt64(&AnnounceStatusThrottled)
+	m.AnnounceStatusError = atomic.LoadInt64(&AnnounceStatusError)
+	m.AnnounceClientSpoofed = atomic.LoadInt64(&AnnounceClientSpoofed)
+	m.AnnounceClients = announceClientCounts()
+	m.AnnounceLatency = AnnounceLatency.Snapshot()
+	m.ScrapeTotal = atomic.LoadInt64(&ScrapeTotal)
+	m.ScrapeLatency = ScrapeLatency.Snapshot()
+	m.Events = eventTypeCounts()
+	m.EventsDropped = atomic.LoadInt64(&EventsDropped)
+	m.StoreSyncDuration, m.StoreSyncErrors = storeSyncSnapshots()
+	m.SwarmSizes = NewHistogramSnapshot(SwarmBuckets, nil)
 	m.NumGC = gc.NumGC
 	m.PauseTotal = gc.PauseTotal.Milliseconds()
 
@@ -186,6 +382,7 @@ func Get() RuntimeMetrics {
 	m.StackSys = mem.StackSys
 	m.MSpanInUse = mem.MSpanInuse
 	m.MSpanSys = mem.MSpanSys
+	m.MCacheInUse = mem.MCacheInuse
 	m.MCacheSys = mem.MCacheSys
 	m.BuckHashSys = mem.BuckHashSys
 	m.GCSys = mem.GCSys
@@ -201,9 +398,33 @@ func Get() RuntimeMetrics {
 
 	m.GoRoutines = runtime.NumGoroutine()
 
+	collectorsLock.RLock()
+	for _, c := range collectors {
+		c(&m)
+	}
+	collectorsLock.RUnlock()
+
 	return m
 }
 
+// Handler serves the metrics in the prometheus text exposition format
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = io.WriteString(w, Get().String())
+	})
+}
+
 func init() {
-	execLock = &sync.Mutex{}
+	clientLock = &sync.Mutex{}
+	announceClients = make(map[string]int64)
+	eventLock = &sync.Mutex{}
+	eventCounts = make(map[string]int64)
+	syncLock = &sync.Mutex{}
+	syncDuration = make(map[string]*Histogram)
+	syncErrors = make(map[string]int64)
+	for _, kind := range StoreSyncKinds {
+		syncDuration[kind] = NewHistogram(SyncBuckets)
+	}
+	collectorsLock = &sync.RWMutex{}
 }
@@ -1,8 +1,12 @@
 package metrics
 
 import (
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestMetrics_String(t *testing.T) {
@@ -11,3 +15,81 @@ func TestMetrics_String(t *testing.T) {
 	s := m.String()
 	require.True(t, len(s) > 100)
 }
+
+func TestAddAnnounceClient(t *testing.T) {
+	AddAnnounceClient("qBittorrent 4.3.3.0")
+	AddAnnounceClient("qBittorrent 4.3.3.0")
+	AddAnnounceClient("Unknown (-ZZ1234-)")
+	AddAnnounceClient("Unknown (-YY9876-)")
+	m := Get()
+	require.Equal(t, int64(2), m.AnnounceClients["qBittorrent 4.3.3.0"])
+	s := m.String()
+	require.Contains(t, s, `t_ann_client_total{client="qBittorrent 4.3.3.0"} 2`)
+	require.Contains(t, s, `t_ann_client_total{client="Unknown"} 2`)
+	require.NotContains(t, m.AnnounceClients, "Unknown (-ZZ1234-)")
+	// Counters are not reset when read
+	require.Equal(t, int64(2), Get().AnnounceClients["qBittorrent 4.3.3.0"])
+}
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram([]float64{1, 5, 10})
+	for _, v := range []float64{0.5, 1, 3, 7, 20} {
+		h.Observe(v)
+	}
+	s := h.Snapshot()
+	require.Equal(t, []uint64{2, 3, 4}, s.Counts)
+	require.Equal(t, uint64(5), s.Count)
+	require.Equal(t, 31.5, s.Sum)
+	require.Equal(t, 1.0, s.Quantile(0.4))
+	require.Equal(t, 3.0, s.Quantile(0.5))
+	require.Equal(t, 10.0, s.Quantile(0.99), "observations above the largest bucket use its upper bound")
+	require.Equal(t, 0.0, HistogramSnapshot{Buckets: []float64{1}, Counts: []uint64{0}}.Quantile(0.5))
+
+	h.Observe(0.5)
+	h.Observe(8)
+	d := h.Snapshot().Sub(s)
+	require.Equal(t, []uint64{1, 1, 2}, d.Counts)
+	require.Equal(t, uint64(2), d.Count)
+	require.Equal(t, 8.5, d.Sum)
+	require.Equal(t, 1.0, d.Quantile(0.5))
+
+	s = NewHistogramSnapshot([]float64{0, 1}, []float64{0, 0, 1, 2})
+	require.Equal(t, []uint64{2, 3}, s.Counts)
+	require.Equal(t, uint64(4), s.Count)
+}
+
+func TestPrometheusFormat(t *testing.T) {
+	ObserveStoreSync("users", time.Now(), nil)
+	ObserveStoreSync("users", time.Now(), errors.New("failed"))
+	RegisterCollector(func(m *RuntimeMetrics) {
+		m.StoreDriver = "test"
+		m.SwarmSizes = NewHistogramSnapshot(SwarmBuckets, []float64{0, 3})
+	})
+	s := Get().String()
+	for _, expected := range []string{
+		"# TYPE t_ann_total counter\n",
+		"# TYPE t_ann_status_total counter\n",
+		`t_ann_status_total{status="ok"} `,
+		`t_ann_status_total{status="throttled"} `,
+		"# TYPE t_ann_latency_seconds histogram\n",
+		`t_ann_latency_seconds_bucket{le="0.0001"} `,
+		`t_ann_latency_seconds_bucket{le="+Inf"} `,
+		"t_ann_latency_seconds_count ",
+		`t_store_sync_duration_seconds_bucket{kind="users",le="+Inf"} 2`,
+		`t_store_sync_duration_seconds_count{kind="users"} 2`,
+		`t_store_sync_errors_total{kind="users"} 1`,
+		`t_store_sync_errors_total{kind="series"} 0`,
+		`t_store_sync_duration_seconds_count{kind="torrents"} 0`,
+		`t_swarm_size_bucket{le="0"} 1`,
+		`t_swarm_size_bucket{le="5"} 2`,
+		`t_store_info{driver="test"} 1`,
+	} {
+		require.Contains(t, s, expected)
+	}
+	require.Equal(t, 1, strings.Count(s, "# TYPE t_ann_status_total counter"), "labelled samples share a single header")
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	require.Contains(t, w.Header().Get("Content-Type"), "version=0.0.4")
+	require.Contains(t, w.Body.String(), "t_ann_total ")
+}
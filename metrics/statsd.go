@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"net"
+	"strings"
+)
+
+const (
+	// TagFormatDogStatsD appends tags to each line as |#key:value,key:value
+	TagFormatDogStatsD = "dogstatsd"
+	// TagFormatInfluxDB adds tags to the metric name as name,key=value,key=value as read by telegraf
+	TagFormatInfluxDB = "influxdb"
+	// TagFormatNone does not send tags, label values are appended to the name instead, eg: t_ann_status_total.ok
+	TagFormatNone = "none"
+)
+
+// statsdPacketSize is the largest payload sent in a single datagram, chosen to fit within
+// the MTU of most networks
+const statsdPacketSize = 1432
+
+var statsdReplacer = strings.NewReplacer(":", "_", "|", "_", "@", "_", ",", "_", "#", "_",
+	"=", "_", " ", "_", "\n", "_")
+
+// StatsD sends metrics to a statsd server over UDP. Gauges are sent as their current value and
+// counters as the change since the previous export.
+type StatsD struct {
+	conn      net.Conn
+	prefix    string
+	tagFormat string
+	tags      map[string]string
+	// prev holds the counter values of the previous export
+	prev map[string]float64
+}
+
+// NewStatsD creates an exporter sending to the statsd server at address, eg: localhost:8125
+func NewStatsD(address string, prefix string, tagFormat string, tags map[string]string) (*StatsD, error) {
+	if address == "" {
+		return nil, errors.New("statsd address cannot be empty")
+	}
+	switch tagFormat {
+	case "":
+		tagFormat = TagFormatDogStatsD
+	case TagFormatDogStatsD, TagFormatInfluxDB, TagFormatNone:
+	default:
+		return nil, errors.Errorf("unknown statsd tag format: %s", tagFormat)
+	}
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to connect to statsd")
+	}
+	return &StatsD{
+		conn:      conn,
+		prefix:    prefix,
+		tagFormat: tagFormat,
+		tags:      tags,
+		prev:      make(map[string]float64),
+	}, nil
+}
+
+// line formats a single sample
+func (s *StatsD) line(sample Sample, value float64, metricType string) string {
+	tags := s.tags
+	if sample.Label != "" {
+		tags = mergeTags(s.tags, map[string]string{sample.Label: sample.LabelValue})
+	}
+	var b strings.Builder
+	b.WriteString(statsdReplacer.Replace(s.prefix + sample.Name))
+	switch s.tagFormat {
+	case TagFormatNone:
+		if sample.Label != "" {
+			b.WriteString("." + statsdReplacer.Replace(sample.LabelValue))
+		}
+	case TagFormatInfluxDB:
+		for _, k := range sortedKeys(tags) {
+			b.WriteString("," + statsdReplacer.Replace(k) + "=" + statsdReplacer.Replace(tags[k]))
+		}
+	}
+	b.WriteString(":" + formatFloat(value) + "|" + metricType)
+	if s.tagFormat == TagFormatDogStatsD && len(tags) > 0 {
+		for i, k := range sortedKeys(tags) {
+			if i == 0 {
+				b.WriteString("|#")
+			} else {
+				b.WriteString(",")
+			}
+			b.WriteString(statsdReplacer.Replace(k) + ":" + statsdReplacer.Replace(tags[k]))
+		}
+	}
+	return b.String()
+}
+
+// Export sends the snapshot, split over as many datagrams as required
+func (s *StatsD) Export(_ context.Context, m RuntimeMetrics) error {
+	var (
+		packet strings.Builder
+		lines  []string
+	)
+	for _, sample := range m.Samples() {
+		if !sample.Counter {
+			lines = append(lines, s.line(sample, sample.Value, "g"))
+			continue
+		}
+		key := sample.key()
+		delta := sample.Value - s.prev[key]
+		s.prev[key] = sample.Value
+		// Unchanged counters are left out, statsd treats them as 0 for the flush interval
+		if delta != 0 {
+			lines = append(lines, s.line(sample, delta, "c"))
+		}
+	}
+	for _, l := range lines {
+		if packet.Len() > 0 && packet.Len()+len(l)+1 > statsdPacketSize {
+			if _, err := s.conn.Write([]byte(packet.String())); err != nil {
+				return errors.Wrap(err, "Failed to write statsd packet")
+			}
+			packet.Reset()
+		}
+		if packet.Len() > 0 {
+			packet.WriteString("\n")
+		}
+		packet.WriteString(l)
+	}
+	if packet.Len() > 0 {
+		if _, err := s.conn.Write([]byte(packet.String())); err != nil {
+			return errors.Wrap(err, "Failed to write statsd packet")
+		}
+	}
+	return nil
+}
+
+// Close closes the UDP socket
+func (s *StatsD) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,601 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.14.0
+// source: proto/metrics.proto
+
+package rpc
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// RuntimeMetrics mirrors metrics.RuntimeMetrics
+type RuntimeMetrics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TorrentsTotalCached           int64 `protobuf:"varint,1,opt,name=torrents_total_cached,json=torrentsTotalCached,proto3" json:"torrents_total_cached,omitempty"`
+	UsersTotalCached              int64 `protobuf:"varint,2,opt,name=users_total_cached,json=usersTotalCached,proto3" json:"users_total_cached,omitempty"`
+	PeersTotalCached              int64 `protobuf:"varint,3,opt,name=peers_total_cached,json=peersTotalCached,proto3" json:"peers_total_cached,omitempty"`
+	AnnounceTotal                 int64 `protobuf:"varint,4,opt,name=announce_total,json=announceTotal,proto3" json:"announce_total,omitempty"`
+	AnnounceStatusOk              int64 `protobuf:"varint,5,opt,name=announce_status_ok,json=announceStatusOk,proto3" json:"announce_status_ok,omitempty"`
+	AnnounceStatusUnauthorized    int64 `protobuf:"varint,6,opt,name=announce_status_unauthorized,json=announceStatusUnauthorized,proto3" json:"announce_status_unauthorized,omitempty"`
+	AnnounceStatusInvalidInfoHash int64 `protobuf:"varint,7,opt,name=announce_status_invalid_info_hash,json=announceStatusInvalidInfoHash,proto3" json:"announce_status_invalid_info_hash,omitempty"`
+	AnnounceStatusMalformed       int64 `protobuf:"varint,8,opt,name=announce_status_malformed,json=announceStatusMalformed,proto3" json:"announce_status_malformed,omitempty"`
+	AnnounceStatusThrottled       int64 `protobuf:"varint,9,opt,name=announce_status_throttled,json=announceStatusThrottled,proto3" json:"announce_status_throttled,omitempty"`
+	AnnounceStatusError           int64 `protobuf:"varint,26,opt,name=announce_status_error,json=announceStatusError,proto3" json:"announce_status_error,omitempty"`
+	AnnounceClientSpoofed         int64 `protobuf:"varint,10,opt,name=announce_client_spoofed,json=announceClientSpoofed,proto3" json:"announce_client_spoofed,omitempty"`
+	// Keyed by the client name decoded from the peer_id
+	AnnounceClients map[string]int64 `protobuf:"bytes,11,rep,name=announce_clients,json=announceClients,proto3" json:"announce_clients,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	EventsDropped   int64            `protobuf:"varint,12,opt,name=events_dropped,json=eventsDropped,proto3" json:"events_dropped,omitempty"`
+	NumGc           int64            `protobuf:"varint,14,opt,name=num_gc,json=numGc,proto3" json:"num_gc,omitempty"`
+	PauseTotal      int64            `protobuf:"varint,15,opt,name=pause_total,json=pauseTotal,proto3" json:"pause_total,omitempty"`
+	GoRoutines      int64            `protobuf:"varint,16,opt,name=go_routines,json=goRoutines,proto3" json:"go_routines,omitempty"`
+	AllocHeap       uint64           `protobuf:"varint,17,opt,name=alloc_heap,json=allocHeap,proto3" json:"alloc_heap,omitempty"`
+	AllocTotal      uint64           `protobuf:"varint,18,opt,name=alloc_total,json=allocTotal,proto3" json:"alloc_total,omitempty"`
+	MemSys          uint64           `protobuf:"varint,19,opt,name=mem_sys,json=memSys,proto3" json:"mem_sys,omitempty"`
+	HeapInUse       uint64           `protobuf:"varint,20,opt,name=heap_in_use,json=heapInUse,proto3" json:"heap_in_use,omitempty"`
+	HeapObjects     uint64           `protobuf:"varint,21,opt,name=heap_objects,json=heapObjects,proto3" json:"heap_objects,omitempty"`
+	GcPauseTotalNs  uint64           `protobuf:"varint,22,opt,name=gc_pause_total_ns,json=gcPauseTotalNs,proto3" json:"gc_pause_total_ns,omitempty"`
+	GcCpuFraction   float64          `protobuf:"fixed64,23,opt,name=gc_cpu_fraction,json=gcCpuFraction,proto3" json:"gc_cpu_fraction,omitempty"`
+	// Latencies are in seconds
+	AnnounceLatency *Histogram `protobuf:"bytes,27,opt,name=announce_latency,json=announceLatency,proto3" json:"announce_latency,omitempty"`
+	ScrapeTotal     int64      `protobuf:"varint,28,opt,name=scrape_total,json=scrapeTotal,proto3" json:"scrape_total,omitempty"`
+	ScrapeLatency   *Histogram `protobuf:"bytes,29,opt,name=scrape_latency,json=scrapeLatency,proto3" json:"scrape_latency,omitempty"`
+	// Keyed by the event type name, eg: peer_completed
+	Events map[string]int64 `protobuf:"bytes,30,rep,name=events,proto3" json:"events,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// Keyed by the kind of data synced, eg: users
+	StoreSyncDuration map[string]*Histogram `protobuf:"bytes,31,rep,name=store_sync_duration,json=storeSyncDuration,proto3" json:"store_sync_duration,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	StoreSyncErrors   map[string]int64      `protobuf:"bytes,32,rep,name=store_sync_errors,json=storeSyncErrors,proto3" json:"store_sync_errors,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	SwarmSizes        *Histogram            `protobuf:"bytes,33,opt,name=swarm_sizes,json=swarmSizes,proto3" json:"swarm_sizes,omitempty"`
+}
+
+func (x *RuntimeMetrics) Reset() {
+	*x = RuntimeMetrics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_metrics_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RuntimeMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuntimeMetrics) ProtoMessage() {}
+
+func (x *RuntimeMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_metrics_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuntimeMetrics.ProtoReflect.Descriptor instead.
+func (*RuntimeMetrics) Descriptor() ([]byte, []int) {
+	return file_proto_metrics_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RuntimeMetrics) GetTorrentsTotalCached() int64 {
+	if x != nil {
+		return x.TorrentsTotalCached
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetUsersTotalCached() int64 {
+	if x != nil {
+		return x.UsersTotalCached
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetPeersTotalCached() int64 {
+	if x != nil {
+		return x.PeersTotalCached
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetAnnounceTotal() int64 {
+	if x != nil {
+		return x.AnnounceTotal
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetAnnounceStatusOk() int64 {
+	if x != nil {
+		return x.AnnounceStatusOk
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetAnnounceStatusUnauthorized() int64 {
+	if x != nil {
+		return x.AnnounceStatusUnauthorized
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetAnnounceStatusInvalidInfoHash() int64 {
+	if x != nil {
+		return x.AnnounceStatusInvalidInfoHash
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetAnnounceStatusMalformed() int64 {
+	if x != nil {
+		return x.AnnounceStatusMalformed
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetAnnounceStatusThrottled() int64 {
+	if x != nil {
+		return x.AnnounceStatusThrottled
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetAnnounceStatusError() int64 {
+	if x != nil {
+		return x.AnnounceStatusError
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetAnnounceClientSpoofed() int64 {
+	if x != nil {
+		return x.AnnounceClientSpoofed
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetAnnounceClients() map[string]int64 {
+	if x != nil {
+		return x.AnnounceClients
+	}
+	return nil
+}
+
+func (x *RuntimeMetrics) GetEventsDropped() int64 {
+	if x != nil {
+		return x.EventsDropped
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetNumGc() int64 {
+	if x != nil {
+		return x.NumGc
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetPauseTotal() int64 {
+	if x != nil {
+		return x.PauseTotal
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetGoRoutines() int64 {
+	if x != nil {
+		return x.GoRoutines
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetAllocHeap() uint64 {
+	if x != nil {
+		return x.AllocHeap
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetAllocTotal() uint64 {
+	if x != nil {
+		return x.AllocTotal
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetMemSys() uint64 {
+	if x != nil {
+		return x.MemSys
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetHeapInUse() uint64 {
+	if x != nil {
+		return x.HeapInUse
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetHeapObjects() uint64 {
+	if x != nil {
+		return x.HeapObjects
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetGcPauseTotalNs() uint64 {
+	if x != nil {
+		return x.GcPauseTotalNs
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetGcCpuFraction() float64 {
+	if x != nil {
+		return x.GcCpuFraction
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetAnnounceLatency() *Histogram {
+	if x != nil {
+		return x.AnnounceLatency
+	}
+	return nil
+}
+
+func (x *RuntimeMetrics) GetScrapeTotal() int64 {
+	if x != nil {
+		return x.ScrapeTotal
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetScrapeLatency() *Histogram {
+	if x != nil {
+		return x.ScrapeLatency
+	}
+	return nil
+}
+
+func (x *RuntimeMetrics) GetEvents() map[string]int64 {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *RuntimeMetrics) GetStoreSyncDuration() map[string]*Histogram {
+	if x != nil {
+		return x.StoreSyncDuration
+	}
+	return nil
+}
+
+func (x *RuntimeMetrics) GetStoreSyncErrors() map[string]int64 {
+	if x != nil {
+		return x.StoreSyncErrors
+	}
+	return nil
+}
+
+func (x *RuntimeMetrics) GetSwarmSizes() *Histogram {
+	if x != nil {
+		return x.SwarmSizes
+	}
+	return nil
+}
+
+// Histogram mirrors metrics.HistogramSnapshot
+type Histogram struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Upper bounds of each bucket, not including +Inf
+	Buckets []float64 `protobuf:"fixed64,1,rep,packed,name=buckets,proto3" json:"buckets,omitempty"`
+	// Cumulative counts of the observations less than or equal to each bucket
+	Counts []uint64 `protobuf:"varint,2,rep,packed,name=counts,proto3" json:"counts,omitempty"`
+	Count  uint64   `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Sum    float64  `protobuf:"fixed64,4,opt,name=sum,proto3" json:"sum,omitempty"`
+}
+
+func (x *Histogram) Reset() {
+	*x = Histogram{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_metrics_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Histogram) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Histogram) ProtoMessage() {}
+
+func (x *Histogram) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_metrics_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Histogram.ProtoReflect.Descriptor instead.
+func (*Histogram) Descriptor() ([]byte, []int) {
+	return file_proto_metrics_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Histogram) GetBuckets() []float64 {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *Histogram) GetCounts() []uint64 {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+func (x *Histogram) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *Histogram) GetSum() float64 {
+	if x != nil {
+		return x.Sum
+	}
+	return 0
+}
+
+var File_proto_metrics_proto protoreflect.FileDescriptor
+
+var file_proto_metrics_proto_rawDesc = []byte{
+	0x0a, 0x13, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x6d, 0x69, 0x6b, 0x61, 0x22, 0x87, 0x0e, 0x0a, 0x0e,
+	0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x32,
+	0x0a, 0x15, 0x74, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x73, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x5f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x74,
+	0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x73, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x61, 0x63, 0x68,
+	0x65, 0x64, 0x12, 0x2c, 0x0a, 0x12, 0x75, 0x73, 0x65, 0x72, 0x73, 0x5f, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x5f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10,
+	0x75, 0x73, 0x65, 0x72, 0x73, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x61, 0x63, 0x68, 0x65, 0x64,
+	0x12, 0x2c, 0x0a, 0x12, 0x70, 0x65, 0x65, 0x72, 0x73, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f,
+	0x63, 0x61, 0x63, 0x68, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x70, 0x65,
+	0x65, 0x72, 0x73, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x61, 0x63, 0x68, 0x65, 0x64, 0x12, 0x25,
+	0x0a, 0x0e, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65,
+	0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x2c, 0x0a, 0x12, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63,
+	0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x6f, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x10, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x4f, 0x6b, 0x12, 0x40, 0x0a, 0x1c, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x5f,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x75, 0x6e, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69,
+	0x7a, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x1a, 0x61, 0x6e, 0x6e, 0x6f, 0x75,
+	0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x55, 0x6e, 0x61, 0x75, 0x74, 0x68, 0x6f,
+	0x72, 0x69, 0x7a, 0x65, 0x64, 0x12, 0x48, 0x0a, 0x21, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63,
+	0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x69, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x1d, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x49, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x12,
+	0x3a, 0x0a, 0x19, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x5f, 0x6d, 0x61, 0x6c, 0x66, 0x6f, 0x72, 0x6d, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x17, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x4d, 0x61, 0x6c, 0x66, 0x6f, 0x72, 0x6d, 0x65, 0x64, 0x12, 0x3a, 0x0a, 0x19, 0x61,
+	0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x74,
+	0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x17,
+	0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x54, 0x68,
+	0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x12, 0x32, 0x0a, 0x15, 0x61, 0x6e, 0x6e, 0x6f, 0x75,
+	0x6e, 0x63, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x1a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x36, 0x0a, 0x17, 0x61,
+	0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x73,
+	0x70, 0x6f, 0x6f, 0x66, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x15, 0x61, 0x6e,
+	0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x70, 0x6f, 0x6f,
+	0x66, 0x65, 0x64, 0x12, 0x54, 0x0a, 0x10, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x5f,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e,
+	0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x4d, 0x65, 0x74, 0x72,
+	0x69, 0x63, 0x73, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x43, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0f, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e,
+	0x63, 0x65, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x5f, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0d, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x44, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64,
+	0x12, 0x15, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x5f, 0x67, 0x63, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x05, 0x6e, 0x75, 0x6d, 0x47, 0x63, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x61, 0x75, 0x73, 0x65,
+	0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x70, 0x61,
+	0x75, 0x73, 0x65, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x1f, 0x0a, 0x0b, 0x67, 0x6f, 0x5f, 0x72,
+	0x6f, 0x75, 0x74, 0x69, 0x6e, 0x65, 0x73, 0x18, 0x10, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x67,
+	0x6f, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x6c, 0x6c,
+	0x6f, 0x63, 0x5f, 0x68, 0x65, 0x61, 0x70, 0x18, 0x11, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x61,
+	0x6c, 0x6c, 0x6f, 0x63, 0x48, 0x65, 0x61, 0x70, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x6c, 0x6c, 0x6f,
+	0x63, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x12, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x61,
+	0x6c, 0x6c, 0x6f, 0x63, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x65, 0x6d,
+	0x5f, 0x73, 0x79, 0x73, 0x18, 0x13, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6d, 0x65, 0x6d, 0x53,
+	0x79, 0x73, 0x12, 0x1e, 0x0a, 0x0b, 0x68, 0x65, 0x61, 0x70, 0x5f, 0x69, 0x6e, 0x5f, 0x75, 0x73,
+	0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x68, 0x65, 0x61, 0x70, 0x49, 0x6e, 0x55,
+	0x73, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x68, 0x65, 0x61, 0x70, 0x5f, 0x6f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x73, 0x18, 0x15, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x68, 0x65, 0x61, 0x70, 0x4f, 0x62,
+	0x6a, 0x65, 0x63, 0x74, 0x73, 0x12, 0x29, 0x0a, 0x11, 0x67, 0x63, 0x5f, 0x70, 0x61, 0x75, 0x73,
+	0x65, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x6e, 0x73, 0x18, 0x16, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0e, 0x67, 0x63, 0x50, 0x61, 0x75, 0x73, 0x65, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x4e, 0x73,
+	0x12, 0x26, 0x0a, 0x0f, 0x67, 0x63, 0x5f, 0x63, 0x70, 0x75, 0x5f, 0x66, 0x72, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x17, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x67, 0x63, 0x43, 0x70, 0x75,
+	0x46, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3a, 0x0a, 0x10, 0x61, 0x6e, 0x6e, 0x6f,
+	0x75, 0x6e, 0x63, 0x65, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x1b, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x67,
+	0x72, 0x61, 0x6d, 0x52, 0x0f, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x4c, 0x61, 0x74,
+	0x65, 0x6e, 0x63, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x63, 0x72, 0x61, 0x70, 0x65, 0x5f, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x73, 0x63, 0x72, 0x61,
+	0x70, 0x65, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x36, 0x0a, 0x0e, 0x73, 0x63, 0x72, 0x61, 0x70,
+	0x65, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x1d, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d,
+	0x52, 0x0d, 0x73, 0x63, 0x72, 0x61, 0x70, 0x65, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12,
+	0x38, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x1e, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x20, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x4d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x5b, 0x0a, 0x13, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x5f, 0x73, 0x79, 0x6e, 0x63, 0x5f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x1f, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x52, 0x75,
+	0x6e, 0x74, 0x69, 0x6d, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x53, 0x74, 0x6f,
+	0x72, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x11, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x44, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x55, 0x0a, 0x11, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x5f,
+	0x73, 0x79, 0x6e, 0x63, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x20, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x29, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
+	0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x79, 0x6e,
+	0x63, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0f, 0x73, 0x74,
+	0x6f, 0x72, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x30, 0x0a,
+	0x0b, 0x73, 0x77, 0x61, 0x72, 0x6d, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x73, 0x18, 0x21, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x67,
+	0x72, 0x61, 0x6d, 0x52, 0x0a, 0x73, 0x77, 0x61, 0x72, 0x6d, 0x53, 0x69, 0x7a, 0x65, 0x73, 0x1a,
+	0x42, 0x0a, 0x14, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x43, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x1a, 0x39, 0x0a, 0x0b, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x55,
+	0x0a, 0x16, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x44, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x25, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6d, 0x69, 0x6b, 0x61,
+	0x2e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x42, 0x0a, 0x14, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x79,
+	0x6e, 0x63, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x4a, 0x04, 0x08, 0x0d, 0x10, 0x0e, 0x4a,
+	0x04, 0x08, 0x18, 0x10, 0x1a, 0x22, 0x65, 0x0a, 0x09, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72,
+	0x61, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x01, 0x52, 0x07, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x04, 0x52, 0x06, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x75,
+	0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x73, 0x75, 0x6d, 0x42, 0x24, 0x5a, 0x22,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x65, 0x69, 0x67, 0x68,
+	0x6d, 0x61, 0x63, 0x64, 0x6f, 0x6e, 0x61, 0x6c, 0x64, 0x2f, 0x6d, 0x69, 0x6b, 0x61, 0x2f, 0x72,
+	0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_metrics_proto_rawDescOnce sync.Once
+	file_proto_metrics_proto_rawDescData = file_proto_metrics_proto_rawDesc
+)
+
+func file_proto_metrics_proto_rawDescGZIP() []byte {
+	file_proto_metrics_proto_rawDescOnce.Do(func() {
+		file_proto_metrics_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_metrics_proto_rawDescData)
+	})
+	return file_proto_metrics_proto_rawDescData
+}
+
+var file_proto_metrics_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_metrics_proto_goTypes = []interface{}{
+	(*RuntimeMetrics)(nil), // 0: mika.RuntimeMetrics
+	(*Histogram)(nil),      // 1: mika.Histogram
+	nil,                    // 2: mika.RuntimeMetrics.AnnounceClientsEntry
+	nil,                    // 3: mika.RuntimeMetrics.EventsEntry
+	nil,                    // 4: mika.RuntimeMetrics.StoreSyncDurationEntry
+	nil,                    // 5: mika.RuntimeMetrics.StoreSyncErrorsEntry
+}
+var file_proto_metrics_proto_depIdxs = []int32{
+	2, // 0: mika.RuntimeMetrics.announce_clients:type_name -> mika.RuntimeMetrics.AnnounceClientsEntry
+	1, // 1: mika.RuntimeMetrics.announce_latency:type_name -> mika.Histogram
+	1, // 2: mika.RuntimeMetrics.scrape_latency:type_name -> mika.Histogram
+	3, // 3: mika.RuntimeMetrics.events:type_name -> mika.RuntimeMetrics.EventsEntry
+	4, // 4: mika.RuntimeMetrics.store_sync_duration:type_name -> mika.RuntimeMetrics.StoreSyncDurationEntry
+	5, // 5: mika.RuntimeMetrics.store_sync_errors:type_name -> mika.RuntimeMetrics.StoreSyncErrorsEntry
+	1, // 6: mika.RuntimeMetrics.swarm_sizes:type_name -> mika.Histogram
+	1, // 7: mika.RuntimeMetrics.StoreSyncDurationEntry.value:type_name -> mika.Histogram
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_proto_metrics_proto_init() }
+func file_proto_metrics_proto_init() {
+	if File_proto_metrics_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_metrics_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RuntimeMetrics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_metrics_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Histogram); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_metrics_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_proto_metrics_proto_goTypes,
+		DependencyIndexes: file_proto_metrics_proto_depIdxs,
+		MessageInfos:      file_proto_metrics_proto_msgTypes,
+	}.Build()
+	File_proto_metrics_proto = out.File
+	file_proto_metrics_proto_rawDesc = nil
+	file_proto_metrics_proto_goTypes = nil
+	file_proto_metrics_proto_depIdxs = nil
+}
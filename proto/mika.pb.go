@@ -34,82 +34,125 @@ var file_proto_mika_proto_rawDesc = []byte{
 	0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x1a, 0x10, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72, 0x6f, 0x6c, 0x65, 0x2e, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x1a, 0x10, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x32, 0xe2, 0x08, 0x0a, 0x04, 0x4d, 0x69, 0x6b, 0x61, 0x12, 0x3e, 0x0a, 0x09, 0x43,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x41, 0x6c, 0x6c, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
-	0x1a, 0x17, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x41, 0x6c,
-	0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3e, 0x0a, 0x0a, 0x43,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x61, 0x76, 0x65, 0x12, 0x16, 0x2e, 0x6d, 0x69, 0x6b, 0x61,
-	0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x61, 0x76, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d,
-	0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x0c, 0x57,
-	0x68, 0x69, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x64, 0x64, 0x12, 0x0f, 0x2e, 0x6d, 0x69,
-	0x6b, 0x61, 0x2e, 0x57, 0x68, 0x69, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
-	0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x48, 0x0a, 0x0f, 0x57, 0x68, 0x69, 0x74, 0x65, 0x4c,
-	0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x1b, 0x2e, 0x6d, 0x69, 0x6b, 0x61,
-	0x2e, 0x57, 0x68, 0x69, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
-	0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00,
-	0x12, 0x44, 0x0a, 0x0c, 0x57, 0x68, 0x69, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x6c, 0x6c,
-	0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1a, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e,
-	0x57, 0x68, 0x69, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x0a, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e,
-	0x74, 0x41, 0x6c, 0x6c, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0d, 0x2e, 0x6d,
-	0x69, 0x6b, 0x61, 0x2e, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x30, 0x01, 0x12,
-	0x32, 0x0a, 0x0a, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x47, 0x65, 0x74, 0x12, 0x13, 0x2e,
-	0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x49, 0x6e, 0x66, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x50, 0x61, 0x72,
-	0x61, 0x6d, 0x1a, 0x0d, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e,
-	0x74, 0x22, 0x00, 0x12, 0x35, 0x0a, 0x0a, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x41, 0x64,
-	0x64, 0x12, 0x16, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74,
-	0x41, 0x64, 0x64, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x0d, 0x2e, 0x6d, 0x69, 0x6b, 0x61,
-	0x2e, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x12, 0x3e, 0x0a, 0x0d, 0x54, 0x6f,
-	0x72, 0x72, 0x65, 0x6e, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x13, 0x2e, 0x6d, 0x69,
-	0x6b, 0x61, 0x2e, 0x49, 0x6e, 0x66, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x50, 0x61, 0x72, 0x61, 0x6d,
-	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x3b, 0x0a, 0x0d, 0x54, 0x6f,
-	0x72, 0x72, 0x65, 0x6e, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x19, 0x2e, 0x6d, 0x69,
-	0x6b, 0x61, 0x2e, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x0d, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x54, 0x6f,
-	0x72, 0x72, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x12, 0x35, 0x0a, 0x0a, 0x54, 0x6f, 0x72, 0x72, 0x65,
-	0x6e, 0x74, 0x54, 0x6f, 0x70, 0x12, 0x16, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x54, 0x6f, 0x72,
-	0x72, 0x65, 0x6e, 0x74, 0x54, 0x6f, 0x70, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x0d, 0x2e,
-	0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x12, 0x25,
-	0x0a, 0x07, 0x55, 0x73, 0x65, 0x72, 0x47, 0x65, 0x74, 0x12, 0x0c, 0x2e, 0x6d, 0x69, 0x6b, 0x61,
-	0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x1a, 0x0a, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x55,
-	0x73, 0x65, 0x72, 0x22, 0x00, 0x12, 0x31, 0x0a, 0x07, 0x55, 0x73, 0x65, 0x72, 0x41, 0x6c, 0x6c,
-	0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0a, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e,
-	0x55, 0x73, 0x65, 0x72, 0x22, 0x00, 0x30, 0x01, 0x12, 0x30, 0x0a, 0x08, 0x55, 0x73, 0x65, 0x72,
-	0x53, 0x61, 0x76, 0x65, 0x12, 0x16, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x55, 0x73, 0x65, 0x72,
-	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x0a, 0x2e, 0x6d,
-	0x69, 0x6b, 0x61, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x22, 0x00, 0x12, 0x34, 0x0a, 0x0a, 0x55, 0x73,
-	0x65, 0x72, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x0c, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e,
-	0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00,
-	0x12, 0x2c, 0x0a, 0x07, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x12, 0x13, 0x2e, 0x6d, 0x69,
-	0x6b, 0x61, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73,
-	0x1a, 0x0a, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x22, 0x00, 0x12, 0x31,
-	0x0a, 0x07, 0x52, 0x6f, 0x6c, 0x65, 0x41, 0x6c, 0x6c, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
-	0x79, 0x1a, 0x0a, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x22, 0x00, 0x30,
-	0x01, 0x12, 0x2c, 0x0a, 0x07, 0x52, 0x6f, 0x6c, 0x65, 0x41, 0x64, 0x64, 0x12, 0x13, 0x2e, 0x6d,
-	0x69, 0x6b, 0x61, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x41, 0x64, 0x64, 0x50, 0x61, 0x72, 0x61, 0x6d,
-	0x73, 0x1a, 0x0a, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x22, 0x00, 0x12,
-	0x34, 0x0a, 0x0a, 0x52, 0x6f, 0x6c, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x0c, 0x2e,
-	0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x49, 0x44, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x11, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x12, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f,
+	0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x13, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x12, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x65, 0x72, 0x69, 0x65, 0x73, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x11, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x61, 0x75, 0x64,
+	0x69, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0xab, 0x0d, 0x0a, 0x04, 0x4d, 0x69, 0x6b, 0x61, 0x12, 0x3e,
+	0x0a, 0x09, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x41, 0x6c, 0x6c, 0x12, 0x16, 0x2e, 0x67, 0x6f,
 	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
-	0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x30, 0x0a, 0x08, 0x52, 0x6f, 0x6c, 0x65, 0x53, 0x61, 0x76,
-	0x65, 0x12, 0x0a, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x1a, 0x16, 0x2e,
-	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
-	0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75,
-	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x65, 0x69, 0x67, 0x68, 0x6d, 0x61, 0x63, 0x64, 0x6f,
-	0x6e, 0x61, 0x6c, 0x64, 0x2f, 0x6d, 0x69, 0x6b, 0x61, 0x2f, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x70, 0x74, 0x79, 0x1a, 0x17, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x41, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3e,
+	0x0a, 0x0a, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x61, 0x76, 0x65, 0x12, 0x16, 0x2e, 0x6d,
+	0x69, 0x6b, 0x61, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x61, 0x76, 0x65, 0x50, 0x61,
+	0x72, 0x61, 0x6d, 0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x39,
+	0x0a, 0x0c, 0x57, 0x68, 0x69, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x64, 0x64, 0x12, 0x0f,
+	0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x57, 0x68, 0x69, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x1a,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x48, 0x0a, 0x0f, 0x57, 0x68, 0x69,
+	0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x1b, 0x2e, 0x6d,
+	0x69, 0x6b, 0x61, 0x2e, 0x57, 0x68, 0x69, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x22, 0x00, 0x12, 0x44, 0x0a, 0x0c, 0x57, 0x68, 0x69, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74,
+	0x41, 0x6c, 0x6c, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1a, 0x2e, 0x6d, 0x69,
+	0x6b, 0x61, 0x2e, 0x57, 0x68, 0x69, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x6c, 0x6c, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x0a, 0x54, 0x6f, 0x72,
+	0x72, 0x65, 0x6e, 0x74, 0x41, 0x6c, 0x6c, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a,
+	0x0d, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x22, 0x00,
+	0x30, 0x01, 0x12, 0x32, 0x0a, 0x0a, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x47, 0x65, 0x74,
+	0x12, 0x13, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x49, 0x6e, 0x66, 0x6f, 0x48, 0x61, 0x73, 0x68,
+	0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x0d, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x54, 0x6f, 0x72,
+	0x72, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x12, 0x35, 0x0a, 0x0a, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e,
+	0x74, 0x41, 0x64, 0x64, 0x12, 0x16, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x54, 0x6f, 0x72, 0x72,
+	0x65, 0x6e, 0x74, 0x41, 0x64, 0x64, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x0d, 0x2e, 0x6d,
+	0x69, 0x6b, 0x61, 0x2e, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x12, 0x3e, 0x0a,
+	0x0d, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x13,
+	0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x49, 0x6e, 0x66, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x50, 0x61,
+	0x72, 0x61, 0x6d, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x3b, 0x0a,
+	0x0d, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x19,
+	0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x0d, 0x2e, 0x6d, 0x69, 0x6b, 0x61,
+	0x2e, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x12, 0x35, 0x0a, 0x0a, 0x54, 0x6f,
+	0x72, 0x72, 0x65, 0x6e, 0x74, 0x54, 0x6f, 0x70, 0x12, 0x16, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e,
+	0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x54, 0x6f, 0x70, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73,
+	0x1a, 0x0d, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x22,
+	0x00, 0x12, 0x44, 0x0a, 0x0d, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x6d, 0x70, 0x6f,
+	0x72, 0x74, 0x12, 0x19, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e,
+	0x74, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x14, 0x2e,
+	0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x3a, 0x0a, 0x0d, 0x54, 0x6f, 0x72, 0x72, 0x65,
+	0x6e, 0x74, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x12, 0x19, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e,
+	0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x73, 0x1a, 0x0c, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x53, 0x65, 0x72, 0x69, 0x65,
+	0x73, 0x22, 0x00, 0x12, 0x2f, 0x0a, 0x08, 0x53, 0x77, 0x61, 0x72, 0x6d, 0x47, 0x65, 0x74, 0x12,
+	0x13, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x49, 0x6e, 0x66, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x50,
+	0x61, 0x72, 0x61, 0x6d, 0x1a, 0x0a, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x50, 0x65, 0x65, 0x72,
+	0x22, 0x00, 0x30, 0x01, 0x12, 0x2b, 0x0a, 0x0b, 0x50, 0x65, 0x65, 0x72, 0x73, 0x42, 0x79, 0x55,
+	0x73, 0x65, 0x72, 0x12, 0x0c, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49,
+	0x44, 0x1a, 0x0a, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x22, 0x00, 0x30,
+	0x01, 0x12, 0x3a, 0x0a, 0x08, 0x50, 0x65, 0x65, 0x72, 0x4b, 0x69, 0x63, 0x6b, 0x12, 0x14, 0x2e,
+	0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x4b, 0x69, 0x63, 0x6b, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x25, 0x0a,
+	0x07, 0x55, 0x73, 0x65, 0x72, 0x47, 0x65, 0x74, 0x12, 0x0c, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e,
+	0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x1a, 0x0a, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x55, 0x73,
+	0x65, 0x72, 0x22, 0x00, 0x12, 0x31, 0x0a, 0x07, 0x55, 0x73, 0x65, 0x72, 0x41, 0x6c, 0x6c, 0x12,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0a, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x55,
+	0x73, 0x65, 0x72, 0x22, 0x00, 0x30, 0x01, 0x12, 0x30, 0x0a, 0x08, 0x55, 0x73, 0x65, 0x72, 0x53,
+	0x61, 0x76, 0x65, 0x12, 0x16, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x0a, 0x2e, 0x6d, 0x69,
+	0x6b, 0x61, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x22, 0x00, 0x12, 0x34, 0x0a, 0x0a, 0x55, 0x73, 0x65,
+	0x72, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x0c, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x55,
+	0x73, 0x65, 0x72, 0x49, 0x44, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12,
+	0x2c, 0x0a, 0x07, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x12, 0x13, 0x2e, 0x6d, 0x69, 0x6b,
+	0x61, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a,
+	0x0a, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x22, 0x00, 0x12, 0x3e, 0x0a,
+	0x0a, 0x55, 0x73, 0x65, 0x72, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x16, 0x2e, 0x6d, 0x69,
+	0x6b, 0x61, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x73, 0x1a, 0x14, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x34, 0x0a,
+	0x0a, 0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x12, 0x16, 0x2e, 0x6d, 0x69,
+	0x6b, 0x61, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x73, 0x1a, 0x0c, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x53, 0x65, 0x72, 0x69, 0x65,
+	0x73, 0x22, 0x00, 0x12, 0x31, 0x0a, 0x07, 0x52, 0x6f, 0x6c, 0x65, 0x41, 0x6c, 0x6c, 0x12, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0a, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x52, 0x6f,
+	0x6c, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x2c, 0x0a, 0x07, 0x52, 0x6f, 0x6c, 0x65, 0x41, 0x64,
+	0x64, 0x12, 0x13, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x41, 0x64, 0x64,
+	0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x0a, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x52, 0x6f,
+	0x6c, 0x65, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x0a, 0x52, 0x6f, 0x6c, 0x65, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x12, 0x16, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x18, 0x2e, 0x6d, 0x69, 0x6b,
+	0x61, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x30, 0x0a, 0x08, 0x52, 0x6f, 0x6c, 0x65, 0x53, 0x61,
+	0x76, 0x65, 0x12, 0x0a, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x1a, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x2f, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x11, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x1a, 0x0b, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x30, 0x01, 0x12, 0x39, 0x0a, 0x07, 0x4d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x14, 0x2e, 0x6d,
+	0x69, 0x6b, 0x61, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x73, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x09, 0x41, 0x75, 0x64, 0x69, 0x74, 0x4c, 0x69, 0x73,
+	0x74, 0x12, 0x15, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x4c, 0x69,
+	0x73, 0x74, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x17, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e,
+	0x41, 0x75, 0x64, 0x69, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x6c, 0x65, 0x69, 0x67, 0x68, 0x6d, 0x61, 0x63, 0x64, 0x6f, 0x6e, 0x61, 0x6c, 0x64,
+	0x2f, 0x6d, 0x69, 0x6b, 0x61, 0x2f, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var file_proto_mika_proto_goTypes = []interface{}{
@@ -121,16 +164,30 @@ var file_proto_mika_proto_goTypes = []interface{}{
 	(*TorrentAddParams)(nil),      // 5: mika.TorrentAddParams
 	(*TorrentUpdateParams)(nil),   // 6: mika.TorrentUpdateParams
 	(*TorrentTopParams)(nil),      // 7: mika.TorrentTopParams
-	(*UserID)(nil),                // 8: mika.UserID
-	(*UserUpdateParams)(nil),      // 9: mika.UserUpdateParams
-	(*UserAddParams)(nil),         // 10: mika.UserAddParams
-	(*RoleAddParams)(nil),         // 11: mika.RoleAddParams
-	(*RoleID)(nil),                // 12: mika.RoleID
-	(*Role)(nil),                  // 13: mika.Role
-	(*ConfigAllResponse)(nil),     // 14: mika.ConfigAllResponse
-	(*WhiteListAllResponse)(nil),  // 15: mika.WhiteListAllResponse
-	(*Torrent)(nil),               // 16: mika.Torrent
-	(*User)(nil),                  // 17: mika.User
+	(*TorrentImportParams)(nil),   // 8: mika.TorrentImportParams
+	(*TorrentSeriesParams)(nil),   // 9: mika.TorrentSeriesParams
+	(*UserID)(nil),                // 10: mika.UserID
+	(*PeerKickParams)(nil),        // 11: mika.PeerKickParams
+	(*UserUpdateParams)(nil),      // 12: mika.UserUpdateParams
+	(*UserAddParams)(nil),         // 13: mika.UserAddParams
+	(*UserImportParams)(nil),      // 14: mika.UserImportParams
+	(*UserSeriesParams)(nil),      // 15: mika.UserSeriesParams
+	(*RoleAddParams)(nil),         // 16: mika.RoleAddParams
+	(*RoleDeleteParams)(nil),      // 17: mika.RoleDeleteParams
+	(*Role)(nil),                  // 18: mika.Role
+	(*EventFilter)(nil),           // 19: mika.EventFilter
+	(*AuditListParams)(nil),       // 20: mika.AuditListParams
+	(*ConfigAllResponse)(nil),     // 21: mika.ConfigAllResponse
+	(*WhiteListAllResponse)(nil),  // 22: mika.WhiteListAllResponse
+	(*Torrent)(nil),               // 23: mika.Torrent
+	(*ImportResponse)(nil),        // 24: mika.ImportResponse
+	(*Series)(nil),                // 25: mika.Series
+	(*Peer)(nil),                  // 26: mika.Peer
+	(*User)(nil),                  // 27: mika.User
+	(*RoleDeleteResponse)(nil),    // 28: mika.RoleDeleteResponse
+	(*Event)(nil),                 // 29: mika.Event
+	(*RuntimeMetrics)(nil),        // 30: mika.RuntimeMetrics
+	(*AuditListResponse)(nil),     // 31: mika.AuditListResponse
 }
 var file_proto_mika_proto_depIdxs = []int32{
 	0,  // 0: mika.Mika.ConfigAll:input_type -> google.protobuf.Empty
@@ -144,37 +201,57 @@ var file_proto_mika_proto_depIdxs = []int32{
 	4,  // 8: mika.Mika.TorrentDelete:input_type -> mika.InfoHashParam
 	6,  // 9: mika.Mika.TorrentUpdate:input_type -> mika.TorrentUpdateParams
 	7,  // 10: mika.Mika.TorrentTop:input_type -> mika.TorrentTopParams
-	8,  // 11: mika.Mika.UserGet:input_type -> mika.UserID
-	0,  // 12: mika.Mika.UserAll:input_type -> google.protobuf.Empty
-	9,  // 13: mika.Mika.UserSave:input_type -> mika.UserUpdateParams
-	8,  // 14: mika.Mika.UserDelete:input_type -> mika.UserID
-	10, // 15: mika.Mika.UserAdd:input_type -> mika.UserAddParams
-	0,  // 16: mika.Mika.RoleAll:input_type -> google.protobuf.Empty
-	11, // 17: mika.Mika.RoleAdd:input_type -> mika.RoleAddParams
-	12, // 18: mika.Mika.RoleDelete:input_type -> mika.RoleID
-	13, // 19: mika.Mika.RoleSave:input_type -> mika.Role
-	14, // 20: mika.Mika.ConfigAll:output_type -> mika.ConfigAllResponse
-	0,  // 21: mika.Mika.ConfigSave:output_type -> google.protobuf.Empty
-	0,  // 22: mika.Mika.WhiteListAdd:output_type -> google.protobuf.Empty
-	0,  // 23: mika.Mika.WhiteListDelete:output_type -> google.protobuf.Empty
-	15, // 24: mika.Mika.WhiteListAll:output_type -> mika.WhiteListAllResponse
-	16, // 25: mika.Mika.TorrentAll:output_type -> mika.Torrent
-	16, // 26: mika.Mika.TorrentGet:output_type -> mika.Torrent
-	16, // 27: mika.Mika.TorrentAdd:output_type -> mika.Torrent
-	0,  // 28: mika.Mika.TorrentDelete:output_type -> google.protobuf.Empty
-	16, // 29: mika.Mika.TorrentUpdate:output_type -> mika.Torrent
-	16, // 30: mika.Mika.TorrentTop:output_type -> mika.Torrent
-	17, // 31: mika.Mika.UserGet:output_type -> mika.User
-	17, // 32: mika.Mika.UserAll:output_type -> mika.User
-	17, // 33: mika.Mika.UserSave:output_type -> mika.User
-	0,  // 34: mika.Mika.UserDelete:output_type -> google.protobuf.Empty
-	17, // 35: mika.Mika.UserAdd:output_type -> mika.User
-	13, // 36: mika.Mika.RoleAll:output_type -> mika.Role
-	13, // 37: mika.Mika.RoleAdd:output_type -> mika.Role
-	0,  // 38: mika.Mika.RoleDelete:output_type -> google.protobuf.Empty
-	0,  // 39: mika.Mika.RoleSave:output_type -> google.protobuf.Empty
-	20, // [20:40] is the sub-list for method output_type
-	0,  // [0:20] is the sub-list for method input_type
+	8,  // 11: mika.Mika.TorrentImport:input_type -> mika.TorrentImportParams
+	9,  // 12: mika.Mika.TorrentSeries:input_type -> mika.TorrentSeriesParams
+	4,  // 13: mika.Mika.SwarmGet:input_type -> mika.InfoHashParam
+	10, // 14: mika.Mika.PeersByUser:input_type -> mika.UserID
+	11, // 15: mika.Mika.PeerKick:input_type -> mika.PeerKickParams
+	10, // 16: mika.Mika.UserGet:input_type -> mika.UserID
+	0,  // 17: mika.Mika.UserAll:input_type -> google.protobuf.Empty
+	12, // 18: mika.Mika.UserSave:input_type -> mika.UserUpdateParams
+	10, // 19: mika.Mika.UserDelete:input_type -> mika.UserID
+	13, // 20: mika.Mika.UserAdd:input_type -> mika.UserAddParams
+	14, // 21: mika.Mika.UserImport:input_type -> mika.UserImportParams
+	15, // 22: mika.Mika.UserSeries:input_type -> mika.UserSeriesParams
+	0,  // 23: mika.Mika.RoleAll:input_type -> google.protobuf.Empty
+	16, // 24: mika.Mika.RoleAdd:input_type -> mika.RoleAddParams
+	17, // 25: mika.Mika.RoleDelete:input_type -> mika.RoleDeleteParams
+	18, // 26: mika.Mika.RoleSave:input_type -> mika.Role
+	19, // 27: mika.Mika.Subscribe:input_type -> mika.EventFilter
+	0,  // 28: mika.Mika.Metrics:input_type -> google.protobuf.Empty
+	20, // 29: mika.Mika.AuditList:input_type -> mika.AuditListParams
+	21, // 30: mika.Mika.ConfigAll:output_type -> mika.ConfigAllResponse
+	0,  // 31: mika.Mika.ConfigSave:output_type -> google.protobuf.Empty
+	0,  // 32: mika.Mika.WhiteListAdd:output_type -> google.protobuf.Empty
+	0,  // 33: mika.Mika.WhiteListDelete:output_type -> google.protobuf.Empty
+	22, // 34: mika.Mika.WhiteListAll:output_type -> mika.WhiteListAllResponse
+	23, // 35: mika.Mika.TorrentAll:output_type -> mika.Torrent
+	23, // 36: mika.Mika.TorrentGet:output_type -> mika.Torrent
+	23, // 37: mika.Mika.TorrentAdd:output_type -> mika.Torrent
+	0,  // 38: mika.Mika.TorrentDelete:output_type -> google.protobuf.Empty
+	23, // 39: mika.Mika.TorrentUpdate:output_type -> mika.Torrent
+	23, // 40: mika.Mika.TorrentTop:output_type -> mika.Torrent
+	24, // 41: mika.Mika.TorrentImport:output_type -> mika.ImportResponse
+	25, // 42: mika.Mika.TorrentSeries:output_type -> mika.Series
+	26, // 43: mika.Mika.SwarmGet:output_type -> mika.Peer
+	26, // 44: mika.Mika.PeersByUser:output_type -> mika.Peer
+	0,  // 45: mika.Mika.PeerKick:output_type -> google.protobuf.Empty
+	27, // 46: mika.Mika.UserGet:output_type -> mika.User
+	27, // 47: mika.Mika.UserAll:output_type -> mika.User
+	27, // 48: mika.Mika.UserSave:output_type -> mika.User
+	0,  // 49: mika.Mika.UserDelete:output_type -> google.protobuf.Empty
+	27, // 50: mika.Mika.UserAdd:output_type -> mika.User
+	24, // 51: mika.Mika.UserImport:output_type -> mika.ImportResponse
+	25, // 52: mika.Mika.UserSeries:output_type -> mika.Series
+	18, // 53: mika.Mika.RoleAll:output_type -> mika.Role
+	18, // 54: mika.Mika.RoleAdd:output_type -> mika.Role
+	28, // 55: mika.Mika.RoleDelete:output_type -> mika.RoleDeleteResponse
+	0,  // 56: mika.Mika.RoleSave:output_type -> google.protobuf.Empty
+	29, // 57: mika.Mika.Subscribe:output_type -> mika.Event
+	30, // 58: mika.Mika.Metrics:output_type -> mika.RuntimeMetrics
+	31, // 59: mika.Mika.AuditList:output_type -> mika.AuditListResponse
+	30, // [30:60] is the sub-list for method output_type
+	0,  // [0:30] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
@@ -189,6 +266,11 @@ func file_proto_mika_proto_init() {
 	file_proto_tracker_proto_init()
 	file_proto_role_proto_init()
 	file_proto_user_proto_init()
+	file_proto_event_proto_init()
+	file_proto_import_proto_init()
+	file_proto_metrics_proto_init()
+	file_proto_series_proto_init()
+	file_proto_audit_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
@@ -30,15 +30,25 @@ type MikaClient interface {
 	TorrentDelete(ctx context.Context, in *InfoHashParam, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	TorrentUpdate(ctx context.Context, in *TorrentUpdateParams, opts ...grpc.CallOption) (*Torrent, error)
 	TorrentTop(ctx context.Context, in *TorrentTopParams, opts ...grpc.CallOption) (*Torrent, error)
+	TorrentImport(ctx context.Context, opts ...grpc.CallOption) (Mika_TorrentImportClient, error)
+	TorrentSeries(ctx context.Context, in *TorrentSeriesParams, opts ...grpc.CallOption) (*Series, error)
+	SwarmGet(ctx context.Context, in *InfoHashParam, opts ...grpc.CallOption) (Mika_SwarmGetClient, error)
+	PeersByUser(ctx context.Context, in *UserID, opts ...grpc.CallOption) (Mika_PeersByUserClient, error)
+	PeerKick(ctx context.Context, in *PeerKickParams, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	UserGet(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*User, error)
 	UserAll(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (Mika_UserAllClient, error)
 	UserSave(ctx context.Context, in *UserUpdateParams, opts ...grpc.CallOption) (*User, error)
 	UserDelete(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	UserAdd(ctx context.Context, in *UserAddParams, opts ...grpc.CallOption) (*User, error)
+	UserImport(ctx context.Context, opts ...grpc.CallOption) (Mika_UserImportClient, error)
+	UserSeries(ctx context.Context, in *UserSeriesParams, opts ...grpc.CallOption) (*Series, error)
 	RoleAll(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (Mika_RoleAllClient, error)
 	RoleAdd(ctx context.Context, in *RoleAddParams, opts ...grpc.CallOption) (*Role, error)
-	RoleDelete(ctx context.Context, in *RoleID, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RoleDelete(ctx context.Context, in *RoleDeleteParams, opts ...grpc.CallOption) (*RoleDeleteResponse, error)
 	RoleSave(ctx context.Context, in *Role, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Subscribe(ctx context.Context, in *EventFilter, opts ...grpc.CallOption) (Mika_SubscribeClient, error)
+	Metrics(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*RuntimeMetrics, error)
+	AuditList(ctx context.Context, in *AuditListParams, opts ...grpc.CallOption) (*AuditListResponse, error)
 }
 
 type mikaClient struct {
@@ -171,6 +181,122 @@ func (c *mikaClient) TorrentTop(ctx context.Context, in *TorrentTopParams, opts
 	return out, nil
 }
 
+func (c *mikaClient) TorrentImport(ctx context.Context, opts ...grpc.CallOption) (Mika_TorrentImportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Mika_ServiceDesc.Streams[1], "/mika.Mika/TorrentImport", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mikaTorrentImportClient{stream}
+	return x, nil
+}
+
+type Mika_TorrentImportClient interface {
+	Send(*TorrentImportParams) error
+	CloseAndRecv() (*ImportResponse, error)
+	grpc.ClientStream
+}
+
+type mikaTorrentImportClient struct {
+	grpc.ClientStream
+}
+
+func (x *mikaTorrentImportClient) Send(m *TorrentImportParams) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *mikaTorrentImportClient) CloseAndRecv() (*ImportResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mikaClient) TorrentSeries(ctx context.Context, in *TorrentSeriesParams, opts ...grpc.CallOption) (*Series, error) {
+	out := new(Series)
+	err := c.cc.Invoke(ctx, "/mika.Mika/TorrentSeries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mikaClient) SwarmGet(ctx context.Context, in *InfoHashParam, opts ...grpc.CallOption) (Mika_SwarmGetClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Mika_ServiceDesc.Streams[2], "/mika.Mika/SwarmGet", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mikaSwarmGetClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Mika_SwarmGetClient interface {
+	Recv() (*Peer, error)
+	grpc.ClientStream
+}
+
+type mikaSwarmGetClient struct {
+	grpc.ClientStream
+}
+
+func (x *mikaSwarmGetClient) Recv() (*Peer, error) {
+	m := new(Peer)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mikaClient) PeersByUser(ctx context.Context, in *UserID, opts ...grpc.CallOption) (Mika_PeersByUserClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Mika_ServiceDesc.Streams[3], "/mika.Mika/PeersByUser", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mikaPeersByUserClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Mika_PeersByUserClient interface {
+	Recv() (*Peer, error)
+	grpc.ClientStream
+}
+
+type mikaPeersByUserClient struct {
+	grpc.ClientStream
+}
+
+func (x *mikaPeersByUserClient) Recv() (*Peer, error) {
+	m := new(Peer)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mikaClient) PeerKick(ctx context.Context, in *PeerKickParams, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/mika.Mika/PeerKick", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mikaClient) UserGet(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*User, error) {
 	out := new(User)
 	err := c.cc.Invoke(ctx, "/mika.Mika/UserGet", in, out, opts...)
@@ -181,7 +307,7 @@ func (c *mikaClient) UserGet(ctx context.Context, in *UserID, opts ...grpc.CallO
 }
 
 func (c *mikaClient) UserAll(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (Mika_UserAllClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Mika_ServiceDesc.Streams[1], "/mika.Mika/UserAll", opts...)
+	stream, err := c.cc.NewStream(ctx, &Mika_ServiceDesc.Streams[4], "/mika.Mika/UserAll", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -239,8 +365,51 @@ func (c *mikaClient) UserAdd(ctx context.Context, in *UserAddParams, opts ...grp
 	return out, nil
 }
 
+func (c *mikaClient) UserImport(ctx context.Context, opts ...grpc.CallOption) (Mika_UserImportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Mika_ServiceDesc.Streams[5], "/mika.Mika/UserImport", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mikaUserImportClient{stream}
+	return x, nil
+}
+
+type Mika_UserImportClient interface {
+	Send(*UserImportParams) error
+	CloseAndRecv() (*ImportResponse, error)
+	grpc.ClientStream
+}
+
+type mikaUserImportClient struct {
+	grpc.ClientStream
+}
+
+func (x *mikaUserImportClient) Send(m *UserImportParams) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *mikaUserImportClient) CloseAndRecv() (*ImportResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mikaClient) UserSeries(ctx context.Context, in *UserSeriesParams, opts ...grpc.CallOption) (*Series, error) {
+	out := new(Series)
+	err := c.cc.Invoke(ctx, "/mika.Mika/UserSeries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mikaClient) RoleAll(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (Mika_RoleAllClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Mika_ServiceDesc.Streams[2], "/mika.Mika/RoleAll", opts...)
+	stream, err := c.cc.NewStream(ctx, &Mika_ServiceDesc.Streams[6], "/mika.Mika/RoleAll", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -280,8 +449,8 @@ func (c *mikaClient) RoleAdd(ctx context.Context, in *RoleAddParams, opts ...grp
 	return out, nil
 }
 
-func (c *mikaClient) RoleDelete(ctx context.Context, in *RoleID, opts ...grpc.CallOption) (*emptypb.Empty, error) {
-	out := new(emptypb.Empty)
+func (c *mikaClient) RoleDelete(ctx context.Context, in *RoleDeleteParams, opts ...grpc.CallOption) (*RoleDeleteResponse, error) {
+	out := new(RoleDeleteResponse)
 	err := c.cc.Invoke(ctx, "/mika.Mika/RoleDelete", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -298,6 +467,56 @@ func (c *mikaClient) RoleSave(ctx context.Context, in *Role, opts ...grpc.CallOp
 	return out, nil
 }
 
+func (c *mikaClient) Subscribe(ctx context.Context, in *EventFilter, opts ...grpc.CallOption) (Mika_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Mika_ServiceDesc.Streams[7], "/mika.Mika/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mikaSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Mika_SubscribeClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type mikaSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *mikaSubscribeClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mikaClient) Metrics(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*RuntimeMetrics, error) {
+	out := new(RuntimeMetrics)
+	err := c.cc.Invoke(ctx, "/mika.Mika/Metrics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mikaClient) AuditList(ctx context.Context, in *AuditListParams, opts ...grpc.CallOption) (*AuditListResponse, error) {
+	out := new(AuditListResponse)
+	err := c.cc.Invoke(ctx, "/mika.Mika/AuditList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MikaServer is the server API for Mika service.
 // All implementations must embed UnimplementedMikaServer
 // for forward compatibility
@@ -313,15 +532,25 @@ type MikaServer interface {
 	TorrentDelete(context.Context, *InfoHashParam) (*emptypb.Empty, error)
 	TorrentUpdate(context.Context, *TorrentUpdateParams) (*Torrent, error)
 	TorrentTop(context.Context, *TorrentTopParams) (*Torrent, error)
+	TorrentImport(Mika_TorrentImportServer) error
+	TorrentSeries(context.Context, *TorrentSeriesParams) (*Series, error)
+	SwarmGet(*InfoHashParam, Mika_SwarmGetServer) error
+	PeersByUser(*UserID, Mika_PeersByUserServer) error
+	PeerKick(context.Context, *PeerKickParams) (*emptypb.Empty, error)
 	UserGet(context.Context, *UserID) (*User, error)
 	UserAll(*emptypb.Empty, Mika_UserAllServer) error
 	UserSave(context.Context, *UserUpdateParams) (*User, error)
 	UserDelete(context.Context, *UserID) (*emptypb.Empty, error)
 	UserAdd(context.Context, *UserAddParams) (*User, error)
+	UserImport(Mika_UserImportServer) error
+	UserSeries(context.Context, *UserSeriesParams) (*Series, error)
 	RoleAll(*emptypb.Empty, Mika_RoleAllServer) error
 	RoleAdd(context.Context, *RoleAddParams) (*Role, error)
-	RoleDelete(context.Context, *RoleID) (*emptypb.Empty, error)
+	RoleDelete(context.Context, *RoleDeleteParams) (*RoleDeleteResponse, error)
 	RoleSave(context.Context, *Role) (*emptypb.Empty, error)
+	Subscribe(*EventFilter, Mika_SubscribeServer) error
+	Metrics(context.Context, *emptypb.Empty) (*RuntimeMetrics, error)
+	AuditList(context.Context, *AuditListParams) (*AuditListResponse, error)
 	mustEmbedUnimplementedMikaServer()
 }
 
@@ -362,6 +591,21 @@ func (UnimplementedMikaServer) TorrentUpdate(context.Context, *TorrentUpdatePara
 func (UnimplementedMikaServer) TorrentTop(context.Context, *TorrentTopParams) (*Torrent, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method TorrentTop not implemented")
 }
+func (UnimplementedMikaServer) TorrentImport(Mika_TorrentImportServer) error {
+	return status.Errorf(codes.Unimplemented, "method TorrentImport not implemented")
+}
+func (UnimplementedMikaServer) TorrentSeries(context.Context, *TorrentSeriesParams) (*Series, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TorrentSeries not implemented")
+}
+func (UnimplementedMikaServer) SwarmGet(*InfoHashParam, Mika_SwarmGetServer) error {
+	return status.Errorf(codes.Unimplemented, "method SwarmGet not implemented")
+}
+func (UnimplementedMikaServer) PeersByUser(*UserID, Mika_PeersByUserServer) error {
+	return status.Errorf(codes.Unimplemented, "method PeersByUser not implemented")
+}
+func (UnimplementedMikaServer) PeerKick(context.Context, *PeerKickParams) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PeerKick not implemented")
+}
 func (UnimplementedMikaServer) UserGet(context.Context, *UserID) (*User, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UserGet not implemented")
 }
@@ -377,18 +621,33 @@ func (UnimplementedMikaServer) UserDelete(context.Context, *UserID) (*emptypb.Em
 func (UnimplementedMikaServer) UserAdd(context.Context, *UserAddParams) (*User, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UserAdd not implemented")
 }
+func (UnimplementedMikaServer) UserImport(Mika_UserImportServer) error {
+	return status.Errorf(codes.Unimplemented, "method UserImport not implemented")
+}
+func (UnimplementedMikaServer) UserSeries(context.Context, *UserSeriesParams) (*Series, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UserSeries not implemented")
+}
 func (UnimplementedMikaServer) RoleAll(*emptypb.Empty, Mika_RoleAllServer) error {
 	return status.Errorf(codes.Unimplemented, "method RoleAll not implemented")
 }
 func (UnimplementedMikaServer) RoleAdd(context.Context, *RoleAddParams) (*Role, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RoleAdd not implemented")
 }
-func (UnimplementedMikaServer) RoleDelete(context.Context, *RoleID) (*emptypb.Empty, error) {
+func (UnimplementedMikaServer) RoleDelete(context.Context, *RoleDeleteParams) (*RoleDeleteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RoleDelete not implemented")
 }
 func (UnimplementedMikaServer) RoleSave(context.Context, *Role) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RoleSave not implemented")
 }
+func (UnimplementedMikaServer) Subscribe(*EventFilter, Mika_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedMikaServer) Metrics(context.Context, *emptypb.Empty) (*RuntimeMetrics, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Metrics not implemented")
+}
+func (UnimplementedMikaServer) AuditList(context.Context, *AuditListParams) (*AuditListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuditList not implemented")
+}
 func (UnimplementedMikaServer) mustEmbedUnimplementedMikaServer() {}
 
 // UnsafeMikaServer may be embedded to opt out of forward compatibility for this service.
@@ -603,6 +862,110 @@ func _Mika_TorrentTop_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Mika_TorrentImport_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MikaServer).TorrentImport(&mikaTorrentImportServer{stream})
+}
+
+type Mika_TorrentImportServer interface {
+	SendAndClose(*ImportResponse) error
+	Recv() (*TorrentImportParams, error)
+	grpc.ServerStream
+}
+
+type mikaTorrentImportServer struct {
+	grpc.ServerStream
+}
+
+func (x *mikaTorrentImportServer) SendAndClose(m *ImportResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *mikaTorrentImportServer) Recv() (*TorrentImportParams, error) {
+	m := new(TorrentImportParams)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Mika_TorrentSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TorrentSeriesParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MikaServer).TorrentSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mika.Mika/TorrentSeries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MikaServer).TorrentSeries(ctx, req.(*TorrentSeriesParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mika_SwarmGet_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(InfoHashParam)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MikaServer).SwarmGet(m, &mikaSwarmGetServer{stream})
+}
+
+type Mika_SwarmGetServer interface {
+	Send(*Peer) error
+	grpc.ServerStream
+}
+
+type mikaSwarmGetServer struct {
+	grpc.ServerStream
+}
+
+func (x *mikaSwarmGetServer) Send(m *Peer) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Mika_PeersByUser_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(UserID)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MikaServer).PeersByUser(m, &mikaPeersByUserServer{stream})
+}
+
+type Mika_PeersByUserServer interface {
+	Send(*Peer) error
+	grpc.ServerStream
+}
+
+type mikaPeersByUserServer struct {
+	grpc.ServerStream
+}
+
+func (x *mikaPeersByUserServer) Send(m *Peer) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Mika_PeerKick_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeerKickParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MikaServer).PeerKick(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mika.Mika/PeerKick",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MikaServer).PeerKick(ctx, req.(*PeerKickParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Mika_UserGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UserID)
 	if err := dec(in); err != nil {
@@ -696,6 +1059,50 @@ func _Mika_UserAdd_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Mika_UserImport_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MikaServer).UserImport(&mikaUserImportServer{stream})
+}
+
+type Mika_UserImportServer interface {
+	SendAndClose(*ImportResponse) error
+	Recv() (*UserImportParams, error)
+	grpc.ServerStream
+}
+
+type mikaUserImportServer struct {
+	grpc.ServerStream
+}
+
+func (x *mikaUserImportServer) SendAndClose(m *ImportResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *mikaUserImportServer) Recv() (*UserImportParams, error) {
+	m := new(UserImportParams)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Mika_UserSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserSeriesParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MikaServer).UserSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mika.Mika/UserSeries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MikaServer).UserSeries(ctx, req.(*UserSeriesParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Mika_RoleAll_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(emptypb.Empty)
 	if err := stream.RecvMsg(m); err != nil {
@@ -736,7 +1143,7 @@ func _Mika_RoleAdd_Handler(srv interface{}, ctx context.Context, dec func(interf
 }
 
 func _Mika_RoleDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RoleID)
+	in := new(RoleDeleteParams)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -748,7 +1155,7 @@ func _Mika_RoleDelete_Handler(srv interface{}, ctx context.Context, dec func(int
 		FullMethod: "/mika.Mika/RoleDelete",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MikaServer).RoleDelete(ctx, req.(*RoleID))
+		return srv.(MikaServer).RoleDelete(ctx, req.(*RoleDeleteParams))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -771,6 +1178,63 @@ func _Mika_RoleSave_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Mika_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EventFilter)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MikaServer).Subscribe(m, &mikaSubscribeServer{stream})
+}
+
+type Mika_SubscribeServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type mikaSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *mikaSubscribeServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Mika_Metrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MikaServer).Metrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mika.Mika/Metrics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MikaServer).Metrics(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mika_AuditList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditListParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MikaServer).AuditList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mika.Mika/AuditList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MikaServer).AuditList(ctx, req.(*AuditListParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Mika_ServiceDesc is the grpc.ServiceDesc for Mika service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -818,6 +1282,14 @@ var Mika_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "TorrentTop",
 			Handler:    _Mika_TorrentTop_Handler,
 		},
+		{
+			MethodName: "TorrentSeries",
+			Handler:    _Mika_TorrentSeries_Handler,
+		},
+		{
+			MethodName: "PeerKick",
+			Handler:    _Mika_PeerKick_Handler,
+		},
 		{
 			MethodName: "UserGet",
 			Handler:    _Mika_UserGet_Handler,
@@ -834,6 +1306,10 @@ var Mika_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UserAdd",
 			Handler:    _Mika_UserAdd_Handler,
 		},
+		{
+			MethodName: "UserSeries",
+			Handler:    _Mika_UserSeries_Handler,
+		},
 		{
 			MethodName: "RoleAdd",
 			Handler:    _Mika_RoleAdd_Handler,
@@ -846,6 +1322,14 @@ var Mika_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RoleSave",
 			Handler:    _Mika_RoleSave_Handler,
 		},
+		{
+			MethodName: "Metrics",
+			Handler:    _Mika_Metrics_Handler,
+		},
+		{
+			MethodName: "AuditList",
+			Handler:    _Mika_AuditList_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -853,16 +1337,41 @@ var Mika_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Mika_TorrentAll_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "TorrentImport",
+			Handler:       _Mika_TorrentImport_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "SwarmGet",
+			Handler:       _Mika_SwarmGet_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "PeersByUser",
+			Handler:       _Mika_PeersByUser_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "UserAll",
 			Handler:       _Mika_UserAll_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "UserImport",
+			Handler:       _Mika_UserImport_Handler,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "RoleAll",
 			Handler:       _Mika_RoleAll_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Subscribe",
+			Handler:       _Mika_Subscribe_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "proto/mika.proto",
 }
@@ -0,0 +1,523 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.14.0
+// source: proto/series.proto
+
+package rpc
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// SeriesResolution is the width of each bucket of a series
+type SeriesResolution int32
+
+const (
+	SeriesResolution_MINUTE SeriesResolution = 0
+	SeriesResolution_HOUR   SeriesResolution = 1
+	SeriesResolution_DAY    SeriesResolution = 2
+)
+
+// Enum value maps for SeriesResolution.
+var (
+	SeriesResolution_name = map[int32]string{
+		0: "MINUTE",
+		1: "HOUR",
+		2: "DAY",
+	}
+	SeriesResolution_value = map[string]int32{
+		"MINUTE": 0,
+		"HOUR":   1,
+		"DAY":    2,
+	}
+)
+
+func (x SeriesResolution) Enum() *SeriesResolution {
+	p := new(SeriesResolution)
+	*p = x
+	return p
+}
+
+func (x SeriesResolution) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SeriesResolution) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_series_proto_enumTypes[0].Descriptor()
+}
+
+func (SeriesResolution) Type() protoreflect.EnumType {
+	return &file_proto_series_proto_enumTypes[0]
+}
+
+func (x SeriesResolution) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SeriesResolution.Descriptor instead.
+func (SeriesResolution) EnumDescriptor() ([]byte, []int) {
+	return file_proto_series_proto_rawDescGZIP(), []int{0}
+}
+
+type TorrentSeriesParams struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InfoHash   []byte           `protobuf:"bytes,1,opt,name=info_hash,json=infoHash,proto3" json:"info_hash,omitempty"`
+	Resolution SeriesResolution `protobuf:"varint,2,opt,name=resolution,proto3,enum=mika.SeriesResolution" json:"resolution,omitempty"`
+	// Number of the most recent buckets returned, 0 returns all of the buckets kept
+	Limit uint32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *TorrentSeriesParams) Reset() {
+	*x = TorrentSeriesParams{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_series_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TorrentSeriesParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TorrentSeriesParams) ProtoMessage() {}
+
+func (x *TorrentSeriesParams) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_series_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TorrentSeriesParams.ProtoReflect.Descriptor instead.
+func (*TorrentSeriesParams) Descriptor() ([]byte, []int) {
+	return file_proto_series_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TorrentSeriesParams) GetInfoHash() []byte {
+	if x != nil {
+		return x.InfoHash
+	}
+	return nil
+}
+
+func (x *TorrentSeriesParams) GetResolution() SeriesResolution {
+	if x != nil {
+		return x.Resolution
+	}
+	return SeriesResolution_MINUTE
+}
+
+func (x *TorrentSeriesParams) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type UserSeriesParams struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserId     uint32           `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Resolution SeriesResolution `protobuf:"varint,2,opt,name=resolution,proto3,enum=mika.SeriesResolution" json:"resolution,omitempty"`
+	// Number of the most recent buckets returned, 0 returns all of the buckets kept
+	Limit uint32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *UserSeriesParams) Reset() {
+	*x = UserSeriesParams{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_series_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserSeriesParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserSeriesParams) ProtoMessage() {}
+
+func (x *UserSeriesParams) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_series_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserSeriesParams.ProtoReflect.Descriptor instead.
+func (*UserSeriesParams) Descriptor() ([]byte, []int) {
+	return file_proto_series_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UserSeriesParams) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *UserSeriesParams) GetResolution() SeriesResolution {
+	if x != nil {
+		return x.Resolution
+	}
+	return SeriesResolution_MINUTE
+}
+
+func (x *UserSeriesParams) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// SeriesPoint holds the activity within a single bucket
+type SeriesPoint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Start of the bucket
+	Time       *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Announces  uint64                 `protobuf:"varint,2,opt,name=announces,proto3" json:"announces,omitempty"`
+	Snatches   uint64                 `protobuf:"varint,3,opt,name=snatches,proto3" json:"snatches,omitempty"`
+	Uploaded   uint64                 `protobuf:"varint,4,opt,name=uploaded,proto3" json:"uploaded,omitempty"`
+	Downloaded uint64                 `protobuf:"varint,5,opt,name=downloaded,proto3" json:"downloaded,omitempty"`
+	// Swarm size as of the last announce within the bucket, always 0 for users
+	Seeders  uint32 `protobuf:"varint,6,opt,name=seeders,proto3" json:"seeders,omitempty"`
+	Leechers uint32 `protobuf:"varint,7,opt,name=leechers,proto3" json:"leechers,omitempty"`
+}
+
+func (x *SeriesPoint) Reset() {
+	*x = SeriesPoint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_series_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SeriesPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeriesPoint) ProtoMessage() {}
+
+func (x *SeriesPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_series_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeriesPoint.ProtoReflect.Descriptor instead.
+func (*SeriesPoint) Descriptor() ([]byte, []int) {
+	return file_proto_series_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SeriesPoint) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *SeriesPoint) GetAnnounces() uint64 {
+	if x != nil {
+		return x.Announces
+	}
+	return 0
+}
+
+func (x *SeriesPoint) GetSnatches() uint64 {
+	if x != nil {
+		return x.Snatches
+	}
+	return 0
+}
+
+func (x *SeriesPoint) GetUploaded() uint64 {
+	if x != nil {
+		return x.Uploaded
+	}
+	return 0
+}
+
+func (x *SeriesPoint) GetDownloaded() uint64 {
+	if x != nil {
+		return x.Downloaded
+	}
+	return 0
+}
+
+func (x *SeriesPoint) GetSeeders() uint32 {
+	if x != nil {
+		return x.Seeders
+	}
+	return 0
+}
+
+func (x *SeriesPoint) GetLeechers() uint32 {
+	if x != nil {
+		return x.Leechers
+	}
+	return 0
+}
+
+// Series holds the activity of a torrent or user, oldest bucket first
+type Series struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Resolution SeriesResolution `protobuf:"varint,1,opt,name=resolution,proto3,enum=mika.SeriesResolution" json:"resolution,omitempty"`
+	Points     []*SeriesPoint   `protobuf:"bytes,2,rep,name=points,proto3" json:"points,omitempty"`
+}
+
+func (x *Series) Reset() {
+	*x = Series{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_series_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Series) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Series) ProtoMessage() {}
+
+func (x *Series) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_series_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Series.ProtoReflect.Descriptor instead.
+func (*Series) Descriptor() ([]byte, []int) {
+	return file_proto_series_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Series) GetResolution() SeriesResolution {
+	if x != nil {
+		return x.Resolution
+	}
+	return SeriesResolution_MINUTE
+}
+
+func (x *Series) GetPoints() []*SeriesPoint {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+var File_proto_series_proto protoreflect.FileDescriptor
+
+var file_proto_series_proto_rawDesc = []byte{
+	0x0a, 0x12, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x65, 0x72, 0x69, 0x65, 0x73, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x6d, 0x69, 0x6b, 0x61, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x80, 0x01, 0x0a, 0x13,
+	0x54, 0x6f, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x6e, 0x66, 0x6f, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x69, 0x6e, 0x66, 0x6f, 0x48, 0x61, 0x73, 0x68,
+	0x12, 0x36, 0x0a, 0x0a, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x53, 0x65, 0x72, 0x69,
+	0x65, 0x73, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x72, 0x65,
+	0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x79,
+	0x0a, 0x10, 0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x36, 0x0a, 0x0a, 0x72,
+	0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x16, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0xe9, 0x01, 0x0a, 0x0b, 0x53, 0x65,
+	0x72, 0x69, 0x65, 0x73, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x6e, 0x6e,
+	0x6f, 0x75, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x61, 0x6e,
+	0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x74, 0x63,
+	0x68, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x74, 0x63,
+	0x68, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x12,
+	0x1e, 0x0a, 0x0a, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0a, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x12,
+	0x18, 0x0a, 0x07, 0x73, 0x65, 0x65, 0x64, 0x65, 0x72, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x07, 0x73, 0x65, 0x65, 0x64, 0x65, 0x72, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x65, 0x65,
+	0x63, 0x68, 0x65, 0x72, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x6c, 0x65, 0x65,
+	0x63, 0x68, 0x65, 0x72, 0x73, 0x22, 0x6b, 0x0a, 0x06, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x12,
+	0x36, 0x0a, 0x0a, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x53, 0x65, 0x72, 0x69, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x72, 0x65, 0x73,
+	0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6d, 0x69, 0x6b, 0x61, 0x2e, 0x53,
+	0x65, 0x72, 0x69, 0x65, 0x73, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x06, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x73, 0x2a, 0x31, 0x0a, 0x10, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x6f,
+	0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0a, 0x0a, 0x06, 0x4d, 0x49, 0x4e, 0x55, 0x54, 0x45,
+	0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x4f, 0x55, 0x52, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03,
+	0x44, 0x41, 0x59, 0x10, 0x02, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x65, 0x69, 0x67, 0x68, 0x6d, 0x61, 0x63, 0x64, 0x6f, 0x6e, 0x61,
+	0x6c, 0x64, 0x2f, 0x6d, 0x69, 0x6b, 0x61, 0x2f, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_series_proto_rawDescOnce sync.Once
+	file_proto_series_proto_rawDescData = file_proto_series_proto_rawDesc
+)
+
+func file_proto_series_proto_rawDescGZIP() []byte {
+	file_proto_series_proto_rawDescOnce.Do(func() {
+		file_proto_series_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_series_proto_rawDescData)
+	})
+	return file_proto_series_proto_rawDescData
+}
+
+var file_proto_series_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_series_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_series_proto_goTypes = []interface{}{
+	(SeriesResolution)(0),         // 0: mika.SeriesResolution
+	(*TorrentSeriesParams)(nil),   // 1: mika.TorrentSeriesParams
+	(*UserSeriesParams)(nil),      // 2: mika.UserSeriesParams
+	(*SeriesPoint)(nil),           // 3: mika.SeriesPoint
+	(*Series)(nil),                // 4: mika.Series
+	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
+}
+var file_proto_series_proto_depIdxs = []int32{
+	0, // 0: mika.TorrentSeriesParams.resolution:type_name -> mika.SeriesResolution
+	0, // 1: mika.UserSeriesParams.resolution:type_name -> mika.SeriesResolution
+	5, // 2: mika.SeriesPoint.time:type_name -> google.protobuf.Timestamp
+	0, // 3: mika.Series.resolution:type_name -> mika.SeriesResolution
+	3, // 4: mika.Series.points:type_name -> mika.SeriesPoint
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_proto_series_proto_init() }
+func file_proto_series_proto_init() {
+	if File_proto_series_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_series_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TorrentSeriesParams); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_series_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserSeriesParams); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_series_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SeriesPoint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_series_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Series); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_series_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_proto_series_proto_goTypes,
+		DependencyIndexes: file_proto_series_proto_depIdxs,
+		EnumInfos:         file_proto_series_proto_enumTypes,
+		MessageInfos:      file_proto_series_proto_msgTypes,
+	}.Build()
+	File_proto_series_proto = out.File
+	file_proto_series_proto_rawDesc = nil
+	file_proto_series_proto_goTypes = nil
+	file_proto_series_proto_depIdxs = nil
+}
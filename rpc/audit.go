@@ -0,0 +1,104 @@
+package rpc
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"github.com/leighmacdonald/mika/consts"
+	pb "github.com/leighmacdonald/mika/proto"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/leighmacdonald/mika/tracker"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func AuditEntryToPB(e *store.AuditEntry) *pb.AuditEntry {
+	return &pb.AuditEntry{
+		AuditId:   e.AuditID,
+		CreatedOn: timestamppb.New(e.CreatedOn),
+		Actor:     e.Actor,
+		Address:   e.Address,
+		Action:    e.Action,
+		Target:    e.Target,
+		Before:    string(e.Before),
+		After:     string(e.After),
+	}
+}
+
+func torrentTarget(ih store.InfoHash) string {
+	return "torrent:" + ih.String()
+}
+
+func userTarget(userID uint32) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+func roleTarget(roleID uint32) string {
+	return fmt.Sprintf("role:%d", roleID)
+}
+
+func whiteListTarget(key string) string {
+	return "whitelist:" + key
+}
+
+func peerTarget(ih store.InfoHash, peerID []byte) string {
+	return fmt.Sprintf("peer:%s/%s", ih.String(), hex.EncodeToString(peerID))
+}
+
+// audit records a change made by the caller of the request. Before and after are the API
+// representations of the object, nil when the object did not exist. Failures are logged
+// instead of failing the request as the change has already been made.
+func audit(ctx context.Context, action string, target string, before proto.Message, after proto.Message) {
+	caller := CallerFromContext(ctx)
+	e := &store.AuditEntry{
+		Actor:   caller.Name,
+		Address: caller.Address,
+		Action:  action,
+		Target:  target,
+	}
+	var err error
+	if before != nil {
+		if e.Before, err = marshalOpts.Marshal(before); err != nil {
+			log.Errorf("Failed to encode audit entry: %v", err)
+		}
+	}
+	if after != nil {
+		if e.After, err = marshalOpts.Marshal(after); err != nil {
+			log.Errorf("Failed to encode audit entry: %v", err)
+		}
+	}
+	if err := tracker.Audit(e); err != nil {
+		log.Errorf("Failed to record audit entry %s %s by %s: %v", action, target, caller.Name, err)
+	}
+}
+
+func (s *MikaService) AuditList(_ context.Context, params *pb.AuditListParams) (*pb.AuditListResponse, error) {
+	f := store.AuditFilter{
+		Actor:  params.Actor,
+		Action: params.Action,
+		Target: params.Target,
+		Limit:  int(params.Limit),
+	}
+	if params.Since != nil {
+		f.Since = params.Since.AsTime()
+	}
+	if params.Until != nil {
+		f.Until = params.Until.AsTime()
+	}
+	entries, err := tracker.AuditList(f)
+	if err != nil {
+		if errors.Is(err, consts.ErrAuditDisabled) {
+			return nil, status.Errorf(codes.FailedPrecondition, "audit log is disabled")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to list audit log")
+	}
+	resp := &pb.AuditListResponse{}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, AuditEntryToPB(e))
+	}
+	return resp, nil
+}
@@ -0,0 +1,138 @@
+package rpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
+	"strings"
+)
+
+// DefaultKeyName is the name of the api.key in the audit log
+const DefaultKeyName = "default"
+
+// APIKeys maps the name of each api key to the key. The name identifies the caller in the audit log.
+type APIKeys map[string]string
+
+// NewAPIKeys returns the api.key, named default, along with the named api.keys
+func NewAPIKeys(key string, named map[string]string) APIKeys {
+	keys := APIKeys{}
+	if key != "" {
+		keys[DefaultKeyName] = key
+	}
+	for name, k := range named {
+		if k != "" {
+			keys[name] = k
+		}
+	}
+	return keys
+}
+
+// identify returns the name of the key, every key is compared to avoid leaking which matched
+func (k APIKeys) identify(key string) (string, bool) {
+	var found string
+	for name, expected := range k {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(expected)) == 1 {
+			found = name
+		}
+	}
+	return found, found != "" && key != ""
+}
+
+// Caller identifies who made a request
+type Caller struct {
+	// Name is the name of the api key used
+	Name string
+	// Address is the remote address of the caller
+	Address string
+}
+
+type callerKey struct{}
+
+func withCaller(ctx context.Context, c Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, c)
+}
+
+// CallerFromContext returns the authenticated caller of the request
+func CallerFromContext(ctx context.Context) Caller {
+	c, ok := ctx.Value(callerKey{}).(Caller)
+	if !ok {
+		return Caller{Name: "unknown"}
+	}
+	return c
+}
+
+// bearerKey returns the key of an Authorization header value, eg: Bearer <key>
+func bearerKey(header string) string {
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(header, prefix)
+}
+
+// authenticate identifies the caller of a gRPC request using the api key sent in the
+// authorization metadata
+func (k APIKeys) authenticate(ctx context.Context) (context.Context, error) {
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+	}
+	name, ok := k.identify(bearerKey(header))
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid api key")
+	}
+	var addr string
+	if p, found := peer.FromContext(ctx); found && p.Addr != nil {
+		addr = p.Addr.String()
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			addr = host
+		}
+	}
+	return withCaller(ctx, Caller{Name: name, Address: addr}), nil
+}
+
+// UnaryInterceptor rejects unary gRPC requests without a valid api key
+func (k APIKeys) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := k.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authedStream replaces the context of a stream with the authenticated context
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s authedStream) Context() context.Context { return s.ctx }
+
+// StreamInterceptor rejects streaming gRPC requests without a valid api key
+func (k APIKeys) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := k.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, authedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// ServerOptions returns the options required to authenticate gRPC requests with the keys
+func (k APIKeys) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(k.UnaryInterceptor()),
+		grpc.StreamInterceptor(k.StreamInterceptor()),
+	}
+}
@@ -19,15 +19,21 @@ type MikaService struct {
 
 func PBToWhiteList(p *pb.WhiteList) *store.WhiteListClient {
 	return &store.WhiteListClient{
-		ClientPrefix: p.Prefix,
-		ClientName:   p.Name,
+		ClientCode: p.Code,
+		ClientName: p.Name,
+		MinVersion: p.MinVersion,
+		MaxVersion: p.MaxVersion,
+		Blacklist:  p.Blacklist,
 	}
 }
 
 func WhiteListToPB(w *store.WhiteListClient) *pb.WhiteList {
 	return &pb.WhiteList{
-		Prefix: w.ClientPrefix,
-		Name:   w.ClientName,
+		Code:       w.ClientCode,
+		Name:       w.ClientName,
+		MinVersion: w.MinVersion,
+		MaxVersion: w.MaxVersion,
+		Blacklist:  w.Blacklist,
 	}
 }
 
@@ -37,9 +43,9 @@ func renderWhiteList(wl []*store.WhiteListClient, title string) {
 	if title != "" {
 		t.SetTitle(title)
 	}
-	t.AppendHeader(table.Row{"name", "prefix"})
+	t.AppendHeader(table.Row{"name", "code", "min_version", "max_version", "blacklist"})
 	for _, w := range wl {
-		t.AppendRow(table.Row{w.ClientName, w.ClientPrefix})
+		t.AppendRow(table.Row{w.ClientName, w.ClientCode, w.MinVersion, w.MaxVersion, w.Blacklist})
 	}
 	t.SortBy([]table.SortBy{{
 		Name: "name",
@@ -54,25 +60,35 @@ func (s *MikaService) ConfigSave(context.Context, *pb.ConfigSaveParams) (*emptyp
 	return nil, status.Errorf(codes.Unimplemented, "method ConfigSave not implemented")
 }
 
-func (s *MikaService) WhiteListAdd(_ context.Context, params *pb.WhiteList) (*emptypb.Empty, error) {
-	wl := &store.WhiteListClient{ClientPrefix: params.Prefix, ClientName: params.Name}
+func (s *MikaService) WhiteListAdd(ctx context.Context, params *pb.WhiteList) (*emptypb.Empty, error) {
+	wl := PBToWhiteList(params)
+	if err := wl.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid whitelist client: %v", err)
+	}
 	err := tracker.WhiteListAdd(wl)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to add whitelist client")
 	}
 	renderWhiteList([]*store.WhiteListClient{wl}, "Whitelisted Client")
 	log.Infof("Added new whitelisted client: %s", params.Name)
+	audit(ctx, "whitelist.add", whiteListTarget(wl.Key()), nil, WhiteListToPB(wl))
 	return &emptypb.Empty{}, nil
 }
 
-func (s *MikaService) WhiteListDelete(_ context.Context, params *pb.WhiteListDeleteParams) (*emptypb.Empty, error) {
-	w, err := tracker.WhiteListGet(params.Prefix)
+func (s *MikaService) WhiteListDelete(ctx context.Context, params *pb.WhiteListDeleteParams) (*emptypb.Empty, error) {
+	key := store.WhiteListClient{
+		ClientCode: params.Code,
+		MinVersion: params.MinVersion,
+		MaxVersion: params.MaxVersion,
+	}.Key()
+	w, err := tracker.WhiteListGet(key)
 	if err != nil {
-		return &emptypb.Empty{}, status.Errorf(codes.NotFound, "unknown client prefix")
+		return &emptypb.Empty{}, status.Errorf(codes.NotFound, "unknown whitelist client")
 	}
 	if err := tracker.WhiteListDelete(w); err != nil {
 		return &emptypb.Empty{}, status.Errorf(codes.NotFound, "error removing client from whitelist")
 	}
+	audit(ctx, "whitelist.delete", whiteListTarget(key), WhiteListToPB(w), nil)
 	return &emptypb.Empty{}, nil
 }
 
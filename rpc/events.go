@@ -0,0 +1,56 @@
+package rpc
+
+import (
+	pb "github.com/leighmacdonald/mika/proto"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/leighmacdonald/mika/tracker"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func PBToEventFilter(p *pb.EventFilter) tracker.EventFilter {
+	var filter tracker.EventFilter
+	for _, t := range p.Types {
+		filter.Types = append(filter.Types, tracker.EventType(t))
+	}
+	filter.UserID = p.UserId
+	_ = store.InfoHashFromBytes(&filter.InfoHash, p.InfoHash)
+	return filter
+}
+
+func EventToPB(e tracker.Event) *pb.Event {
+	ev := &pb.Event{
+		Type:   pb.EventType(e.Type),
+		Time:   timestamppb.New(e.Time),
+		UserId: e.UserID,
+		Reason: e.Reason,
+	}
+	if e.InfoHash != (store.InfoHash{}) {
+		ev.InfoHash = e.InfoHash.Bytes()
+	}
+	if e.PeerID != (store.PeerID{}) {
+		ev.PeerId = e.PeerID.Bytes()
+	}
+	return ev
+}
+
+// Subscribe streams tracker events matching the filter until the client disconnects. Events
+// are dropped for clients which cannot keep up, the count of dropped events is sent with the
+// next event delivered.
+func (s *MikaService) Subscribe(params *pb.EventFilter, stream pb.Mika_SubscribeServer) error {
+	sub := tracker.Subscribe(PBToEventFilter(params))
+	defer tracker.Unsubscribe(sub)
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e := <-sub.C:
+			ev := EventToPB(e)
+			ev.Dropped = sub.Dropped()
+			if err := stream.Send(ev); err != nil {
+				return status.Errorf(codes.Internal, "failed to send event")
+			}
+		}
+	}
+}
@@ -0,0 +1,564 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/leighmacdonald/mika/metrics"
+	pb "github.com/leighmacdonald/mika/proto"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/toorop/gin-logrus"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GatewayPrefix is the path prefix of all the REST/JSON admin API routes
+const GatewayPrefix = "/api/v1"
+
+var (
+	marshalOpts   = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+	unmarshalOpts = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// route maps a HTTP method and path to a method of the MikaService. The request message is
+// decoded from the JSON body, path params and query string. Path params and query args are
+// named after the request field they set, nested fields are separated by a "." in the query
+// string. Bytes fields are hex encoded when set from the path or query.
+type route struct {
+	rpc     string
+	method  string
+	path    string
+	summary string
+	// request is an instance of the request message, nil for methods taking no arguments
+	request proto.Message
+	// response is an instance of the response message
+	response proto.Message
+	// list is true for streaming methods, which are sent as a JSON array of response messages
+	list bool
+	// stream is true for client streaming methods, the request body is a JSON array of request
+	// messages which are received by the method in order
+	stream bool
+	// fields maps path params to the request field they set when the names differ
+	fields map[string]string
+	call   func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error)
+	// recv is called instead of call for client streaming methods
+	recv func(ctx context.Context, s *MikaService, reqs []proto.Message) ([]proto.Message, error)
+}
+
+func (r route) field(param string) string {
+	if f, found := r.fields[param]; found {
+		return f
+	}
+	return param
+}
+
+func unary(m proto.Message, err error) ([]proto.Message, error) {
+	if err != nil {
+		return nil, err
+	}
+	return []proto.Message{m}, nil
+}
+
+// collectStream implements grpc.ServerStream so the streaming methods of the MikaService can be
+// called by the gateway, collecting the messages sent instead of writing them to a connection.
+// Client streaming methods receive the reqs messages in order.
+type collectStream struct {
+	ctx  context.Context
+	msgs []proto.Message
+	reqs []proto.Message
+}
+
+func (s *collectStream) SetHeader(metadata.MD) error  { return nil }
+func (s *collectStream) SendHeader(metadata.MD) error { return nil }
+func (s *collectStream) SetTrailer(metadata.MD)       {}
+func (s *collectStream) Context() context.Context     { return s.ctx }
+func (s *collectStream) RecvMsg(m interface{}) error {
+	if len(s.reqs) == 0 {
+		return io.EOF
+	}
+	proto.Merge(m.(proto.Message), s.reqs[0])
+	s.reqs = s.reqs[1:]
+	return nil
+}
+func (s *collectStream) SendMsg(m interface{}) error {
+	s.msgs = append(s.msgs, m.(proto.Message))
+	return nil
+}
+
+type torrentStream struct{ *collectStream }
+
+func (s torrentStream) Send(m *pb.Torrent) error { return s.SendMsg(m) }
+
+type userStream struct{ *collectStream }
+
+func (s userStream) Send(m *pb.User) error { return s.SendMsg(m) }
+
+type roleStream struct{ *collectStream }
+
+func (s roleStream) Send(m *pb.Role) error { return s.SendMsg(m) }
+
+type peerStream struct{ *collectStream }
+
+func (s peerStream) Send(m *pb.Peer) error { return s.SendMsg(m) }
+
+type userImportStream struct{ *collectStream }
+
+func (s userImportStream) SendAndClose(m *pb.ImportResponse) error { return s.SendMsg(m) }
+func (s userImportStream) Recv() (*pb.UserImportParams, error) {
+	m := &pb.UserImportParams{}
+	return m, s.RecvMsg(m)
+}
+
+type torrentImportStream struct{ *collectStream }
+
+func (s torrentImportStream) SendAndClose(m *pb.ImportResponse) error { return s.SendMsg(m) }
+func (s torrentImportStream) Recv() (*pb.TorrentImportParams, error) {
+	m := &pb.TorrentImportParams{}
+	return m, s.RecvMsg(m)
+}
+
+// routes are all of the gateway routes. Subscribe is not available as it never completes, the
+// webhook dispatcher can be used instead.
+var routes = []route{
+	{rpc: "ConfigAll", method: http.MethodGet, path: "/config", summary: "Get the current config",
+		response: &pb.ConfigAllResponse{},
+		call: func(ctx context.Context, s *MikaService, _ proto.Message) ([]proto.Message, error) {
+			return unary(s.ConfigAll(ctx, &emptypb.Empty{}))
+		}},
+	{rpc: "ConfigSave", method: http.MethodPut, path: "/config", summary: "Update the config",
+		request: &pb.ConfigSaveParams{}, response: &emptypb.Empty{},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.ConfigSave(ctx, req.(*pb.ConfigSaveParams)))
+		}},
+	{rpc: "WhiteListAll", method: http.MethodGet, path: "/whitelist", summary: "List the whitelist and blacklist",
+		response: &pb.WhiteListAllResponse{},
+		call: func(ctx context.Context, s *MikaService, _ proto.Message) ([]proto.Message, error) {
+			return unary(s.WhiteListAll(ctx, &emptypb.Empty{}))
+		}},
+	{rpc: "WhiteListAdd", method: http.MethodPost, path: "/whitelist", summary: "Add a whitelist or blacklist entry",
+		request: &pb.WhiteList{}, response: &emptypb.Empty{},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.WhiteListAdd(ctx, req.(*pb.WhiteList)))
+		}},
+	{rpc: "WhiteListDelete", method: http.MethodDelete, path: "/whitelist/:code",
+		summary: "Delete a whitelist or blacklist entry", request: &pb.WhiteListDeleteParams{},
+		response: &emptypb.Empty{},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.WhiteListDelete(ctx, req.(*pb.WhiteListDeleteParams)))
+		}},
+	{rpc: "TorrentAll", method: http.MethodGet, path: "/torrents", summary: "List all torrents",
+		response: &pb.Torrent{}, list: true,
+		call: func(ctx context.Context, s *MikaService, _ proto.Message) ([]proto.Message, error) {
+			stream := &collectStream{ctx: ctx}
+			err := s.TorrentAll(&emptypb.Empty{}, torrentStream{stream})
+			return stream.msgs, err
+		}},
+	{rpc: "TorrentTop", method: http.MethodGet, path: "/top/torrents", summary: "Get the most active torrents",
+		request: &pb.TorrentTopParams{}, response: &pb.Torrent{},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.TorrentTop(ctx, req.(*pb.TorrentTopParams)))
+		}},
+	{rpc: "TorrentGet", method: http.MethodGet, path: "/torrents/:info_hash", summary: "Get a torrent",
+		request: &pb.InfoHashParam{}, response: &pb.Torrent{},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.TorrentGet(ctx, req.(*pb.InfoHashParam)))
+		}},
+	{rpc: "TorrentAdd", method: http.MethodPost, path: "/torrents", summary: "Add a torrent",
+		request: &pb.TorrentAddParams{}, response: &pb.Torrent{},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.TorrentAdd(ctx, req.(*pb.TorrentAddParams)))
+		}},
+	{rpc: "TorrentUpdate", method: http.MethodPut, path: "/torrents", summary: "Update a torrent",
+		request: &pb.TorrentUpdateParams{}, response: &pb.Torrent{},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.TorrentUpdate(ctx, req.(*pb.TorrentUpdateParams)))
+		}},
+	{rpc: "TorrentDelete", method: http.MethodDelete, path: "/torrents/:info_hash", summary: "Delete a torrent",
+		request: &pb.InfoHashParam{}, response: &emptypb.Empty{},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.TorrentDelete(ctx, req.(*pb.InfoHashParam)))
+		}},
+	{rpc: "TorrentImport", method: http.MethodPost, path: "/import/torrents",
+		summary: "Import a batch of torrents", request: &pb.TorrentImportParams{}, response: &pb.ImportResponse{},
+		stream: true,
+		recv: func(ctx context.Context, s *MikaService, reqs []proto.Message) ([]proto.Message, error) {
+			stream := &collectStream{ctx: ctx, reqs: reqs}
+			err := s.TorrentImport(torrentImportStream{stream})
+			return stream.msgs, err
+		}},
+	{rpc: "SwarmGet", method: http.MethodGet, path: "/torrents/:info_hash/peers",
+		summary: "List the peers in a torrents swarm", request: &pb.InfoHashParam{}, response: &pb.Peer{},
+		list: true,
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			stream := &collectStream{ctx: ctx}
+			err := s.SwarmGet(req.(*pb.InfoHashParam), peerStream{stream})
+			return stream.msgs, err
+		}},
+	{rpc: "TorrentSeries", method: http.MethodGet, path: "/torrents/:info_hash/series",
+		summary: "Get the activity of a torrent over time", request: &pb.TorrentSeriesParams{},
+		response: &pb.Series{},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.TorrentSeries(ctx, req.(*pb.TorrentSeriesParams)))
+		}},
+	{rpc: "PeerKick", method: http.MethodDelete, path: "/torrents/:info_hash/peers/:peer_id",
+		summary: "Remove a peer from a torrents swarm", request: &pb.PeerKickParams{}, response: &emptypb.Empty{},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.PeerKick(ctx, req.(*pb.PeerKickParams)))
+		}},
+	{rpc: "UserAll", method: http.MethodGet, path: "/users", summary: "List all users",
+		response: &pb.User{}, list: true,
+		call: func(ctx context.Context, s *MikaService, _ proto.Message) ([]proto.Message, error) {
+			stream := &collectStream{ctx: ctx}
+			err := s.UserAll(&emptypb.Empty{}, userStream{stream})
+			return stream.msgs, err
+		}},
+	{rpc: "UserGet", method: http.MethodGet, path: "/users/:user_id", summary: "Get a user",
+		request: &pb.UserID{}, response: &pb.User{},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.UserGet(ctx, req.(*pb.UserID)))
+		}},
+	{rpc: "UserAdd", method: http.MethodPost, path: "/users", summary: "Add a user",
+		request: &pb.UserAddParams{}, response: &pb.User{},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.UserAdd(ctx, req.(*pb.UserAddParams)))
+		}},
+	{rpc: "UserSave", method: http.MethodPut, path: "/users/:user_id", summary: "Update a user",
+		request: &pb.UserUpdateParams{}, response: &pb.User{},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.UserSave(ctx, req.(*pb.UserUpdateParams)))
+		}},
+	{rpc: "UserDelete", method: http.MethodDelete, path: "/users/:user_id", summary: "Delete a user",
+		request: &pb.UserID{}, response: &emptypb.Empty{},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.UserDelete(ctx, req.(*pb.UserID)))
+		}},
+	{rpc: "UserImport", method: http.MethodPost, path: "/import/users",
+		summary: "Import a batch of users", request: &pb.UserImportParams{}, response: &pb.ImportResponse{},
+		stream: true,
+		recv: func(ctx context.Context, s *MikaService, reqs []proto.Message) ([]proto.Message, error) {
+			stream := &collectStream{ctx: ctx, reqs: reqs}
+			err := s.UserImport(userImportStream{stream})
+			return stream.msgs, err
+		}},
+	{rpc: "PeersByUser", method: http.MethodGet, path: "/users/:user_id/peers",
+		summary: "List the active peers of a user", request: &pb.UserID{}, response: &pb.Peer{}, list: true,
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			stream := &collectStream{ctx: ctx}
+			err := s.PeersByUser(req.(*pb.UserID), peerStream{stream})
+			return stream.msgs, err
+		}},
+	{rpc: "UserSeries", method: http.MethodGet, path: "/users/:user_id/series",
+		summary: "Get the transfer of a user over time", request: &pb.UserSeriesParams{}, response: &pb.Series{},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.UserSeries(ctx, req.(*pb.UserSeriesParams)))
+		}},
+	{rpc: "RoleAll", method: http.MethodGet, path: "/roles", summary: "List all roles",
+		response: &pb.Role{}, list: true,
+		call: func(ctx context.Context, s *MikaService, _ proto.Message) ([]proto.Message, error) {
+			stream := &collectStream{ctx: ctx}
+			err := s.RoleAll(&emptypb.Empty{}, roleStream{stream})
+			return stream.msgs, err
+		}},
+	{rpc: "RoleAdd", method: http.MethodPost, path: "/roles", summary: "Add a role",
+		request: &pb.RoleAddParams{}, response: &pb.Role{},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.RoleAdd(ctx, req.(*pb.RoleAddParams)))
+		}},
+	{rpc: "RoleSave", method: http.MethodPut, path: "/roles/:role_id", summary: "Update a role",
+		request: &pb.Role{}, response: &emptypb.Empty{},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.RoleSave(ctx, req.(*pb.Role)))
+		}},
+	{rpc: "RoleDelete", method: http.MethodDelete, path: "/roles/:role_id", summary: "Delete a role",
+		request: &pb.RoleDeleteParams{}, response: &pb.RoleDeleteResponse{},
+		fields: map[string]string{"role_id": "role.role_id"},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.RoleDelete(ctx, req.(*pb.RoleDeleteParams)))
+		}},
+	{rpc: "AuditList", method: http.MethodGet, path: "/audit",
+		summary: "List the audit log of changes made through the API", request: &pb.AuditListParams{}, response: &pb.AuditListResponse{},
+		call: func(ctx context.Context, s *MikaService, req proto.Message) ([]proto.Message, error) {
+			return unary(s.AuditList(ctx, req.(*pb.AuditListParams)))
+		}},
+	{rpc: "Metrics", method: http.MethodGet, path: "/metrics", summary: "Get the tracker and runtime metrics",
+		response: &pb.RuntimeMetrics{},
+		call: func(ctx context.Context, s *MikaService, _ proto.Message) ([]proto.Message, error) {
+			return unary(s.Metrics(ctx, &emptypb.Empty{}))
+		}},
+}
+
+// httpStatus maps gRPC status codes to the closest HTTP status
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		return 499
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// apiError writes a JSON error response, eg: {"code": "NotFound", "error": "unknown infohash"}
+func apiError(c *gin.Context, code codes.Code, msg string) {
+	c.AbortWithStatusJSON(httpStatus(code), gin.H{"code": code.String(), "error": msg})
+}
+
+// parseValue converts the string into a value suitable for the field
+func parseValue(fd protoreflect.FieldDescriptor, value string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(value), nil
+	case protoreflect.BytesKind:
+		b, err := hex.DecodeString(value)
+		return protoreflect.ValueOfBytes(b), err
+	case protoreflect.BoolKind:
+		v, err := strconv.ParseBool(value)
+		return protoreflect.ValueOfBool(v), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v, err := strconv.ParseInt(value, 10, 32)
+		return protoreflect.ValueOfInt32(int32(v)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err := strconv.ParseInt(value, 10, 64)
+		return protoreflect.ValueOfInt64(v), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		v, err := strconv.ParseUint(value, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(v)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		v, err := strconv.ParseUint(value, 10, 64)
+		return protoreflect.ValueOfUint64(v), err
+	case protoreflect.FloatKind:
+		v, err := strconv.ParseFloat(value, 32)
+		return protoreflect.ValueOfFloat32(float32(v)), err
+	case protoreflect.DoubleKind:
+		v, err := strconv.ParseFloat(value, 64)
+		return protoreflect.ValueOfFloat64(v), err
+	case protoreflect.EnumKind:
+		// Enums accept either the value name, eg: hour, or its number
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(strings.ToUpper(value))); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		v, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		if fd.Enum().Values().ByNumber(protoreflect.EnumNumber(v)) == nil {
+			return protoreflect.Value{}, errors.Errorf("unknown enum value: %s", value)
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(v)), nil
+	case protoreflect.MessageKind:
+		// Timestamps are accepted in RFC3339 format, eg: 2020-06-01T12:00:00Z
+		if fd.Message().FullName() == "google.protobuf.Timestamp" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return protoreflect.Value{}, err
+			}
+			return protoreflect.ValueOfMessage(timestamppb.New(t).ProtoReflect()), nil
+		}
+	}
+	return protoreflect.Value{}, errors.Errorf("unsupported field type: %s", fd.Kind())
+}
+
+// setField sets the field at the "." separated path of the message from its string form
+func setField(msg protoreflect.Message, path string, value string) error {
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		fd := msg.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if fd == nil || fd.IsList() || fd.IsMap() {
+			return errors.Errorf("unknown field: %s", path)
+		}
+		if i < len(names)-1 {
+			if fd.Kind() != protoreflect.MessageKind {
+				return errors.Errorf("unknown field: %s", path)
+			}
+			msg = msg.Mutable(fd).Message()
+			continue
+		}
+		v, err := parseValue(fd, value)
+		if err != nil {
+			return errors.Errorf("invalid value for %s", path)
+		}
+		msg.Set(fd, v)
+	}
+	return nil
+}
+
+// decodeRequest builds the request message from the body, path params and query string
+func decodeRequest(c *gin.Context, rt route) (proto.Message, error) {
+	req := rt.request.ProtoReflect().New().Interface()
+	body, err := c.GetRawData()
+	if err != nil {
+		return nil, errors.New("failed to read request body")
+	}
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := unmarshalOpts.Unmarshal(body, req); err != nil {
+			return nil, errors.Errorf("invalid request body: %v", err)
+		}
+	}
+	for key, values := range c.Request.URL.Query() {
+		if err := setField(req.ProtoReflect(), key, values[0]); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range c.Params {
+		if err := setField(req.ProtoReflect(), rt.field(p.Key), p.Value); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// decodeRequests builds the request messages of a client streaming method from the JSON array body
+func decodeRequests(c *gin.Context, rt route) ([]proto.Message, error) {
+	body, err := c.GetRawData()
+	if err != nil {
+		return nil, errors.New("failed to read request body")
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, errors.New("invalid request body: expected an array")
+	}
+	reqs := make([]proto.Message, len(items))
+	for i, item := range items {
+		reqs[i] = rt.request.ProtoReflect().New().Interface()
+		if err := unmarshalOpts.Unmarshal(item, reqs[i]); err != nil {
+			return nil, errors.Errorf("invalid request body item %d: %v", i, err)
+		}
+	}
+	return reqs, nil
+}
+
+func (rt route) handler(s *MikaService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			msgs []proto.Message
+			err  error
+		)
+		if rt.stream {
+			reqs, errDecode := decodeRequests(c, rt)
+			if errDecode != nil {
+				apiError(c, codes.InvalidArgument, errDecode.Error())
+				return
+			}
+			msgs, err = rt.recv(c.Request.Context(), s, reqs)
+		} else {
+			var req proto.Message
+			if rt.request != nil {
+				r, errDecode := decodeRequest(c, rt)
+				if errDecode != nil {
+					apiError(c, codes.InvalidArgument, errDecode.Error())
+					return
+				}
+				req = r
+			}
+			msgs, err = rt.call(c.Request.Context(), s, req)
+		}
+		if err != nil {
+			st := status.Convert(err)
+			apiError(c, st.Code(), st.Message())
+			return
+		}
+		var body []byte
+		if rt.list {
+			items := make([]json.RawMessage, len(msgs))
+			for i, m := range msgs {
+				items[i], err = marshalOpts.Marshal(m)
+				if err != nil {
+					break
+				}
+			}
+			if err == nil {
+				body, err = json.Marshal(items)
+			}
+		} else {
+			body, err = marshalOpts.Marshal(msgs[0])
+		}
+		if err != nil {
+			log.Errorf("Failed to encode %s response: %v", rt.rpc, err)
+			apiError(c, codes.Internal, "failed to encode response")
+			return
+		}
+		c.Data(http.StatusOK, "application/json", body)
+	}
+}
+
+// apiKeyAuth requires an api key in the Authorization header, eg: Authorization: Bearer <key>
+func apiKeyAuth(keys APIKeys) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name, ok := keys.identify(bearerKey(c.GetHeader("Authorization")))
+		if !ok {
+			apiError(c, codes.Unauthenticated, "invalid api key")
+			return
+		}
+		c.Request = c.Request.WithContext(withCaller(c.Request.Context(), Caller{Name: name, Address: c.ClientIP()}))
+		c.Next()
+	}
+}
+
+// NewGateway creates the REST/JSON admin API handler. Each route calls the same MikaService
+// method used for gRPC requests. The OpenAPI document describing the routes is served without
+// authentication at /api/v1/openapi.json. The metrics are served without authentication in the
+// prometheus text format at /metrics.
+func NewGateway(s *MikaService, keys APIKeys) *gin.Engine {
+	r := gin.New()
+	r.Use(ginlogrus.Logger(log.New()), gin.Recovery())
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+	api := r.Group(GatewayPrefix)
+	api.GET("/openapi.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, OpenAPI())
+	})
+	authed := api.Group("", apiKeyAuth(keys))
+	for _, rt := range routes {
+		authed.Handle(rt.method, rt.path, rt.handler(s))
+	}
+	return r
+}
+
+// NewAPIHandler serves both the gRPC service and the REST gateway on the same listener.
+// gRPC clients connect using HTTP/2 without TLS (h2c), everything else is sent to the gateway.
+func NewAPIHandler(grpcServer *grpc.Server, gateway http.Handler) http.Handler {
+	return h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		gateway.ServeHTTP(w, r)
+	}), &http2.Server{})
+}
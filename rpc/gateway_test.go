@@ -0,0 +1,296 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/leighmacdonald/mika/metrics"
+	pb "github.com/leighmacdonald/mika/proto"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/leighmacdonald/mika/tracker"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testKey = "test-api-key"
+
+var testKeys = NewAPIKeys("", map[string]string{"test": testKey})
+
+func request(t *testing.T, h http.Handler, method string, path string, body string, key string) (int, []byte) {
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, GatewayPrefix+path, r)
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	b, err := ioutil.ReadAll(w.Body)
+	require.NoError(t, err)
+	return w.Code, b
+}
+
+func TestGateway(t *testing.T) {
+	h := NewGateway(&MikaService{}, testKeys)
+	code, _ := request(t, h, "GET", "/torrents", "", "")
+	require.Equal(t, http.StatusUnauthorized, code)
+	code, _ = request(t, h, "GET", "/torrents", "", "invalid")
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	tor := store.GenerateTestTorrent()
+	body, err := json.Marshal(map[string]interface{}{"info_hash": tor.InfoHash.Bytes(), "title": "gateway"})
+	require.NoError(t, err)
+	code, b := request(t, h, "POST", "/torrents", string(body), testKey)
+	require.Equal(t, http.StatusOK, code, string(b))
+
+	code, b = request(t, h, "GET", "/torrents/"+tor.InfoHash.String(), "", testKey)
+	require.Equal(t, http.StatusOK, code, string(b))
+	var torrent map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &torrent))
+	require.Equal(t, "gateway", torrent["title"])
+	// Unset fields are included
+	require.Contains(t, torrent, "snatches")
+
+	code, b = request(t, h, "GET", "/torrents", "", testKey)
+	require.Equal(t, http.StatusOK, code, string(b))
+	var torrents []map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &torrents))
+	require.NotEmpty(t, torrents)
+
+	code, b = request(t, h, "GET", "/torrents/"+tor.InfoHash.String()+"/peers", "", testKey)
+	require.Equal(t, http.StatusOK, code, string(b))
+	require.Equal(t, "[]", string(b))
+
+	code, _ = request(t, h, "GET", "/torrents/xyz", "", testKey)
+	require.Equal(t, http.StatusBadRequest, code)
+	code, _ = request(t, h, "GET", "/torrents/"+tor.InfoHash.String()+"?unknown=1", "", testKey)
+	require.Equal(t, http.StatusBadRequest, code)
+	code, _ = request(t, h, "POST", "/torrents", "{", testKey)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, b = request(t, h, "DELETE", "/torrents/"+tor.InfoHash.String(), "", testKey)
+	require.Equal(t, http.StatusOK, code, string(b))
+	require.Equal(t, "{}", string(b))
+
+	code, b = request(t, h, "GET", "/metrics", "", testKey)
+	require.Equal(t, http.StatusOK, code, string(b))
+	var m map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &m))
+	require.NotZero(t, m["go_routines"])
+
+	code, b = request(t, h, "GET", "/users/999999", "", testKey)
+	require.Equal(t, http.StatusNotFound, code, string(b))
+	var apiErr map[string]string
+	require.NoError(t, json.Unmarshal(b, &apiErr))
+	require.Equal(t, "NotFound", apiErr["code"])
+}
+
+func TestOpenAPI(t *testing.T) {
+	h := NewGateway(&MikaService{}, testKeys)
+	code, b := request(t, h, "GET", "/openapi.json", "", "")
+	require.Equal(t, http.StatusOK, code)
+	var doc struct {
+		Paths map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(b, &doc))
+	ops := map[string]bool{}
+	for path, methods := range doc.Paths {
+		for method, op := range methods {
+			ops[op.OperationID] = true
+			require.True(t, strings.HasPrefix(path, GatewayPrefix), "%s %s", method, path)
+		}
+	}
+	// Every service method except the Subscribe stream must be available
+	methods := pb.File_proto_mika_proto.Services().ByName("Mika").Methods()
+	for i := 0; i < methods.Len(); i++ {
+		name := string(methods.Get(i).Name())
+		if name == "Subscribe" {
+			continue
+		}
+		require.True(t, ops[name], "missing route for %s", name)
+	}
+	require.Contains(t, doc.Components.Schemas, "Torrent")
+	require.Contains(t, doc.Components.Schemas, "TimeMeta")
+}
+
+func TestAPIHandler(t *testing.T) {
+	grpcServer := grpc.NewServer(testKeys.ServerOptions()...)
+	pb.RegisterMikaServer(grpcServer, &MikaService{})
+	ts := httptest.NewServer(NewAPIHandler(grpcServer, NewGateway(&MikaService{}, testKeys)))
+	defer ts.Close()
+
+	tor := store.GenerateTestTorrent()
+	conn, err := grpc.Dial(strings.TrimPrefix(ts.URL, "http://"), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	cl := pb.NewMikaClient(conn)
+	_, err = cl.TorrentAdd(context.Background(), &pb.TorrentAddParams{InfoHash: tor.InfoHash.Bytes(), Title: "h2c"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+testKey)
+	_, err = cl.TorrentAdd(ctx, &pb.TorrentAddParams{InfoHash: tor.InfoHash.Bytes(), Title: "h2c"})
+	require.NoError(t, err)
+
+	// The torrent added over gRPC is visible from the gateway on the same listener
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s/torrents/%s", ts.URL, GatewayPrefix, tor.InfoHash), nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+testKey)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	b, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"title":"h2c"`)
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	h := NewGateway(&MikaService{}, testKeys)
+	// Prometheus scrapes without the api key
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "# TYPE t_ann_total counter")
+	require.Contains(t, w.Body.String(), `t_store_info{driver="memory"} 1`)
+
+	code, b := request(t, h, "GET", "/metrics", "", testKey)
+	require.Equal(t, http.StatusOK, code, string(b))
+	var m pb.RuntimeMetrics
+	require.NoError(t, unmarshalOpts.Unmarshal(b, &m))
+	require.Equal(t, metrics.LatencyBuckets, m.AnnounceLatency.Buckets)
+	require.Len(t, m.ScrapeLatency.Counts, len(metrics.LatencyBuckets))
+	require.Equal(t, metrics.SwarmBuckets, m.SwarmSizes.Buckets)
+	for _, kind := range metrics.StoreSyncKinds {
+		require.Contains(t, m.StoreSyncDuration, kind)
+	}
+	snap := metrics.NewHistogramSnapshot(metrics.LatencyBuckets, []float64{0.001, 0.2})
+	require.Equal(t, snap, PBToHistogram(HistogramToPB(snap)))
+}
+
+func TestGatewayImport(t *testing.T) {
+	h := NewGateway(&MikaService{}, testKeys)
+	tor := store.GenerateTestTorrent()
+	body, err := json.Marshal([]map[string]interface{}{
+		{"row": 1, "torrent": map[string]interface{}{"info_hash": tor.InfoHash.Bytes(), "snatches": 5},
+			"options": map[string]interface{}{"dry_run": true}},
+		{"row": 2, "torrent": map[string]interface{}{"info_hash": []byte("short")}},
+	})
+	require.NoError(t, err)
+	code, b := request(t, h, "POST", "/import/torrents", string(body), testKey)
+	require.Equal(t, http.StatusOK, code, string(b))
+	var resp pb.ImportResponse
+	require.NoError(t, unmarshalOpts.Unmarshal(b, &resp))
+	require.True(t, resp.DryRun)
+	require.EqualValues(t, 1, resp.Created)
+	require.EqualValues(t, 1, resp.Failed)
+	require.EqualValues(t, 2, resp.Errors[0].Row)
+
+	code, b = request(t, h, "GET", "/torrents/"+tor.InfoHash.String(), "", testKey)
+	require.NotEqual(t, http.StatusOK, code, string(b))
+
+	code, _ = request(t, h, "POST", "/import/torrents", "{}", testKey)
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestGatewaySeries(t *testing.T) {
+	h := NewGateway(&MikaService{}, testKeys)
+	tor := store.GenerateTestTorrent()
+	body, err := json.Marshal(map[string]interface{}{"info_hash": tor.InfoHash.Bytes(), "title": "series"})
+	require.NoError(t, err)
+	code, b := request(t, h, "POST", "/torrents", string(body), testKey)
+	require.Equal(t, http.StatusOK, code, string(b))
+
+	code, b = request(t, h, "GET", "/torrents/"+tor.InfoHash.String()+"/series?resolution=hour&limit=3", "", testKey)
+	require.Equal(t, http.StatusOK, code, string(b))
+	var series pb.Series
+	require.NoError(t, unmarshalOpts.Unmarshal(b, &series))
+	require.Equal(t, pb.SeriesResolution_HOUR, series.Resolution)
+	require.Len(t, series.Points, 3)
+	require.True(t, series.Points[0].Time.AsTime().Before(series.Points[2].Time.AsTime()))
+
+	code, _ = request(t, h, "GET", "/torrents/"+tor.InfoHash.String()+"/series?resolution=2", "", testKey)
+	require.Equal(t, http.StatusOK, code)
+	code, _ = request(t, h, "GET", "/torrents/"+tor.InfoHash.String()+"/series?resolution=week", "", testKey)
+	require.Equal(t, http.StatusBadRequest, code)
+	code, _ = request(t, h, "GET", "/torrents/"+store.GenerateTestTorrent().InfoHash.String()+"/series", "", testKey)
+	require.Equal(t, http.StatusNotFound, code)
+	code, _ = request(t, h, "GET", "/users/4294967295/series", "", testKey)
+	require.Equal(t, http.StatusNotFound, code)
+}
+
+func TestGatewayAudit(t *testing.T) {
+	h := NewGateway(&MikaService{}, testKeys)
+	start := time.Now().Add(-time.Second).UTC().Format(time.RFC3339)
+	tor := store.GenerateTestTorrent()
+	body, err := json.Marshal(map[string]interface{}{"info_hash": tor.InfoHash.Bytes(), "title": "audited"})
+	require.NoError(t, err)
+	code, b := request(t, h, "POST", "/torrents", string(body), testKey)
+	require.Equal(t, http.StatusOK, code, string(b))
+	code, b = request(t, h, "DELETE", "/torrents/"+tor.InfoHash.String(), "", testKey)
+	require.Equal(t, http.StatusOK, code, string(b))
+
+	code, b = request(t, h, "GET", "/audit?action=torrent&target=torrent:"+tor.InfoHash.String()+"&since="+start, "", testKey)
+	require.Equal(t, http.StatusOK, code, string(b))
+	var resp struct {
+		Entries []struct {
+			Actor  string `json:"actor"`
+			Action string `json:"action"`
+			Before string `json:"before"`
+			After  string `json:"after"`
+		} `json:"entries"`
+	}
+	require.NoError(t, json.Unmarshal(b, &resp))
+	require.Len(t, resp.Entries, 2)
+	require.Equal(t, "torrent.delete", resp.Entries[0].Action)
+	require.Equal(t, "test", resp.Entries[0].Actor)
+	require.Contains(t, resp.Entries[0].Before, `"title":"audited"`)
+	require.Empty(t, resp.Entries[0].After)
+	require.Equal(t, "torrent.add", resp.Entries[1].Action)
+	require.Empty(t, resp.Entries[1].Before)
+	require.Contains(t, resp.Entries[1].After, `"title":"audited"`)
+
+	code, _ = request(t, h, "GET", "/audit?since=yesterday", "", testKey)
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestAuditUserPasskey(t *testing.T) {
+	role := store.GenerateTestRole()
+	require.NoError(t, tracker.RoleAdd(&role))
+	ctx := withCaller(context.Background(), Caller{Name: "test"})
+	s := &MikaService{}
+	usr, err := s.UserAdd(ctx, &pb.UserAddParams{RoleId: role.RoleID, UserName: "audited", DownloadEnabled: true})
+	require.NoError(t, err)
+	require.NotEmpty(t, usr.Passkey, "the passkey is still returned to the caller")
+	_, err = s.UserSave(ctx, &pb.UserUpdateParams{UserId: usr.UserId, RoleId: role.RoleID, UserName: "renamed",
+		Passkey: "updatedpasskey000000"})
+	require.NoError(t, err)
+	_, err = s.UserDelete(ctx, &pb.UserID{UserId: usr.UserId})
+	require.NoError(t, err)
+
+	entries, err := tracker.AuditList(store.AuditFilter{Target: userTarget(usr.UserId)})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	for _, e := range entries {
+		require.NotContains(t, string(e.Before), usr.Passkey, e.Action)
+		require.NotContains(t, string(e.After), usr.Passkey, e.Action)
+		require.NotContains(t, string(e.Before), "updatedpasskey", e.Action)
+		require.NotContains(t, string(e.After), "updatedpasskey", e.Action)
+	}
+}
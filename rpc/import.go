@@ -0,0 +1,192 @@
+package rpc
+
+import (
+	pb "github.com/leighmacdonald/mika/proto"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/leighmacdonald/mika/tracker"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"sort"
+)
+
+// importRow tracks the source row of each imported item so errors can be reported against it
+type importRow struct {
+	row uint32
+	err error
+}
+
+func PBToImportOptions(o *pb.ImportOptions) tracker.ImportOptions {
+	return tracker.ImportOptions{Upsert: o.GetUpsert(), DryRun: o.GetDryRun()}
+}
+
+// importResultToPB converts the result of an import to its protobuf form. rows holds the source
+// row of each item of the imported batch followed by any rows which failed to decode.
+func importResultToPB(res tracker.ImportResult, rows []importRow, dryRun bool) *pb.ImportResponse {
+	resp := &pb.ImportResponse{
+		Created: uint32(res.Created),
+		Updated: uint32(res.Updated),
+		DryRun:  dryRun,
+	}
+	for i, err := range res.Errors {
+		rows[i].err = err
+	}
+	for _, r := range rows {
+		if r.err != nil {
+			resp.Errors = append(resp.Errors, &pb.ImportError{Row: r.row, Error: r.err.Error()})
+		}
+	}
+	sort.Slice(resp.Errors, func(i, j int) bool {
+		return resp.Errors[i].Row < resp.Errors[j].Row
+	})
+	resp.Failed = uint32(len(resp.Errors))
+	return resp
+}
+
+// importUser converts a user read from an import stream. Unlike PBToUser the timestamps and
+// role are optional and ignored.
+func importUser(p *pb.User) (*store.User, error) {
+	if p == nil {
+		return nil, errors.New("missing user")
+	}
+	return &store.User{
+		RoleID:          p.RoleId,
+		RemoteID:        p.RemoteId,
+		UserName:        p.UserName,
+		Passkey:         p.Passkey,
+		DownloadEnabled: p.DownloadEnabled,
+		Downloaded:      p.Downloaded,
+		Uploaded:        p.Uploaded,
+		Announces:       p.Announces,
+	}, nil
+}
+
+// importTorrent converts a torrent read from an import stream, validating the infohashes
+func importTorrent(p *pb.Torrent) (*store.Torrent, error) {
+	var (
+		ih   store.InfoHash
+		ihV2 store.InfoHashV2
+	)
+	if p == nil {
+		return nil, errors.New("missing torrent")
+	}
+	if len(p.InfoHash) == 0 && len(p.InfoHashV2) == 0 {
+		return nil, errors.New("info_hash or info_hash_v2 required")
+	}
+	if len(p.InfoHash) > 0 {
+		if len(p.InfoHash) != 20 {
+			return nil, errors.New("invalid info_hash")
+		}
+		_ = store.InfoHashFromBytes(&ih, p.InfoHash)
+	}
+	if len(p.InfoHashV2) > 0 {
+		if err := store.InfoHashV2FromBytes(&ihV2, p.InfoHashV2); err != nil {
+			return nil, errors.New("invalid info_hash_v2")
+		}
+	}
+	return &store.Torrent{
+		InfoHash:   ih,
+		InfoHashV2: ihV2,
+		Title:      p.Title,
+		IsEnabled:  p.IsEnabled,
+		Reason:     p.Reason,
+		MultiUp:    p.MultiUp,
+		MultiDn:    p.MultiDn,
+		Snatches:   p.Snatches,
+		Uploaded:   p.Uploaded,
+		Downloaded: p.Downloaded,
+		Announces:  p.Announces,
+	}, nil
+}
+
+// UserImport reads every user from the stream and imports them as a single batch. The import
+// options are read from the first message. Rows which fail are reported in the response
+// instead of failing the whole import.
+func (s *MikaService) UserImport(stream pb.Mika_UserImportServer) error {
+	var (
+		batch   []*store.User
+		rows    []importRow
+		invalid []importRow
+		opts    *pb.ImportOptions
+	)
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if opts == nil {
+			opts = in.Options
+			if opts == nil {
+				opts = &pb.ImportOptions{}
+			}
+		}
+		u, err := importUser(in.User)
+		if err != nil {
+			invalid = append(invalid, importRow{row: in.Row, err: err})
+			continue
+		}
+		batch = append(batch, u)
+		rows = append(rows, importRow{row: in.Row})
+	}
+	if opts == nil {
+		return status.Errorf(codes.InvalidArgument, "no users to import")
+	}
+	res := tracker.UserImport(batch, PBToImportOptions(opts))
+	resp := importResultToPB(res, append(rows, invalid...), opts.DryRun)
+	log.WithFields(log.Fields{"rows": len(rows) + len(invalid), "failed": resp.Failed}).
+		Debug("Imported users")
+	if !opts.DryRun {
+		audit(stream.Context(), "user.import", "", nil, resp)
+	}
+	return stream.SendAndClose(resp)
+}
+
+// TorrentImport reads every torrent from the stream and imports them as a single batch. The
+// import options are read from the first message. Rows which fail are reported in the response
+// instead of failing the whole import.
+func (s *MikaService) TorrentImport(stream pb.Mika_TorrentImportServer) error {
+	var (
+		batch   []*store.Torrent
+		rows    []importRow
+		invalid []importRow
+		opts    *pb.ImportOptions
+	)
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if opts == nil {
+			opts = in.Options
+			if opts == nil {
+				opts = &pb.ImportOptions{}
+			}
+		}
+		t, err := importTorrent(in.Torrent)
+		if err != nil {
+			invalid = append(invalid, importRow{row: in.Row, err: err})
+			continue
+		}
+		batch = append(batch, t)
+		rows = append(rows, importRow{row: in.Row})
+	}
+	if opts == nil {
+		return status.Errorf(codes.InvalidArgument, "no torrents to import")
+	}
+	res := tracker.TorrentImport(batch, PBToImportOptions(opts))
+	resp := importResultToPB(res, append(rows, invalid...), opts.DryRun)
+	log.WithFields(log.Fields{"rows": len(rows) + len(invalid), "failed": resp.Failed}).
+		Debug("Imported torrents")
+	if !opts.DryRun {
+		audit(stream.Context(), "torrent.import", "", nil, resp)
+	}
+	return stream.SendAndClose(resp)
+}
@@ -0,0 +1,71 @@
+package rpc
+
+import (
+	"context"
+	"github.com/leighmacdonald/mika/metrics"
+	pb "github.com/leighmacdonald/mika/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func HistogramToPB(h metrics.HistogramSnapshot) *pb.Histogram {
+	return &pb.Histogram{
+		Buckets: h.Buckets,
+		Counts:  h.Counts,
+		Count:   h.Count,
+		Sum:     h.Sum,
+	}
+}
+
+func PBToHistogram(h *pb.Histogram) metrics.HistogramSnapshot {
+	return metrics.HistogramSnapshot{
+		Buckets: h.GetBuckets(),
+		Counts:  h.GetCounts(),
+		Count:   h.GetCount(),
+		Sum:     h.GetSum(),
+	}
+}
+
+func RuntimeMetricsToPB(m metrics.RuntimeMetrics) *pb.RuntimeMetrics {
+	syncDuration := make(map[string]*pb.Histogram, len(m.StoreSyncDuration))
+	for kind, h := range m.StoreSyncDuration {
+		syncDuration[kind] = HistogramToPB(h)
+	}
+	return &pb.RuntimeMetrics{
+		TorrentsTotalCached:           m.TorrentsTotalCached,
+		UsersTotalCached:              m.UsersTotalCached,
+		PeersTotalCached:              m.PeersTotalCached,
+		AnnounceTotal:                 m.AnnounceTotal,
+		AnnounceStatusOk:              m.AnnounceStatusOK,
+		AnnounceStatusUnauthorized:    m.AnnounceStatusUnauthorized,
+		AnnounceStatusInvalidInfoHash: m.AnnounceStatusInvalidInfoHash,
+		AnnounceStatusMalformed:       m.AnnounceStatusMalformed,
+		AnnounceStatusThrottled:       m.AnnounceStatusThrottled,
+		AnnounceStatusError:           m.AnnounceStatusError,
+		AnnounceClientSpoofed:         m.AnnounceClientSpoofed,
+		AnnounceClients:               m.AnnounceClients,
+		EventsDropped:                 m.EventsDropped,
+		NumGc:                         m.NumGC,
+		PauseTotal:                    m.PauseTotal,
+		GoRoutines:                    int64(m.GoRoutines),
+		AllocHeap:                     m.AllocHeap,
+		AllocTotal:                    m.AllocTotal,
+		MemSys:                        m.MemSys,
+		HeapInUse:                     m.HeapInUse,
+		HeapObjects:                   m.HeapObjects,
+		GcPauseTotalNs:                m.GCPauseTotalNS,
+		GcCpuFraction:                 m.GCCPUFraction,
+		AnnounceLatency:               HistogramToPB(m.AnnounceLatency),
+		ScrapeTotal:                   m.ScrapeTotal,
+		ScrapeLatency:                 HistogramToPB(m.ScrapeLatency),
+		Events:                        m.Events,
+		StoreSyncDuration:             syncDuration,
+		StoreSyncErrors:               m.StoreSyncErrors,
+		SwarmSizes:                    HistogramToPB(m.SwarmSizes),
+	}
+}
+
+// Metrics returns a snapshot of the tracker and go runtime metrics. The counters are totals
+// since the tracker was started.
+func (s *MikaService) Metrics(context.Context, *emptypb.Empty) (*pb.RuntimeMetrics, error) {
+	return RuntimeMetricsToPB(metrics.Get()), nil
+}
@@ -0,0 +1,244 @@
+package rpc
+
+import (
+	"github.com/leighmacdonald/mika/consts"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"strings"
+)
+
+// schemaRef returns a reference to the component schema of the message
+func schemaRef(md protoreflect.MessageDescriptor) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + string(md.Name())}
+}
+
+// fieldSchema returns the schema of a single value of the field, matching the protojson encoding
+func fieldSchema(fd protoreflect.FieldDescriptor, schemas map[string]interface{}) map[string]interface{} {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]interface{}{"type": "boolean"}
+	case protoreflect.StringKind:
+		return map[string]interface{}{"type": "string"}
+	case protoreflect.BytesKind:
+		return map[string]interface{}{"type": "string", "format": "byte"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// 64bit integers are encoded as strings so they are not truncated by javascript clients
+		return map[string]interface{}{"type": "string", "format": "int64"}
+	case protoreflect.FloatKind:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case protoreflect.EnumKind:
+		var names []string
+		values := fd.Enum().Values()
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, string(values.Get(i).Name()))
+		}
+		return map[string]interface{}{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		switch fd.Message().FullName() {
+		case "google.protobuf.Timestamp":
+			return map[string]interface{}{"type": "string", "format": "date-time"}
+		case "google.protobuf.Duration":
+			return map[string]interface{}{"type": "string"}
+		}
+		addSchema(fd.Message(), schemas)
+		return schemaRef(fd.Message())
+	}
+	return map[string]interface{}{}
+}
+
+// addSchema adds the component schema of the message, and any messages it references
+func addSchema(md protoreflect.MessageDescriptor, schemas map[string]interface{}) {
+	name := string(md.Name())
+	if _, found := schemas[name]; found {
+		return
+	}
+	props := map[string]interface{}{}
+	schema := map[string]interface{}{"type": "object", "properties": props}
+	// Added before the fields to stop recursive messages looping forever
+	schemas[name] = schema
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		var prop map[string]interface{}
+		switch {
+		case fd.IsMap():
+			prop = map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": fieldSchema(fd.MapValue(), schemas),
+			}
+		case fd.IsList():
+			prop = map[string]interface{}{"type": "array", "items": fieldSchema(fd, schemas)}
+		default:
+			prop = fieldSchema(fd, schemas)
+		}
+		props[string(fd.Name())] = prop
+	}
+}
+
+// fieldByPath returns the field at the "." separated path of the message
+func fieldByPath(md protoreflect.MessageDescriptor, path string) protoreflect.FieldDescriptor {
+	var fd protoreflect.FieldDescriptor
+	for _, name := range strings.Split(path, ".") {
+		if md == nil {
+			return nil
+		}
+		fd = md.Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			return nil
+		}
+		md = fd.Message()
+	}
+	return fd
+}
+
+// paramSchema returns the schema of a path or query param, bytes are hex encoded
+func paramSchema(fd protoreflect.FieldDescriptor) map[string]interface{} {
+	if fd.Kind() == protoreflect.BytesKind {
+		return map[string]interface{}{"type": "string", "format": "hex"}
+	}
+	return fieldSchema(fd, nil)
+}
+
+// openAPIPath converts the gin route path into the OpenAPI path template format
+func openAPIPath(path string) (string, []string) {
+	var params []string
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") {
+			params = append(params, part[1:])
+			parts[i] = "{" + part[1:] + "}"
+		}
+	}
+	return GatewayPrefix + strings.Join(parts, "/"), params
+}
+
+func routeOperation(rt route, schemas map[string]interface{}) map[string]interface{} {
+	_, pathParams := openAPIPath(rt.path)
+	op := map[string]interface{}{
+		"operationId": rt.rpc,
+		"summary":     rt.summary,
+		"tags":        []string{strings.Split(strings.TrimPrefix(rt.path, "/"), "/")[0]},
+	}
+	var params []interface{}
+	inPath := map[string]bool{}
+	for _, p := range pathParams {
+		field := rt.field(p)
+		inPath[field] = true
+		fd := fieldByPath(rt.request.ProtoReflect().Descriptor(), field)
+		params = append(params, map[string]interface{}{
+			"name":     p,
+			"in":       "path",
+			"required": true,
+			"schema":   paramSchema(fd),
+		})
+	}
+	if rt.request != nil {
+		md := rt.request.ProtoReflect().Descriptor()
+		if rt.method == "GET" || rt.method == "DELETE" {
+			// Scalar and timestamp fields which are not already set by the path can be set using the query string
+			fields := md.Fields()
+			for i := 0; i < fields.Len(); i++ {
+				fd := fields.Get(i)
+				if inPath[string(fd.Name())] || fd.IsList() || fd.IsMap() ||
+					(fd.Message() != nil && fd.Message().FullName() != "google.protobuf.Timestamp") {
+					continue
+				}
+				params = append(params, map[string]interface{}{
+					"name":   string(fd.Name()),
+					"in":     "query",
+					"schema": paramSchema(fd),
+				})
+			}
+		} else {
+			addSchema(md, schemas)
+			reqSchema := schemaRef(md)
+			if rt.stream {
+				reqSchema = map[string]interface{}{"type": "array", "items": reqSchema}
+			}
+			op["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": reqSchema},
+				},
+			}
+		}
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+	md := rt.response.ProtoReflect().Descriptor()
+	addSchema(md, schemas)
+	schema := schemaRef(md)
+	if rt.list {
+		schema = map[string]interface{}{"type": "array", "items": schema}
+	}
+	op["responses"] = map[string]interface{}{
+		"200": map[string]interface{}{
+			"description": "OK",
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": schema},
+			},
+		},
+		"default": map[string]interface{}{
+			"description": "Error",
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": map[string]interface{}{"$ref": "#/components/schemas/Error"},
+				},
+			},
+		},
+	}
+	return op
+}
+
+// OpenAPI returns the OpenAPI 3 document describing the REST/JSON admin API. It is generated
+// from the gateway routes and the protobuf message descriptors so it always matches the
+// gRPC service.
+func OpenAPI() map[string]interface{} {
+	schemas := map[string]interface{}{
+		"Error": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"code":  map[string]interface{}{"type": "string"},
+				"error": map[string]interface{}{"type": "string"},
+			},
+		},
+	}
+	paths := map[string]interface{}{}
+	for _, rt := range routes {
+		path, _ := openAPIPath(rt.path)
+		item, found := paths[path].(map[string]interface{})
+		if !found {
+			item = map[string]interface{}{}
+			paths[path] = item
+		}
+		item[strings.ToLower(rt.method)] = routeOperation(rt, schemas)
+	}
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "mika admin API",
+			"version": consts.BuildVersion,
+			"description": "REST/JSON mirror of the mika gRPC admin service. Bytes fields are base64 encoded " +
+				"in JSON bodies and hex encoded in paths and query strings.",
+		},
+		"servers":  []interface{}{map[string]interface{}{"url": "/"}},
+		"paths":    paths,
+		"security": []interface{}{map[string]interface{}{"bearerAuth": []string{}}},
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "The api.key value from the tracker config",
+				},
+			},
+		},
+	}
+}
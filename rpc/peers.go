@@ -0,0 +1,140 @@
+package rpc
+
+import (
+	"context"
+	"github.com/leighmacdonald/mika/consts"
+	"github.com/leighmacdonald/mika/geo"
+	pb "github.com/leighmacdonald/mika/proto"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/leighmacdonald/mika/tracker"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+func PeerToPB(ih store.InfoHash, p *store.Peer) *pb.Peer {
+	return &pb.Peer{
+		InfoHash:      ih.Bytes(),
+		PeerId:        p.PeerID.Bytes(),
+		UserId:        p.UserID,
+		Ip:            p.IP.String(),
+		Port:          uint32(p.Port),
+		Ipv6:          p.IPv6,
+		Uploaded:      p.Uploaded,
+		Downloaded:    p.Downloaded,
+		Left:          p.Left,
+		SpeedUp:       p.SpeedUP,
+		SpeedDn:       p.SpeedDN,
+		SpeedUpMax:    p.SpeedUPMax,
+		SpeedDnMax:    p.SpeedDNMax,
+		Announces:     p.Announces,
+		AnnounceFirst: timestamppb.New(p.AnnounceFirst),
+		AnnounceLast:  timestamppb.New(p.AnnounceLast),
+		TotalTime:     int64(p.TotalTime),
+		Client:        p.Client,
+		ClientCode:    p.ClientCode,
+		CountryCode:   p.CountryCode,
+		Asn:           p.ASN,
+		AsName:        p.AS,
+		Latitude:      p.Location.Latitude,
+		Longitude:     p.Location.Longitude,
+		CryptoLevel:   uint32(p.CryptoLevel),
+		Paused:        p.Paused,
+		Webrtc:        p.WebRTC,
+		Connectable:   atomic.LoadUint32(&p.Connectable),
+		Spoofed:       p.Spoofed,
+	}
+}
+
+func PBToPeer(p *pb.Peer) (store.InfoHash, *store.Peer) {
+	var ih store.InfoHash
+	_ = store.InfoHashFromBytes(&ih, p.InfoHash)
+	return ih, &store.Peer{
+		PeerID:        store.PeerIDFromString(string(p.PeerId)),
+		UserID:        p.UserId,
+		IP:            net.ParseIP(p.Ip),
+		Port:          uint16(p.Port),
+		IPv6:          p.Ipv6,
+		Uploaded:      p.Uploaded,
+		Downloaded:    p.Downloaded,
+		Left:          p.Left,
+		SpeedUP:       p.SpeedUp,
+		SpeedDN:       p.SpeedDn,
+		SpeedUPMax:    p.SpeedUpMax,
+		SpeedDNMax:    p.SpeedDnMax,
+		Announces:     p.Announces,
+		AnnounceFirst: p.AnnounceFirst.AsTime(),
+		AnnounceLast:  p.AnnounceLast.AsTime(),
+		TotalTime:     time.Duration(p.TotalTime),
+		Client:        p.Client,
+		ClientCode:    p.ClientCode,
+		CountryCode:   p.CountryCode,
+		ASN:           p.Asn,
+		AS:            p.AsName,
+		Location:      geo.LatLong{Latitude: p.Latitude, Longitude: p.Longitude},
+		CryptoLevel:   consts.CryptoLevel(p.CryptoLevel),
+		Paused:        p.Paused,
+		WebRTC:        p.Webrtc,
+		Connectable:   p.Connectable,
+		Spoofed:       p.Spoofed,
+	}
+}
+
+func (s *MikaService) SwarmGet(params *pb.InfoHashParam, stream pb.Mika_SwarmGetServer) error {
+	var ih store.InfoHash
+	if err := store.InfoHashFromBytes(&ih, params.InfoHash); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid info_hash")
+	}
+	peers, err := tracker.SwarmPeers(ih)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "unknown infohash")
+	}
+	for _, peer := range peers {
+		if err := stream.Send(PeerToPB(ih, peer)); err != nil {
+			return status.Errorf(codes.Internal, "failed to send peer list")
+		}
+	}
+	return nil
+}
+
+func (s *MikaService) PeersByUser(userID *pb.UserID, stream pb.Mika_PeersByUserServer) error {
+	u, err := findUser(userID)
+	if err != nil {
+		if errors.Is(err, consts.ErrInvalidUser) {
+			return status.Errorf(codes.NotFound, "user doesnt exist")
+		}
+		return status.Errorf(codes.Internal, "failed to get user")
+	}
+	for ph, peer := range tracker.PeersByUser(u.UserID) {
+		if err := stream.Send(PeerToPB(ph.InfoHash(), peer)); err != nil {
+			return status.Errorf(codes.Internal, "failed to send peer list")
+		}
+	}
+	return nil
+}
+
+func (s *MikaService) PeerKick(ctx context.Context, params *pb.PeerKickParams) (*emptypb.Empty, error) {
+	var ih store.InfoHash
+	if err := store.InfoHashFromBytes(&ih, params.InfoHash); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid info_hash")
+	}
+	if len(params.PeerId) != 20 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid peer_id")
+	}
+	if err := tracker.PeerKick(ih, store.PeerIDFromString(string(params.PeerId))); err != nil {
+		if errors.Is(err, consts.ErrInvalidInfoHash) {
+			return nil, status.Errorf(codes.NotFound, "unknown infohash")
+		}
+		if errors.Is(err, consts.ErrInvalidPeerID) {
+			return nil, status.Errorf(codes.NotFound, "unknown peer")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to kick peer")
+	}
+	audit(ctx, "peer.kick", peerTarget(ih, params.PeerId), nil, nil)
+	return &emptypb.Empty{}, nil
+}
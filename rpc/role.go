@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"context"
+	"github.com/leighmacdonald/mika/consts"
 	pb "github.com/leighmacdonald/mika/proto"
 	"github.com/leighmacdonald/mika/store"
 	"github.com/leighmacdonald/mika/tracker"
@@ -27,6 +28,7 @@ func (s *MikaService) RoleAll(_ *emptypb.Empty, stream pb.Mika_RoleAllServer) er
 			UploadEnabled:   r.UploadEnabled,
 			MultiUp:         r.MultiUp,
 			MultiDown:       r.MultiDown,
+			MaxPeers:        r.MaxPeers,
 			Time: &pb.TimeMeta{
 				CreatedOn: timestamppb.New(r.CreatedOn),
 				UpdatedOn: timestamppb.New(r.UpdatedOn),
@@ -49,6 +51,7 @@ func RoleToPB(r *store.Role) *pb.Role {
 		UploadEnabled:   r.UploadEnabled,
 		MultiUp:         r.MultiUp,
 		MultiDown:       r.MultiDown,
+		MaxPeers:        r.MaxPeers,
 		Time: &pb.TimeMeta{
 			CreatedOn: timestamppb.New(r.CreatedOn),
 			UpdatedOn: timestamppb.New(r.UpdatedOn),
@@ -66,6 +69,7 @@ func PBToRole(r *pb.Role) *store.Role {
 		MultiDown:       r.MultiDown,
 		DownloadEnabled: r.DownloadEnabled,
 		UploadEnabled:   r.UploadEnabled,
+		MaxPeers:        r.MaxPeers,
 		CreatedOn:       r.Time.CreatedOn.AsTime(),
 		UpdatedOn:       r.Time.UpdatedOn.AsTime(),
 	}
@@ -81,32 +85,64 @@ func (s *MikaService) RoleAdd(ctx context.Context, params *pb.RoleAddParams) (*p
 		MultiDown:       params.MultiDown,
 		DownloadEnabled: params.UploadEnabled,
 		UploadEnabled:   params.UploadEnabled,
+		MaxPeers:        params.MaxPeers,
 	}
 	if err := tracker.RoleAdd(r); err != nil {
 		return nil, errors.Wrapf(err, "Failed to add role: %s", err.Error())
 	}
+	audit(ctx, "role.add", roleTarget(r.RoleID), nil, RoleToPB(r))
 	return RoleToPB(r), nil
 }
 
-func (s *MikaService) RoleDelete(ctx context.Context, roleID *pb.RoleID) (*emptypb.Empty, error) {
-	var rID uint32
+// findRoleID resolves a RoleID param to a known role_id using either the id or name
+func findRoleID(roleID *pb.RoleID) uint32 {
+	if roleID == nil {
+		return 0
+	}
 	if roleID.RoleId > 0 {
-		rID = roleID.RoleId
-	} else if roleID.RoleName != "" {
+		return roleID.RoleId
+	}
+	if roleID.RoleName != "" {
 		for _, role := range tracker.RoleAll() {
-			if strings.ToLower(role.RoleName) == roleID.RoleName {
-				rID = role.RoleID
-				break
+			if strings.ToLower(role.RoleName) == strings.ToLower(roleID.RoleName) {
+				return role.RoleID
 			}
 		}
 	}
+	return 0
+}
+
+func (s *MikaService) RoleDelete(ctx context.Context, params *pb.RoleDeleteParams) (*pb.RoleDeleteResponse, error) {
+	rID := findRoleID(params.Role)
 	if rID <= 0 {
 		return nil, status.Errorf(codes.NotFound, "role does not exist")
 	}
-	if err := tracker.RoleDelete(rID); err != nil {
-		return nil, errors.Wrapf(err, "Failed to delete role: %s", err.Error())
+	var reassignTo uint32
+	if params.ReassignTo != nil {
+		reassignTo = findRoleID(params.ReassignTo)
+		if reassignTo <= 0 {
+			return nil, status.Errorf(codes.NotFound, "reassignment role does not exist")
+		}
+	}
+	var before *pb.Role
+	for _, role := range tracker.RoleAll() {
+		if role.RoleID == rID {
+			before = RoleToPB(role)
+		}
+	}
+	moved, err := tracker.RoleDelete(rID, reassignTo)
+	if err != nil {
+		if errors.Is(err, consts.ErrRoleInUse) {
+			return nil, status.Errorf(codes.FailedPrecondition,
+				"role is still assigned to users, a reassignment role is required")
+		}
+		if errors.Is(err, consts.ErrInvalidRole) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid role")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to delete role")
 	}
-	return &emptypb.Empty{}, nil
+	audit(ctx, "role.delete", roleTarget(rID), before, nil)
+	return &pb.RoleDeleteResponse{UsersMoved: uint32(moved)}, nil
 }
 
 func (s *MikaService) RoleSave(context.Context, *pb.Role) (*emptypb.Empty, error) {
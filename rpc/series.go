@@ -0,0 +1,76 @@
+package rpc
+
+import (
+	"context"
+	"github.com/leighmacdonald/mika/consts"
+	pb "github.com/leighmacdonald/mika/proto"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/leighmacdonald/mika/tracker"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func SeriesToPB(res pb.SeriesResolution, points []store.SeriesPoint) *pb.Series {
+	s := &pb.Series{Resolution: res}
+	for _, p := range points {
+		s.Points = append(s.Points, &pb.SeriesPoint{
+			Time:       timestamppb.New(p.Time),
+			Announces:  p.Announces,
+			Snatches:   p.Snatches,
+			Uploaded:   p.Uploaded,
+			Downloaded: p.Downloaded,
+			Seeders:    p.Seeders,
+			Leechers:   p.Leechers,
+		})
+	}
+	return s
+}
+
+func resolutionFromPB(res pb.SeriesResolution) (store.Resolution, error) {
+	switch res {
+	case pb.SeriesResolution_MINUTE:
+		return store.Minute, nil
+	case pb.SeriesResolution_HOUR:
+		return store.Hour, nil
+	case pb.SeriesResolution_DAY:
+		return store.Day, nil
+	default:
+		return 0, status.Errorf(codes.InvalidArgument, "invalid resolution")
+	}
+}
+
+func (s *MikaService) TorrentSeries(_ context.Context, params *pb.TorrentSeriesParams) (*pb.Series, error) {
+	var ih store.InfoHash
+	if err := store.InfoHashFromBytes(&ih, params.InfoHash); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid info_hash")
+	}
+	res, err := resolutionFromPB(params.Resolution)
+	if err != nil {
+		return nil, err
+	}
+	points, err := tracker.TorrentSeries(ih, res, int(params.Limit))
+	if err != nil {
+		if errors.Is(err, consts.ErrInvalidInfoHash) {
+			return nil, status.Errorf(codes.NotFound, "torrent doesnt exist")
+		}
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	return SeriesToPB(params.Resolution, points), nil
+}
+
+func (s *MikaService) UserSeries(_ context.Context, params *pb.UserSeriesParams) (*pb.Series, error) {
+	res, err := resolutionFromPB(params.Resolution)
+	if err != nil {
+		return nil, err
+	}
+	points, err := tracker.UserSeries(params.UserId, res, int(params.Limit))
+	if err != nil {
+		if errors.Is(err, consts.ErrInvalidUser) {
+			return nil, status.Errorf(codes.NotFound, "user doesnt exist")
+		}
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	return SeriesToPB(params.Resolution, points), nil
+}
@@ -14,8 +14,13 @@ import (
 )
 
 func TorrentToPB(r *store.Torrent) *pb.Torrent {
+	var ihV2 []byte
+	if !r.InfoHashV2.IsZero() {
+		ihV2 = r.InfoHashV2.Bytes()
+	}
 	return &pb.Torrent{
 		InfoHash:   r.InfoHash.Bytes(),
+		InfoHashV2: ihV2,
 		Snatches:   r.Snatches,
 		Uploaded:   r.Uploaded,
 		Downloaded: r.Downloaded,
@@ -36,10 +41,15 @@ func TorrentToPB(r *store.Torrent) *pb.Torrent {
 }
 
 func PBtoTorrent(p *pb.Torrent) *store.Torrent {
-	var ih store.InfoHash
+	var (
+		ih   store.InfoHash
+		ihV2 store.InfoHashV2
+	)
 	_ = store.InfoHashFromBytes(&ih, p.InfoHash)
+	_ = store.InfoHashV2FromBytes(&ihV2, p.InfoHashV2)
 	return &store.Torrent{
 		InfoHash:   ih,
+		InfoHashV2: ihV2,
 		Snatches:   p.Snatches,
 		Uploaded:   p.Uploaded,
 		Downloaded: p.Downloaded,
@@ -71,30 +81,44 @@ func (s *MikaService) TorrentGet(_ context.Context, params *pb.InfoHashParam) (*
 	return TorrentToPB(t), nil
 }
 
-func (s *MikaService) TorrentAdd(_ context.Context, params *pb.TorrentAddParams) (*pb.Torrent, error) {
-	var ih store.InfoHash
-	err := store.InfoHashFromBytes(&ih, params.InfoHash)
-	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "invalid info_hash")
+func (s *MikaService) TorrentAdd(ctx context.Context, params *pb.TorrentAddParams) (*pb.Torrent, error) {
+	var (
+		ih   store.InfoHash
+		ihV2 store.InfoHashV2
+	)
+	if len(params.InfoHash) == 0 && len(params.InfoHashV2) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "info_hash or info_hash_v2 required")
+	}
+	if len(params.InfoHash) > 0 {
+		if len(params.InfoHash) != 20 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid info_hash")
+		}
+		_ = store.InfoHashFromBytes(&ih, params.InfoHash)
+	}
+	if len(params.InfoHashV2) > 0 {
+		if err := store.InfoHashV2FromBytes(&ihV2, params.InfoHashV2); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid info_hash_v2")
+		}
 	}
 	t := &store.Torrent{
-		InfoHash:  ih,
-		MultiUp:   params.MultiUp,
-		MultiDn:   params.MultiDn,
-		Title:     params.Title,
-		IsEnabled: true,
+		InfoHash:   ih,
+		InfoHashV2: ihV2,
+		MultiUp:    params.MultiUp,
+		MultiDn:    params.MultiDn,
+		Title:      params.Title,
+		IsEnabled:  true,
 	}
-	err = tracker.TorrentAdd(t)
-	if err != nil {
+	if err := tracker.TorrentAdd(t); err != nil {
 		if errors.Is(err, consts.ErrDuplicate) {
 			return nil, status.Errorf(codes.AlreadyExists, "info_hash already exists")
 		}
 		return nil, status.Errorf(codes.Internal, "failed to add torrent")
 	}
+	audit(ctx, "torrent.add", torrentTarget(t.InfoHash), nil, TorrentToPB(t))
 	return TorrentToPB(t), nil
 }
 
-func (s *MikaService) TorrentDelete(_ context.Context, params *pb.InfoHashParam) (*emptypb.Empty, error) {
+func (s *MikaService) TorrentDelete(ctx context.Context, params *pb.InfoHashParam) (*emptypb.Empty, error) {
 	var (
 		ih store.InfoHash
 		t  *store.Torrent
@@ -107,9 +131,11 @@ func (s *MikaService) TorrentDelete(_ context.Context, params *pb.InfoHashParam)
 	if err != nil {
 		return &emptypb.Empty{}, status.Errorf(codes.NotFound, "unknown infohash")
 	}
+	before := TorrentToPB(t)
 	if err := tracker.TorrentDelete(t); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to delete torrent")
 	}
+	audit(ctx, "torrent.delete", torrentTarget(t.InfoHash), before, nil)
 	return &emptypb.Empty{}, nil
 }
 
@@ -50,7 +50,7 @@ func (s *MikaService) UserAll(_ *emptypb.Empty, stream pb.Mika_UserAllServer) er
 	return nil
 }
 
-func (s *MikaService) UserSave(_ context.Context, params *pb.UserUpdateParams) (*pb.User, error) {
+func (s *MikaService) UserSave(ctx context.Context, params *pb.UserUpdateParams) (*pb.User, error) {
 	usr, err := tracker.UserGetByUserID(params.UserId)
 	if err != nil {
 		if errors.Is(err, consts.ErrInvalidUser) {
@@ -58,20 +58,24 @@ func (s *MikaService) UserSave(_ context.Context, params *pb.UserUpdateParams) (
 		}
 		return nil, status.Errorf(codes.Internal, "failed to get user")
 	}
-	usr.RoleID = params.RoleId
-	usr.RemoteID = params.RemoteId
-	usr.UserName = params.UserName
-	usr.DownloadEnabled = params.DownloadEnabled
-	usr.Downloaded = params.Downloaded
-	usr.Uploaded = params.Uploaded
-	usr.Passkey = params.Passkey
-	if err := tracker.UserSave(usr); err != nil {
+	before := auditUser(usr)
+	err = tracker.UserUpdate(usr, func(u *store.User) {
+		u.RoleID = params.RoleId
+		u.RemoteID = params.RemoteId
+		u.UserName = params.UserName
+		u.DownloadEnabled = params.DownloadEnabled
+		u.Downloaded = params.Downloaded
+		u.Uploaded = params.Uploaded
+		u.Passkey = params.Passkey
+	})
+	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to update user")
 	}
+	audit(ctx, "user.save", userTarget(usr.UserID), before, auditUser(usr))
 	return UserToPB(usr), nil
 }
 
-func (s *MikaService) UserDelete(_ context.Context, userID *pb.UserID) (*emptypb.Empty, error) {
+func (s *MikaService) UserDelete(ctx context.Context, userID *pb.UserID) (*emptypb.Empty, error) {
 	u, err := findUser(userID)
 	if err != nil {
 		if errors.Is(err, consts.ErrInvalidUser) {
@@ -79,10 +83,12 @@ func (s *MikaService) UserDelete(_ context.Context, userID *pb.UserID) (*emptypb
 		}
 		return nil, status.Errorf(codes.Internal, "failed to delete user")
 	}
+	before := auditUser(u)
 	if err := tracker.UserDelete(u); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to delete user")
 	}
-	return nil, status.Errorf(codes.Unimplemented, "method UserDelete not implemented")
+	audit(ctx, "user.delete", userTarget(u.UserID), before, nil)
+	return &emptypb.Empty{}, nil
 }
 
 func (s *MikaService) UserAdd(ctx context.Context, p *pb.UserAddParams) (*pb.User, error) {
@@ -99,18 +105,22 @@ func (s *MikaService) UserAdd(ctx context.Context, p *pb.UserAddParams) (*pb.Use
 	if err := tracker.UserAdd(u); err != nil {
 		return nil, err
 	}
+	audit(ctx, "user.add", userTarget(u.UserID), nil, auditUser(u))
 	return UserToPB(u), nil
 }
 
 func UserToPB(u *store.User) *pb.User {
 	return &pb.User{
-		UserId:     u.UserID,
-		RoleId:     u.RoleID,
-		RemoteId:   u.RemoteID,
-		UserName:   u.UserName,
-		Downloaded: u.Downloaded,
-		Uploaded:   u.Uploaded,
-		Passkey:    u.Passkey,
+		UserId:          u.UserID,
+		RoleId:          u.RoleID,
+		RemoteId:        u.RemoteID,
+		UserName:        u.UserName,
+		Downloaded:      u.Downloaded,
+		Uploaded:        u.Uploaded,
+		Passkey:         u.Passkey,
+		IsDeleted:       u.IsDeleted,
+		DownloadEnabled: u.DownloadEnabled,
+		Announces:       u.Announces,
 		Time: &pb.TimeMeta{
 			CreatedOn: timestamppb.New(u.CreatedOn),
 			UpdatedOn: timestamppb.New(u.UpdatedOn),
@@ -119,6 +129,14 @@ func UserToPB(u *store.User) *pb.User {
 	}
 }
 
+// auditUser returns the user as recorded in the audit log, the passkey is omitted as it grants
+// access to the tracker
+func auditUser(u *store.User) *pb.User {
+	pu := UserToPB(u)
+	pu.Passkey = ""
+	return pu
+}
+
 func PBToUser(u *pb.User) *store.User {
 	return &store.User{
 		UserID:          u.UserId,
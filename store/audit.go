@@ -0,0 +1,72 @@
+package store
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// AuditEntry records a single administrative change made through the API
+type AuditEntry struct {
+	AuditID   uint64    `db:"audit_id" json:"audit_id"`
+	CreatedOn time.Time `db:"created_on" json:"created_on"`
+	// Actor is the name of the api key used to make the change
+	Actor string `db:"actor" json:"actor"`
+	// Address is the remote address of the caller
+	Address string `db:"address" json:"address"`
+	// Action is the type of change, eg: user.add
+	Action string `db:"action" json:"action"`
+	// Target identifies the changed object, eg: user:10
+	Target string `db:"target" json:"target"`
+	// Before and After are the JSON encoded values of the object before and after the change,
+	// they are empty when the object did not exist
+	Before json.RawMessage `db:"before_value" json:"before,omitempty"`
+	After  json.RawMessage `db:"after_value" json:"after,omitempty"`
+}
+
+// AuditFilter selects the entries returned by AuditList, zero values match every entry
+type AuditFilter struct {
+	Actor string
+	// Action matches the action exactly or, when it has no ".", all actions of the type, eg: user
+	Action string
+	Target string
+	Since  time.Time
+	Until  time.Time
+	// Limit is the maximum number of entries returned, 0 returns all entries
+	Limit int
+}
+
+// Match returns true if the entry is selected by the filter
+func (f AuditFilter) Match(e *AuditEntry) bool {
+	if f.Actor != "" && e.Actor != f.Actor {
+		return false
+	}
+	if f.Action != "" && e.Action != f.Action && !strings.HasPrefix(e.Action, f.Action+".") {
+		return false
+	}
+	if f.Target != "" && e.Target != f.Target {
+		return false
+	}
+	if !f.Since.IsZero() && e.CreatedOn.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.CreatedOn.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// FilterAudit returns the entries selected by the filter, newest first. The entries must
+// be ordered oldest first.
+func FilterAudit(entries []*AuditEntry, f AuditFilter) []*AuditEntry {
+	var found []*AuditEntry
+	for i := len(entries) - 1; i >= 0; i-- {
+		if f.Limit > 0 && len(found) >= f.Limit {
+			break
+		}
+		if f.Match(entries[i]) {
+			found = append(found, entries[i])
+		}
+	}
+	return found
+}
@@ -0,0 +1,364 @@
+package store
+
+import (
+	"fmt"
+	"github.com/leighmacdonald/mika/consts"
+	"strconv"
+	"strings"
+)
+
+// BTClient is a client and its version decoded from a peer id or User-Agent header
+type BTClient struct {
+	// Code is the client code from the peer_id, eg: qB for -qB4330-. For peer ids which could not
+	// be decoded it holds the printable chars of the first 8 bytes.
+	Code     string
+	Name     string
+	Major    int
+	Minor    int
+	Patch    int
+	SubPatch int
+}
+
+func (b BTClient) String() string {
+	if b.Name == ClientUnknown {
+		return fmt.Sprintf("%s (%s)", b.Name, b.Code)
+	}
+	return fmt.Sprintf("%s %d.%d.%d.%d", b.Name, b.Major, b.Minor, b.Patch, b.SubPatch)
+}
+
+const (
+	// ClientUnknown is the name used for clients which could not be decoded
+	ClientUnknown = "Unknown"
+	shadowCharSet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz.-"
+)
+
+// otherClientNames maps the codes of clients using their own peer id encodings to the name
+// of the client
+var otherClientNames = map[string]string{
+	"M":    "Mainline",
+	"exbc": "BitComet",
+	"FUTB": "BitComet",
+	"xUTB": "BitComet",
+	"LORD": "BitLord",
+	"XBT":  "XBT Client",
+	"OP":   "Opera",
+	"ML":   "MLdonkey",
+}
+
+// clientName returns the name of the client using the code
+func clientName(code string) (string, bool) {
+	if name, found := clientNames[code]; found {
+		return name, true
+	}
+	name, found := otherClientNames[code]
+	return name, found
+}
+
+// peerIDDecoders are tried in order until one is able to decode the peer id
+var peerIDDecoders = []func(peerID PeerID) (BTClient, bool){
+	decodeAzureus,
+	decodeMainline,
+	decodeBitComet,
+	decodeXBT,
+	decodeOpera,
+	decodeMLdonkey,
+	decodeShadow,
+}
+
+// ClientString decodes the client and version from the peer id. The following encodings
+// are understood:
+//
+// Azureus style: -qB4330- The 2 char client code followed by 4 version chars. Versions use 0-9
+// then A-Z for 10-35, eg: -qB4A20- is 4.10.2. A trailing lowercase char is a release tag. BitComet
+// uses 2 digits each for the major and minor version, eg: -BC0201- is 2.01.
+//
+// Shadow style: S58B----- Each character in the version string represents a number from 0 to 63.
+// '0'=0, ..., '9'=9, 'A'=10, ..., 'Z'=35, 'a'=36, ..., 'z'=61, '.'=62, '-'=63.
+//
+// Mainline style: M7-2-0-- or M4-20-8- The client code followed by - separated decimal versions.
+//
+// BitComet & BitLord: exbc followed by the major and minor version as bytes, BitLord sends LORD
+// after the version.
+//
+// XBT: XBT054d- The version followed by d for debug builds.
+//
+// Opera: OP7685 The build number, used as the major version.
+//
+// MLdonkey: -ML2.7.2- A dotted version between dashes.
+//
+// Peer ids which cannot be decoded have the name Unknown, with the printable chars of the prefix
+// used as the code so unknown clients can be identified.
+func ClientString(peerID PeerID) BTClient {
+	for _, decode := range peerIDDecoders {
+		if cl, ok := decode(peerID); ok {
+			return cl
+		}
+	}
+	return BTClient{Code: unknownPrefix(peerID), Name: ClientUnknown}
+}
+
+// unknownPrefix returns the first 8 bytes of the peer id with non printable chars replaced
+func unknownPrefix(peerID PeerID) string {
+	prefix := make([]byte, 8)
+	for i, c := range peerID[0:8] {
+		if c < 0x20 || c > 0x7e {
+			c = '?'
+		}
+		prefix[i] = c
+	}
+	return string(prefix)
+}
+
+// azureusDigit decodes a single version char of an Azureus style peer id
+func azureusDigit(c byte) (int, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0'), true
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 10, true
+	}
+	return 0, false
+}
+
+func decodeAzureus(peerID PeerID) (BTClient, bool) {
+	if peerID[0] != '-' || peerID[7] != '-' {
+		return BTClient{}, false
+	}
+	code := string(peerID[1:3])
+	name, found := clientNames[code]
+	if !found {
+		return BTClient{}, false
+	}
+	cl := BTClient{Code: code, Name: name}
+	var v [4]int
+	for i, c := range peerID[3:7] {
+		d, ok := azureusDigit(c)
+		if !ok {
+			// Release tags such as the s in -DE203s- are not part of the version
+			if i != 3 || c < 'a' || c > 'z' {
+				return BTClient{}, false
+			}
+		}
+		v[i] = d
+	}
+	cl.Major, cl.Minor, cl.Patch, cl.SubPatch = v[0], v[1], v[2], v[3]
+	// Transmission 3.00 and older use major.minor with 2 minor digits, then a release tag
+	if code == "TR" && cl.Major <= 3 {
+		cl.Minor, cl.Patch, cl.SubPatch = v[1]*10+v[2], 0, 0
+	}
+	if code == "BC" {
+		cl.Major, cl.Minor, cl.Patch, cl.SubPatch = v[0]*10+v[1], v[2]*10+v[3], 0, 0
+	}
+	return cl, true
+}
+
+func decodeMainline(peerID PeerID) (BTClient, bool) {
+	code := string(peerID[0:1])
+	name, found := otherClientNames[code]
+	if !found || code != "M" {
+		return BTClient{}, false
+	}
+	parts := strings.SplitN(string(peerID[1:8]), "-", 4)
+	if len(parts) < 4 {
+		return BTClient{}, false
+	}
+	cl := BTClient{Code: code, Name: name}
+	for i, field := range []*int{&cl.Major, &cl.Minor, &cl.Patch} {
+		v, err := strconv.ParseUint(parts[i], 10, 8)
+		if err != nil {
+			return BTClient{}, false
+		}
+		*field = int(v)
+	}
+	// The remainder is padding
+	if strings.Trim(parts[3], "-") != "" {
+		return BTClient{}, false
+	}
+	return cl, true
+}
+
+func decodeBitComet(peerID PeerID) (BTClient, bool) {
+	code := string(peerID[0:4])
+	if code != "exbc" && code != "FUTB" && code != "xUTB" {
+		return BTClient{}, false
+	}
+	if string(peerID[6:10]) == "LORD" {
+		code = "LORD"
+	}
+	return BTClient{
+		Code:  code,
+		Name:  otherClientNames[code],
+		Major: int(peerID[4]),
+		Minor: int(peerID[5]),
+	}, true
+}
+
+func decodeXBT(peerID PeerID) (BTClient, bool) {
+	if string(peerID[0:3]) != "XBT" || (peerID[6] != 'd' && peerID[6] != '-') {
+		return BTClient{}, false
+	}
+	cl := BTClient{Code: "XBT", Name: otherClientNames["XBT"]}
+	for i, field := range []*int{&cl.Major, &cl.Minor, &cl.Patch} {
+		c := peerID[3+i]
+		if c < '0' || c > '9' {
+			return BTClient{}, false
+		}
+		*field = int(c - '0')
+	}
+	return cl, true
+}
+
+func decodeOpera(peerID PeerID) (BTClient, bool) {
+	if string(peerID[0:2]) != "OP" {
+		return BTClient{}, false
+	}
+	build, err := strconv.ParseUint(string(peerID[2:6]), 10, 16)
+	if err != nil {
+		return BTClient{}, false
+	}
+	return BTClient{Code: "OP", Name: otherClientNames["OP"], Major: int(build)}, true
+}
+
+func decodeMLdonkey(peerID PeerID) (BTClient, bool) {
+	if string(peerID[0:3]) != "-ML" {
+		return BTClient{}, false
+	}
+	end := strings.IndexByte(string(peerID[3:]), '-')
+	if end <= 0 {
+		return BTClient{}, false
+	}
+	cl, err := ParseVersion(string(peerID[3 : 3+end]))
+	if err != nil {
+		return BTClient{}, false
+	}
+	cl.Code = "ML"
+	cl.Name = otherClientNames["ML"]
+	return cl, true
+}
+
+func decodeShadow(peerID PeerID) (BTClient, bool) {
+	code := string(peerID[0:1])
+	name, found := clientNames[code]
+	if !found {
+		return BTClient{}, false
+	}
+	// Versions are 3 or 4 chars followed by - padding
+	end := strings.IndexByte(string(peerID[1:6]), '-') + 1
+	if end < 4 {
+		return BTClient{}, false
+	}
+	cl := BTClient{Code: code, Name: name}
+	for i, field := range []*int{&cl.Major, &cl.Minor, &cl.Patch, &cl.SubPatch}[:end-1] {
+		v := strings.IndexByte(shadowCharSet, peerID[1+i])
+		if v < 0 {
+			return BTClient{}, false
+		}
+		*field = v
+	}
+	return cl, true
+}
+
+// userAgentNames maps the lower case product name sent in the User-Agent header of a client to
+// the name used for the same client in clientNames. Only clients which send the same version in
+// the User-Agent as they encode into their peer_id are included.
+var userAgentNames = map[string]string{
+	"azureus":      clientNames["AZ"],
+	"bitcomet":     clientNames["BC"],
+	"deluge":       clientNames["DE"],
+	"ktorrent":     clientNames["KT"],
+	"qbittorrent":  clientNames["qB"],
+	"transmission": clientNames["TR"],
+	"utorrent":     clientNames["UT"],
+	"utorrentmac":  clientNames["UM"],
+	"µtorrent":     clientNames["UT"],
+	"µtorrentmac":  clientNames["UM"],
+	"webtorrent":   clientNames["WW"],
+}
+
+// ParseVersion parses a dotted version string such as 4.3.3 into the version fields of a BTClient.
+// Versions may have up to 4 components, missing components are 0.
+func ParseVersion(version string) (BTClient, error) {
+	var cl BTClient
+	parts := strings.Split(strings.TrimSpace(version), ".")
+	if len(parts) > 4 {
+		return cl, consts.ErrInvalidClient
+	}
+	fields := []*int{&cl.Major, &cl.Minor, &cl.Patch, &cl.SubPatch}
+	for i, p := range parts {
+		v, err := strconv.ParseUint(p, 10, 16)
+		if err != nil {
+			return cl, consts.ErrInvalidClient
+		}
+		*fields[i] = int(v)
+	}
+	return cl, nil
+}
+
+// Version returns the dotted version of the client
+func (b BTClient) Version() string {
+	return fmt.Sprintf("%d.%d.%d.%d", b.Major, b.Minor, b.Patch, b.SubPatch)
+}
+
+// Compare compares the versions of 2 clients, ignoring the name. The result is 0 if the
+// versions are equal, -1 if b is older than o and +1 if b is newer than o.
+func (b BTClient) Compare(o BTClient) int {
+	a := []int{b.Major, b.Minor, b.Patch, b.SubPatch}
+	c := []int{o.Major, o.Minor, o.Patch, o.SubPatch}
+	for i := range a {
+		if a[i] < c[i] {
+			return -1
+		}
+		if a[i] > c[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// ClientFromUserAgent parses the client name and version from a User-Agent header. Both the
+// name/version and "name version" formats are understood, eg: qBittorrent/4.3.3,
+// uTorrent/3550(45934) & Azureus 5.7.6.0;Windows 10;Java 1.8
+//
+// Versions made of only digits without any separators, as used by µTorrent, are treated as a single
+// digit per version component. False is returned for unknown clients or versions.
+func ClientFromUserAgent(userAgent string) (BTClient, bool) {
+	// Only the first product is of interest, eg: Deluge 1.3.15 libtorrent/1.1.5
+	product := strings.SplitN(userAgent, ";", 2)[0]
+	sep := strings.IndexAny(product, "/ ")
+	if sep <= 0 {
+		return BTClient{}, false
+	}
+	name, found := userAgentNames[strings.ToLower(product[:sep])]
+	if !found {
+		return BTClient{}, false
+	}
+	version := strings.TrimLeft(product[sep+1:], "v")
+	if end := strings.IndexAny(version, " (/-"); end >= 0 {
+		version = version[:end]
+	}
+	if !strings.Contains(version, ".") && len(version) > 1 {
+		version = strings.Join(strings.Split(version, ""), ".")
+	}
+	cl, err := ParseVersion(version)
+	if err != nil {
+		return cl, false
+	}
+	cl.Name = name
+	return cl, true
+}
+
+// ClientSpoofed returns true when the client decoded from the peer_id does not match the client
+// sent in the User-Agent header. Only the client family and major version are compared as clients
+// do not encode the full version consistently. If either value cannot be decoded no determination
+// can be made and false is returned.
+func ClientSpoofed(peerID PeerID, userAgent string) bool {
+	uaClient, found := ClientFromUserAgent(userAgent)
+	if !found {
+		return false
+	}
+	pidClient := ClientString(peerID)
+	if pidClient.Name == ClientUnknown {
+		return false
+	}
+	return uaClient.Name != pidClient.Name || uaClient.Major != pidClient.Major
+}
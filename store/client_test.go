@@ -0,0 +1,81 @@
+package store
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestClientFromUserAgent(t *testing.T) {
+	type ua struct {
+		userAgent string
+		client    string
+		found     bool
+	}
+	agents := []ua{
+		{userAgent: "qBittorrent/4.3.3", client: "qBittorrent 4.3.3.0", found: true},
+		{userAgent: "qBittorrent v4.1.7", client: "qBittorrent 4.1.7.0", found: true},
+		{userAgent: "uTorrent/3550(45934)", client: "µTorrent 3.5.5.0", found: true},
+		{userAgent: "Transmission/3.00", client: "Transmission 3.0.0.0", found: true},
+		{userAgent: "Deluge 1.3.15", client: "DelugeTorrent 1.3.15.0", found: true},
+		{userAgent: "Azureus 5.7.6.0;Windows 10;Java 1.8.0_202", client: "Azureus 5.7.6.0", found: true},
+		{userAgent: "rtorrent/0.9.8/0.13.8", found: false},
+		{userAgent: "Mozilla/5.0 (X11; Linux x86_64)", found: false},
+		{userAgent: "qBittorrent/beta", found: false},
+		{userAgent: "", found: false},
+	}
+	for _, a := range agents {
+		cl, found := ClientFromUserAgent(a.userAgent)
+		require.Equal(t, a.found, found, a.userAgent)
+		if a.found {
+			require.Equal(t, a.client, cl.String())
+		}
+	}
+}
+
+func TestClientSpoofed(t *testing.T) {
+	pid := PeerIDFromString("-qB4330-u-rGseINmloG")
+	require.False(t, ClientSpoofed(pid, "qBittorrent/4.3.3"))
+	// Only the major version is compared
+	require.False(t, ClientSpoofed(pid, "qBittorrent/4.2.5"))
+	require.True(t, ClientSpoofed(pid, "qBittorrent/3.3.16"))
+	require.True(t, ClientSpoofed(pid, "Transmission/4.00"))
+	// Unknown user agents or peer ids cannot be checked
+	require.False(t, ClientSpoofed(pid, "rtorrent/0.9.8/0.13.8"))
+	require.False(t, ClientSpoofed(PeerIDFromString("--------u-rGseINmloG"), "qBittorrent/4.3.3"))
+
+	// The peer_id prefix and User-Agent sent by a real client of each of the userAgentNames
+	genuine := map[string][2]string{
+		"azureus":      {"-AZ5760-", "Azureus 5.7.6.0;Windows 10;Java 1.8.0_202"},
+		"bitcomet":     {"-BC0200-", "BitComet/2.00"},
+		"deluge":       {"-DE13F0-", "Deluge 1.3.15"},
+		"ktorrent":     {"-KT4310-", "KTorrent/4.3.1"},
+		"qbittorrent":  {"-qB4330-", "qBittorrent/4.3.3"},
+		"transmission": {"-TR2940-", "Transmission/2.94"},
+		"utorrent":     {"-UT3550-", "uTorrent/3550(45934)"},
+		"utorrentmac":  {"-UM1850-", "uTorrentMac/1850(45356)"},
+		"µtorrent":     {"-UT2210-", "µTorrent/2210(25302)"},
+		"µtorrentmac":  {"-UM1630-", "µTorrentMac/1630(26429)"},
+		"webtorrent":   {"-WW0107-", "WebTorrent/0.107.17 (https://webtorrent.io)"},
+	}
+	for name := range userAgentNames {
+		pair, found := genuine[name]
+		require.True(t, found, "missing genuine client for %s", name)
+		pid := PeerIDFromString(pair[0] + "u-rGseINmloG")
+		require.False(t, ClientSpoofed(pid, pair[1]), "%s %s", pair[0], pair[1])
+	}
+	require.True(t, ClientSpoofed(PeerIDFromString("-BC0200-u-rGseINmloG"), "BitComet/1.85"))
+}
+
+func TestParseVersion(t *testing.T) {
+	v, err := ParseVersion("4.3")
+	require.NoError(t, err)
+	require.Equal(t, BTClient{Major: 4, Minor: 3}, v)
+	for _, bad := range []string{"", "a.b", "1.2.3.4.5", "-1"} {
+		_, err := ParseVersion(bad)
+		require.Error(t, err, bad)
+	}
+	older, _ := ParseVersion("4.2.9")
+	require.Equal(t, -1, older.Compare(v))
+	require.Equal(t, 1, v.Compare(older))
+	require.Equal(t, 0, v.Compare(BTClient{Name: "Other", Major: 4, Minor: 3}))
+}
@@ -0,0 +1,463 @@
+package store
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/leighmacdonald/mika/util"
+	"github.com/pkg/errors"
+	"sort"
+	"strconv"
+)
+
+// Tables copied by Copy, in the order they are copied
+const (
+	TableRoles     = "roles"
+	TableUsers     = "users"
+	TableTorrents  = "torrents"
+	TableWhiteList = "whitelist"
+	TableSeries    = "series"
+	TableAuditLog  = "audit_log"
+)
+
+// Tables are all of the tables copied by Copy, in the order they are copied
+var Tables = []string{TableRoles, TableUsers, TableTorrents, TableWhiteList, TableSeries, TableAuditLog}
+
+// copyProgressInterval is the number of rows copied between progress reports
+const copyProgressInterval = 1000
+
+// CopyProgress is reported periodically while a table is copied
+type CopyProgress struct {
+	Table string
+	Done  int
+	Total int
+}
+
+// CopyResult holds the number of rows copied for each table. As the destination store
+// assigns new role_id and user_id values the mapping from the source IDs is also returned.
+type CopyResult struct {
+	Counts map[string]int
+	// RoleIDs maps the source role_id to the destination role_id
+	RoleIDs map[uint32]uint32
+	// UserIDs maps the source user_id to the destination user_id
+	UserIDs map[uint32]uint32
+}
+
+// Copy streams all of the roles, users, torrents, whitelist entries, time series and audit log entries
+// from the src store to the dst store. The schema of dst is migrated first and it must not already contain
+// any roles, users or torrents. Peers are not copied as they are ephemeral and rebuilt by announces.
+// Audit log entries are copied unchanged, the role_id and user_id values they contain are those of the src.
+// The progress func, if not nil, is called periodically for each table.
+func Copy(src Store, dst Store, progress func(CopyProgress)) (*CopyResult, error) {
+	if progress == nil {
+		progress = func(CopyProgress) {}
+	}
+	if err := dst.Migrate(); err != nil {
+		return nil, errors.Wrap(err, "Failed to migrate destination store")
+	}
+	if err := checkEmpty(dst); err != nil {
+		return nil, err
+	}
+	res := &CopyResult{
+		Counts:  make(map[string]int),
+		RoleIDs: make(map[uint32]uint32),
+		UserIDs: make(map[uint32]uint32),
+	}
+	if err := copyRoles(src, dst, res, progress); err != nil {
+		return res, err
+	}
+	if err := copyUsers(src, dst, res, progress); err != nil {
+		return res, err
+	}
+	if err := copyTorrents(src, dst, res, progress); err != nil {
+		return res, err
+	}
+	if err := copyWhiteList(src, dst, res, progress); err != nil {
+		return res, err
+	}
+	if err := copySeries(src, dst, res, progress); err != nil {
+		return res, err
+	}
+	if err := copyAuditLog(src, dst, res, progress); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+func checkEmpty(s Store) error {
+	roles, err := s.Roles()
+	if err != nil {
+		return errors.Wrap(err, "Failed to read destination roles")
+	}
+	users, err := s.Users()
+	if err != nil {
+		return errors.Wrap(err, "Failed to read destination users")
+	}
+	torrents, err := s.Torrents()
+	if err != nil {
+		return errors.Wrap(err, "Failed to read destination torrents")
+	}
+	if len(roles) > 0 || len(users) > 0 || len(torrents) > 0 {
+		return errors.Errorf("Destination store is not empty (roles: %d users: %d torrents: %d)",
+			len(roles), len(users), len(torrents))
+	}
+	return nil
+}
+
+// report calls progress at each interval and once the table is complete
+func report(progress func(CopyProgress), table string, done int, total int) {
+	if done%copyProgressInterval == 0 || done == total {
+		progress(CopyProgress{Table: table, Done: done, Total: total})
+	}
+}
+
+func copyRoles(src Store, dst Store, res *CopyResult, progress func(CopyProgress)) error {
+	roles, err := src.Roles()
+	if err != nil {
+		return errors.Wrap(err, "Failed to read source roles")
+	}
+	sorted := make([]*Role, 0, len(roles))
+	for _, r := range roles {
+		sorted = append(sorted, r)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].RoleID < sorted[j].RoleID
+	})
+	for i, r := range sorted {
+		role := *r
+		role.RoleID = 0
+		if role.CreatedOn.IsZero() {
+			role.CreatedOn = util.Now()
+			role.UpdatedOn = role.CreatedOn
+		}
+		if err := dst.RoleAdd(&role); err != nil {
+			return errors.Wrapf(err, "Failed to copy role: %s", r.RoleName)
+		}
+		res.RoleIDs[r.RoleID] = role.RoleID
+		res.Counts[TableRoles]++
+		report(progress, TableRoles, i+1, len(sorted))
+	}
+	return nil
+}
+
+func copyUsers(src Store, dst Store, res *CopyResult, progress func(CopyProgress)) error {
+	users, err := src.Users()
+	if err != nil {
+		return errors.Wrap(err, "Failed to read source users")
+	}
+	sorted := make([]*User, 0, len(users))
+	for _, u := range users {
+		sorted = append(sorted, u)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].UserID < sorted[j].UserID
+	})
+	for i, u := range sorted {
+		roleID, found := res.RoleIDs[u.RoleID]
+		if !found {
+			return errors.Errorf("User %d references unknown role_id %d", u.UserID, u.RoleID)
+		}
+		user := *u
+		user.UserID = 0
+		user.RoleID = roleID
+		user.Role = nil
+		user.Writes = 0
+		if user.CreatedOn.IsZero() {
+			user.CreatedOn = util.Now()
+			user.UpdatedOn = user.CreatedOn
+		}
+		if err := dst.UserAdd(&user); err != nil {
+			return errors.Wrapf(err, "Failed to copy user: %d", u.UserID)
+		}
+		res.UserIDs[u.UserID] = user.UserID
+		res.Counts[TableUsers]++
+		report(progress, TableUsers, i+1, len(sorted))
+	}
+	return nil
+}
+
+func copyTorrents(src Store, dst Store, res *CopyResult, progress func(CopyProgress)) error {
+	torrents, err := src.Torrents()
+	if err != nil {
+		return errors.Wrap(err, "Failed to read source torrents")
+	}
+	sorted := make([]*Torrent, 0, len(torrents))
+	for _, t := range torrents {
+		sorted = append(sorted, t)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].InfoHash.Bytes(), sorted[j].InfoHash.Bytes()) < 0
+	})
+	for i, t := range sorted {
+		tor := *t
+		// Swarm state is rebuilt by announces
+		tor.Peers = nil
+		tor.Seeders = 0
+		tor.Leechers = 0
+		tor.Writes = 0
+		if err := dst.TorrentAdd(&tor); err != nil {
+			return errors.Wrapf(err, "Failed to copy torrent: %s", t.InfoHash)
+		}
+		// Not every driver stores the totals and flags when adding a torrent
+		if err := dst.TorrentSave(&tor); err != nil {
+			return errors.Wrapf(err, "Failed to copy torrent totals: %s", t.InfoHash)
+		}
+		res.Counts[TableTorrents]++
+		report(progress, TableTorrents, i+1, len(sorted))
+	}
+	return nil
+}
+
+func copyWhiteList(src Store, dst Store, res *CopyResult, progress func(CopyProgress)) error {
+	wl, err := src.WhiteListGetAll()
+	if err != nil {
+		return errors.Wrap(err, "Failed to read source whitelist")
+	}
+	sort.Slice(wl, func(i, j int) bool {
+		return wl[i].Key() < wl[j].Key()
+	})
+	for i, c := range wl {
+		client := *c
+		if err := dst.WhiteListAdd(&client); err != nil {
+			return errors.Wrapf(err, "Failed to copy whitelist entry: %s", c.Key())
+		}
+		res.Counts[TableWhiteList]++
+		report(progress, TableWhiteList, i+1, len(wl))
+	}
+	return nil
+}
+
+// copySeries copies the torrent and user time series, the keys of user series are changed to
+// the user_id assigned by the destination
+func copySeries(src Store, dst Store, res *CopyResult, progress func(CopyProgress)) error {
+	series, err := src.Series()
+	if err != nil {
+		return errors.Wrap(err, "Failed to read source series")
+	}
+	sort.Slice(series, func(i, j int) bool {
+		if series[i].Kind != series[j].Kind {
+			return series[i].Kind < series[j].Kind
+		}
+		return series[i].Key < series[j].Key
+	})
+	var batch []*Series
+	for i, s := range series {
+		key := s.Key
+		if s.Kind == SeriesUser {
+			userID, err := strconv.ParseUint(s.Key, 10, 32)
+			if err != nil {
+				return errors.Wrapf(err, "Invalid user series key: %s", s.Key)
+			}
+			newUserID, found := res.UserIDs[uint32(userID)]
+			if !found {
+				return errors.Errorf("Series references unknown user_id %d", userID)
+			}
+			key = UserSeriesKey(newUserID)
+		}
+		// Copy the rings so the source series is not shared with the destination
+		b, err := json.Marshal(s)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to encode series: %s %s", s.Kind, s.Key)
+		}
+		var ts Series
+		if err := json.Unmarshal(b, &ts); err != nil {
+			return errors.Wrapf(err, "Failed to decode series: %s %s", s.Kind, s.Key)
+		}
+		ts.Key = key
+		batch = append(batch, &ts)
+		if len(batch) == copyProgressInterval || i == len(series)-1 {
+			if err := dst.SeriesSync(batch); err != nil {
+				return errors.Wrap(err, "Failed to copy series")
+			}
+			batch = nil
+		}
+		res.Counts[TableSeries]++
+		report(progress, TableSeries, i+1, len(series))
+	}
+	return nil
+}
+
+// copyAuditLog copies the audit log entries oldest first, keeping the time they were created
+func copyAuditLog(src Store, dst Store, res *CopyResult, progress func(CopyProgress)) error {
+	entries, err := src.AuditList(AuditFilter{})
+	if err != nil {
+		return errors.Wrap(err, "Failed to read source audit log")
+	}
+	for i := range entries {
+		e := *entries[len(entries)-1-i]
+		e.AuditID = 0
+		if err := dst.AuditAdd(&e); err != nil {
+			return errors.Wrapf(err, "Failed to copy audit entry: %d", entries[len(entries)-1-i].AuditID)
+		}
+		res.Counts[TableAuditLog]++
+		report(progress, TableAuditLog, i+1, len(entries))
+	}
+	return nil
+}
+
+// canonicalJSON re-encodes the value so that the formatting and key order used by the store
+// does not change the checksum
+func canonicalJSON(b json.RawMessage) (string, error) {
+	if len(b) == 0 {
+		return "", nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return "", err
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// TableVerify is the result of comparing a single table between two stores
+type TableVerify struct {
+	Table    string
+	SrcCount int
+	DstCount int
+	SrcSum   string
+	DstSum   string
+}
+
+// OK returns true when both the row counts and checksums match
+func (v TableVerify) OK() bool {
+	return v.SrcCount == v.DstCount && v.SrcSum == v.DstSum
+}
+
+// Verify compares the row counts and checksums of each table copied by Copy. The checksums do
+// not include role_id and user_id values as they are reassigned by the destination, users are
+// instead compared using the name of their role and user series using the passkey of the user.
+// Only the fields stored by every driver are included.
+func Verify(src Store, dst Store) ([]TableVerify, error) {
+	var results []TableVerify
+	for _, table := range Tables {
+		srcCount, srcSum, err := tableChecksum(src, table)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to checksum source %s", table)
+		}
+		dstCount, dstSum, err := tableChecksum(dst, table)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to checksum destination %s", table)
+		}
+		results = append(results, TableVerify{
+			Table:    table,
+			SrcCount: srcCount,
+			DstCount: dstCount,
+			SrcSum:   srcSum,
+			DstSum:   dstSum,
+		})
+	}
+	return results, nil
+}
+
+// tableChecksum returns the row count and a sha256 checksum of the sorted canonical rows
+func tableChecksum(s Store, table string) (int, string, error) {
+	var rows []string
+	switch table {
+	case TableRoles, TableUsers:
+		roles, err := s.Roles()
+		if err != nil {
+			return 0, "", err
+		}
+		if table == TableRoles {
+			for _, r := range roles {
+				rows = append(rows, fmt.Sprintf("%s|%d|%.2f|%.2f|%t|%t|%d", r.RoleName, r.Priority,
+					r.MultiUp, r.MultiDown, r.DownloadEnabled, r.UploadEnabled, r.MaxPeers))
+			}
+			break
+		}
+		users, err := s.Users()
+		if err != nil {
+			return 0, "", err
+		}
+		for _, u := range users {
+			roleName := ""
+			if r, found := roles[u.RoleID]; found {
+				roleName = r.RoleName
+			}
+			rows = append(rows, fmt.Sprintf("%s|%s|%t|%t|%d|%d|%d", u.Passkey, roleName, u.IsDeleted,
+				u.DownloadEnabled, u.Downloaded, u.Uploaded, u.Announces))
+		}
+	case TableTorrents:
+		torrents, err := s.Torrents()
+		if err != nil {
+			return 0, "", err
+		}
+		for _, t := range torrents {
+			rows = append(rows, fmt.Sprintf("%s|%s|%d|%d|%d|%d|%t|%t|%s|%.2f|%.2f", t.InfoHash.String(),
+				hex.EncodeToString(t.InfoHashV2.Bytes()), t.Snatches, t.Uploaded, t.Downloaded, t.Announces,
+				t.IsDeleted, t.IsEnabled, t.Reason, t.MultiUp, t.MultiDn))
+		}
+	case TableWhiteList:
+		wl, err := s.WhiteListGetAll()
+		if err != nil {
+			return 0, "", err
+		}
+		for _, c := range wl {
+			rows = append(rows, fmt.Sprintf("%s|%s|%s|%s|%t", c.ClientCode, c.ClientName, c.MinVersion,
+				c.MaxVersion, c.Blacklist))
+		}
+	case TableSeries:
+		users, err := s.Users()
+		if err != nil {
+			return 0, "", err
+		}
+		passkeys := make(map[string]string, len(users))
+		for _, u := range users {
+			passkeys[UserSeriesKey(u.UserID)] = u.Passkey
+		}
+		series, err := s.Series()
+		if err != nil {
+			return 0, "", err
+		}
+		for _, ts := range series {
+			b, err := json.Marshal(ts)
+			if err != nil {
+				return 0, "", err
+			}
+			var sj seriesJSON
+			if err := json.Unmarshal(b, &sj); err != nil {
+				return 0, "", err
+			}
+			rings, err := json.Marshal(sj.Rings)
+			if err != nil {
+				return 0, "", err
+			}
+			key := ts.Key
+			if ts.Kind == SeriesUser {
+				key = passkeys[ts.Key]
+			}
+			rows = append(rows, fmt.Sprintf("%s|%s|%s", ts.Kind, key, rings))
+		}
+	case TableAuditLog:
+		entries, err := s.AuditList(AuditFilter{})
+		if err != nil {
+			return 0, "", err
+		}
+		for _, e := range entries {
+			before, err := canonicalJSON(e.Before)
+			if err != nil {
+				return 0, "", err
+			}
+			after, err := canonicalJSON(e.After)
+			if err != nil {
+				return 0, "", err
+			}
+			rows = append(rows, fmt.Sprintf("%d|%s|%s|%s|%s|%s|%s", e.CreatedOn.Unix(), e.Actor, e.Address,
+				e.Action, e.Target, before, after))
+		}
+	default:
+		return 0, "", errors.Errorf("Unknown table: %s", table)
+	}
+	sort.Strings(rows)
+	h := sha256.New()
+	for _, row := range rows {
+		h.Write([]byte(row))
+		h.Write([]byte{'\n'})
+	}
+	return len(rows), hex.EncodeToString(h.Sum(nil)), nil
+}
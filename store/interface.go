@@ -50,7 +50,7 @@ type Store interface {
 	UserDelete(user *User) error
 	// UserSave is used to change a known user
 	UserSave(user *User) error
-	// Close will cleanup and close the underlying storage driver if necessary
+	// UserSync batch writes the current state of the users, including their transfer totals
 	UserSync(b []*User) error
 
 	// Roles fetches all known groups
@@ -59,8 +59,11 @@ type Store interface {
 	RoleByID(roleID uint32) (*Role, error)
 	// RoleAdd adds a new role to the system
 	RoleAdd(role *Role) error
-	// RoleDelete permanently deletes a role from the system
-	RoleDelete(roleID uint32) error
+	// RoleDelete permanently deletes a role from the system. If any users still reference the role
+	// the deletion is refused with consts.ErrRoleInUse unless reassignTo is non-zero, in which case
+	// those users are moved to the reassignTo role within the same transaction.
+	// Returns the number of users that were reassigned.
+	RoleDelete(roleID uint32, reassignTo uint32) (int, error)
 	// RoleSave commits the role to persistent store
 	RoleSave(role *Role) error
 
@@ -78,6 +81,18 @@ type Store interface {
 	// TorrentSync batch updates the backing store with the new TorrentStats provided
 	TorrentSync(b []*Torrent) error
 
+	// Series returns all of the stored torrent and user time series
+	Series() ([]*Series, error)
+	// SeriesSync writes the series to the backing store, replacing any existing values
+	SeriesSync(b []*Series) error
+	// SeriesDelete removes the series of the kind and key, missing series are ignored
+	SeriesDelete(kind SeriesKind, key string) error
+
+	// AuditAdd records the entry, assigning the next AuditID
+	AuditAdd(e *AuditEntry) error
+	// AuditList returns the entries matching the filter, newest first
+	AuditList(f AuditFilter) ([]*AuditEntry, error)
+
 	// WhiteListDelete removes a client from the global whitelist
 	WhiteListDelete(client *WhiteListClient) error
 	// WhiteListAdd will insert a new client prefix into the allowed clients list
@@ -96,6 +111,12 @@ type Store interface {
 	Close() error
 }
 
+// StatsProvider is optionally implemented by drivers which can report internal statistics, such
+// as the state of their connection pool, which are included in the metrics
+type StatsProvider interface {
+	Stats() map[string]float64
+}
+
 // NewStore will attempt to initialize a StoreI using the driver name provided
 func NewStore(config config.StoreConfig) (Store, error) {
 	driverMutex.RLock()
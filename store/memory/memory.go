@@ -48,6 +48,52 @@ func (d *Driver) TorrentSync(_ []*store.Torrent) error {
 	return nil
 }
 
+// Series returns all of the stored torrent and user time series
+func (d *Driver) Series() ([]*store.Series, error) {
+	d.seriesMu.RLock()
+	defer d.seriesMu.RUnlock()
+	series := make([]*store.Series, 0, len(d.series))
+	for _, ts := range d.series {
+		series = append(series, ts)
+	}
+	return series, nil
+}
+
+// SeriesSync stores the series, replacing any existing values
+func (d *Driver) SeriesSync(b []*store.Series) error {
+	d.seriesMu.Lock()
+	for _, ts := range b {
+		d.series[string(ts.Kind)+":"+ts.Key] = ts
+	}
+	d.seriesMu.Unlock()
+	return nil
+}
+
+// SeriesDelete removes the series of the kind and key
+func (d *Driver) SeriesDelete(kind store.SeriesKind, key string) error {
+	d.seriesMu.Lock()
+	delete(d.series, string(kind)+":"+key)
+	d.seriesMu.Unlock()
+	return nil
+}
+
+// AuditAdd records the entry, assigning the next AuditID
+func (d *Driver) AuditAdd(e *store.AuditEntry) error {
+	d.auditMu.Lock()
+	e.AuditID = uint64(len(d.audit) + 1)
+	entry := *e
+	d.audit = append(d.audit, &entry)
+	d.auditMu.Unlock()
+	return nil
+}
+
+// AuditList returns the entries matching the filter, newest first
+func (d *Driver) AuditList(f store.AuditFilter) ([]*store.AuditEntry, error) {
+	d.auditMu.RLock()
+	defer d.auditMu.RUnlock()
+	return store.FilterAudit(d.audit, f), nil
+}
+
 // Conn always returns nil for in-memory store
 func (d *Driver) Conn() interface{} {
 	return nil
@@ -57,7 +103,7 @@ func (d *Driver) Conn() interface{} {
 func (d *Driver) WhiteListDelete(client *store.WhiteListClient) error {
 	d.whitelistMu.Lock()
 	defer d.whitelistMu.Unlock()
-	delete(d.whitelist, client.ClientPrefix)
+	delete(d.whitelist, client.Key())
 	return nil
 }
 
@@ -65,7 +111,7 @@ func (d *Driver) WhiteListDelete(client *store.WhiteListClient) error {
 func (d *Driver) WhiteListAdd(client *store.WhiteListClient) error {
 	d.whitelistMu.Lock()
 	defer d.whitelistMu.Unlock()
-	d.whitelist[client.ClientPrefix] = client
+	d.whitelist[client.Key()] = client
 	return nil
 }
 
@@ -105,6 +151,9 @@ func NewDriver() *Driver {
 		torrentsMu:  &sync.RWMutex{},
 		usersMu:     &sync.RWMutex{},
 		whitelistMu: &sync.RWMutex{},
+		series:      make(map[string]*store.Series),
+		seriesMu:    &sync.RWMutex{},
+		auditMu:     &sync.RWMutex{},
 	}
 }
 
@@ -118,6 +167,10 @@ type Driver struct {
 	torrentsMu  *sync.RWMutex
 	usersMu     *sync.RWMutex
 	whitelistMu *sync.RWMutex
+	series      map[string]*store.Series
+	seriesMu    *sync.RWMutex
+	audit       []*store.AuditEntry
+	auditMu     *sync.RWMutex
 	lastUserID  uint32
 	lastRoleID  uint32
 }
@@ -126,12 +179,26 @@ func (d *Driver) Migrate() error {
 	return nil
 }
 
+// Users returns a copy of the user set so the caller can modify it without holding the lock
 func (d *Driver) Users() (store.Users, error) {
-	return d.users, nil
+	d.usersMu.RLock()
+	defer d.usersMu.RUnlock()
+	users := make(store.Users, len(d.users))
+	for k, v := range d.users {
+		users[k] = v
+	}
+	return users, nil
 }
 
+// Torrents returns a copy of the torrent set so the caller can modify it without holding the lock
 func (d *Driver) Torrents() (store.Torrents, error) {
-	return d.torrents, nil
+	d.torrentsMu.RLock()
+	defer d.torrentsMu.RUnlock()
+	torrents := make(store.Torrents, len(d.torrents))
+	for k, v := range d.torrents {
+		torrents[k] = v
+	}
+	return torrents, nil
 }
 
 func (d *Driver) RoleSave(r *store.Role) error {
@@ -142,6 +209,8 @@ func (d *Driver) RoleSave(r *store.Role) error {
 }
 
 func (d *Driver) RoleByID(roleID uint32) (*store.Role, error) {
+	d.rolesMu.RLock()
+	defer d.rolesMu.RUnlock()
 	for _, r := range d.roles {
 		if r.RoleID == roleID {
 			return r, nil
@@ -166,24 +235,45 @@ func (d *Driver) RoleAdd(role *store.Role) error {
 	return nil
 }
 
-func (d *Driver) RoleDelete(roleID uint32) error {
+func (d *Driver) RoleDelete(roleID uint32, reassignTo uint32) (int, error) {
 	d.rolesMu.Lock()
 	defer d.rolesMu.Unlock()
-	conflicts := 0
+	if _, found := d.roles[roleID]; !found {
+		return 0, consts.ErrInvalidRole
+	}
+	if reassignTo > 0 {
+		if _, found := d.roles[reassignTo]; !found || reassignTo == roleID {
+			return 0, consts.ErrInvalidRole
+		}
+	}
+	d.usersMu.Lock()
+	defer d.usersMu.Unlock()
+	var assigned []*store.User
 	for _, u := range d.users {
 		if u.RoleID == roleID {
-			conflicts++
+			assigned = append(assigned, u)
 		}
 	}
-	if conflicts > 0 {
-		return errors.Errorf("Found %d users with only a single role, cannot remove only role", conflicts)
+	if len(assigned) > 0 && reassignTo == 0 {
+		return 0, errors.Wrapf(consts.ErrRoleInUse, "Found %d users with role", len(assigned))
+	}
+	for _, u := range assigned {
+		u.RoleID = reassignTo
+		u.Role = d.roles[reassignTo]
 	}
 	delete(d.roles, roleID)
-	return nil
+	return len(assigned), nil
 }
 
+// Roles returns a copy of the role set so the caller can modify it without holding the lock
 func (d *Driver) Roles() (store.Roles, error) {
-	return d.roles, nil
+	d.rolesMu.RLock()
+	defer d.rolesMu.RUnlock()
+	roles := make(store.Roles, len(d.roles))
+	for k, v := range d.roles {
+		roles[k] = v
+	}
+	return roles, nil
 }
 
 // Update is used to change a known user
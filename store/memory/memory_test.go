@@ -8,3 +8,7 @@ import (
 func TestMemoryTorrentStore(t *testing.T) {
 	store.TestStore(t, NewDriver())
 }
+
+func TestMemoryCopy(t *testing.T) {
+	store.TestCopy(t, NewDriver(), NewDriver())
+}
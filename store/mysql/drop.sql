@@ -1,3 +1,5 @@
+DROP TABLE IF EXISTS audit_log cascade;
+DROP TABLE IF EXISTS series cascade;
 DROP TABLE IF EXISTS user_multi cascade;
 DROP TABLE IF EXISTS user cascade;
 DROP TABLE IF EXISTS role cascade;
@@ -5,6 +5,7 @@ package mysql
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 	"github.com/leighmacdonald/golib"
@@ -16,6 +17,8 @@ import (
 	log "github.com/sirupsen/logrus"
 	"io/ioutil"
 	"path"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -61,7 +64,7 @@ func (s *Driver) Users() (store.Users, error) {
 
 func (s *Driver) Torrents() (store.Torrents, error) {
 	const q = `
-		SELECT info_hash, total_uploaded, total_downloaded, total_completed, 
+		SELECT info_hash, info_hash_v2, total_uploaded, total_downloaded, total_completed, 
 		       is_deleted, is_enabled, reason, multi_up, multi_dn, seeders, leechers, announces,
 		       title, created_on, updated_on
 		FROM torrent`
@@ -80,13 +83,13 @@ func (s *Driver) RoleSave(role *store.Role) error {
 	const q = `
 		INSERT INTO role (
             remote_id, role_name, priority, multi_up, multi_down, 
-		    download_enabled, upload_enabled, created_on, updated_on) 
+		    download_enabled, upload_enabled, max_peers, created_on, updated_on) 
 		VALUES 
 		    (:remote_id, :role_name, :priority, :multi_up, :multi_down, 
-		    :download_enabled, :upload_enabled, :created_on, :updated_on)
+		    :download_enabled, :upload_enabled, :max_peers, :created_on, :updated_on)
 		ON DUPLICATE KEY UPDATE 
 			remote_id = :remote_id, download_enabled = :download_enabled, upload_enabled = :upload_enabled, 
-		    multi_down = :multi_down, multi_up = :multi_up, 
+		    multi_down = :multi_down, multi_up = :multi_up, max_peers = :max_peers, 
 		    priority = :priority, role_name = :role_name
 		`
 	res, err := s.db.NamedExec(q, role)
@@ -107,7 +110,7 @@ func (s *Driver) RoleByID(roleID uint32) (*store.Role, error) {
 	const q = `
 		SELECT 
        		role_id, role_name, priority, multi_up, multi_down, 
-       		download_enabled, upload_enabled, created_on, updated_on 
+       		download_enabled, upload_enabled, max_peers, created_on, updated_on 
 		FROM role 
 		WHERE role_id = ?`
 	var role store.Role
@@ -123,10 +126,11 @@ func (s *Driver) RoleByID(roleID uint32) (*store.Role, error) {
 func (s *Driver) RoleAdd(role *store.Role) error {
 	const q = `
 		INSERT INTO role 
-		    (role_name, priority, multi_up, multi_down, download_enabled, upload_enabled, created_on, updated_on) 
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+		    (role_name, priority, multi_up, multi_down, download_enabled, upload_enabled, max_peers, 
+		     created_on, updated_on) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	res, err := s.db.Exec(q, role.RoleName, role.Priority, role.MultiUp, role.MultiDown, role.DownloadEnabled,
-		role.UploadEnabled, role.CreatedOn, role.UpdatedOn)
+		role.UploadEnabled, role.MaxPeers, role.CreatedOn, role.UpdatedOn)
 	if err != nil {
 		return errors.Wrap(err, "Failed to create role")
 	}
@@ -138,19 +142,65 @@ func (s *Driver) RoleAdd(role *store.Role) error {
 	return nil
 }
 
-func (s *Driver) RoleDelete(roleID uint32) error {
-	const q = `DELETE FROM role WHERE role_id = ?`
-	if _, err := s.db.Exec(q, roleID); err != nil {
-		return errors.Wrap(err, "Failed to delete role")
+func (s *Driver) RoleDelete(roleID uint32, reassignTo uint32) (int, error) {
+	const (
+		// The roles and the users assigned to the role are locked until the tx completes so
+		// users cannot be assigned the role, and the new role cannot be deleted, in the meantime
+		qRoles    = `SELECT role_id FROM role WHERE role_id IN (?, ?) FOR UPDATE`
+		qCount    = `SELECT COUNT(*) FROM user WHERE role_id = ? FOR UPDATE`
+		qReassign = `UPDATE user SET role_id = ? WHERE role_id = ?`
+		qDelete   = `DELETE FROM role WHERE role_id = ?`
+	)
+	if reassignTo == roleID {
+		return 0, consts.ErrInvalidRole
+	}
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to begin role delete tx")
+	}
+	var roleIDs []uint32
+	if err := tx.Select(&roleIDs, qRoles, roleID, reassignTo); err != nil {
+		_ = tx.Rollback()
+		return 0, errors.Wrap(err, "Failed to lock roles")
+	}
+	found := map[uint32]bool{}
+	for _, id := range roleIDs {
+		found[id] = true
+	}
+	if !found[roleID] || (reassignTo > 0 && !found[reassignTo]) {
+		_ = tx.Rollback()
+		return 0, consts.ErrInvalidRole
+	}
+	var assigned int
+	if err := tx.Get(&assigned, qCount, roleID); err != nil {
+		_ = tx.Rollback()
+		return 0, errors.Wrap(err, "Failed to count role users")
+	}
+	if assigned > 0 {
+		if reassignTo == 0 {
+			_ = tx.Rollback()
+			return 0, errors.Wrapf(consts.ErrRoleInUse, "Found %d users with role", assigned)
+		}
+		if _, err := tx.Exec(qReassign, reassignTo, roleID); err != nil {
+			_ = tx.Rollback()
+			return 0, errors.Wrap(err, "Failed to reassign role users")
+		}
 	}
-	return nil
+	if _, err := tx.Exec(qDelete, roleID); err != nil {
+		_ = tx.Rollback()
+		return 0, errors.Wrap(err, "Failed to delete role")
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, errors.Wrap(err, "Failed to commit role delete tx")
+	}
+	return assigned, nil
 }
 
 func (s *Driver) Roles() (store.Roles, error) {
 	const q = `
 		SELECT 
 		    role_id, role_name, priority, multi_up, multi_down, download_enabled, 
-       		upload_enabled, created_on, updated_on 
+       		upload_enabled, max_peers, created_on, updated_on 
 		FROM role`
 	var roles []*store.Role
 	if err := s.db.Select(&roles, q); err != nil {
@@ -163,13 +213,20 @@ func (s *Driver) Roles() (store.Roles, error) {
 	return result, nil
 }
 
-// Sync batch updates the backing store with the new UserStats provided
+// UserSync writes the current state of the batch of users in a single transaction
 func (s *Driver) UserSync(b []*store.User) error {
-	const q = ` UPDATE user
-    SET announces  = (announces + ?),
-        uploaded   = (uploaded + ?),
-        downloaded = (downloaded + ?)
-    WHERE passkey = ?;`
+	const q = `
+		UPDATE user
+		SET
+			role_id          = ?,
+			remote_id        = ?,
+			passkey          = ?,
+			download_enabled = ?,
+			is_deleted       = ?,
+			downloaded       = ?,
+			uploaded         = ?,
+			announces        = ?
+		WHERE user_id = ?`
 	// TODO use ctx for timeout
 	ctx := context.Background()
 	tx, err := s.db.BeginTx(ctx, nil)
@@ -180,8 +237,9 @@ func (s *Driver) UserSync(b []*store.User) error {
 	if err != nil {
 		return errors.Wrap(err, "Failed to prepare user Sync() tx")
 	}
-	for passkey, stats := range b {
-		_, err := stmt.Exec(stats.Announces, stats.Uploaded, stats.Downloaded, passkey)
+	for _, u := range b {
+		_, err := stmt.Exec(u.RoleID, u.RemoteID, u.Passkey, u.DownloadEnabled, u.IsDeleted,
+			u.Downloaded, u.Uploaded, u.Announces, u.UserID)
 		if err != nil {
 			if err := tx.Rollback(); err != nil {
 				log.Errorf("Failed to roll back user Sync() tx")
@@ -326,12 +384,26 @@ func (s *Driver) Name() string {
 	return driverName
 }
 
+// Stats returns the state of the connection pool
+func (s *Driver) Stats() map[string]float64 {
+	st := s.db.Stats()
+	return map[string]float64{
+		"max_open_connections":  float64(st.MaxOpenConnections),
+		"open_connections":      float64(st.OpenConnections),
+		"in_use":                float64(st.InUse),
+		"idle":                  float64(st.Idle),
+		"wait_count":            float64(st.WaitCount),
+		"wait_duration_seconds": st.WaitDuration.Seconds(),
+	}
+}
+
 func (s *Driver) TorrentSave(torrent *store.Torrent) error {
 	const q = `
 		UPDATE 
 		    torrent 
 		SET
 		    info_hash = ?,
+		    info_hash_v2 = ?,
 		    total_completed = ?,
 		    total_uploaded = ?,
 		    total_downloaded = ?,
@@ -346,6 +418,7 @@ func (s *Driver) TorrentSave(torrent *store.Torrent) error {
 			`
 	_, err := s.db.Exec(q,
 		torrent.InfoHash.Bytes(),
+		&torrent.InfoHashV2,
 		torrent.Snatches,
 		torrent.Uploaded,
 		torrent.Downloaded,
@@ -404,6 +477,157 @@ func (s *Driver) TorrentSync(b []*store.Torrent) error {
 	return nil
 }
 
+// Series returns all of the stored torrent and user time series
+func (s *Driver) Series() ([]*store.Series, error) {
+	const q = `SELECT data FROM series`
+	var rows [][]byte
+	if err := s.db.Select(&rows, q); err != nil {
+		return nil, errors.Wrap(err, "Failed to get all series")
+	}
+	var series []*store.Series
+	for _, data := range rows {
+		var ts store.Series
+		if err := json.Unmarshal(data, &ts); err != nil {
+			return nil, errors.Wrap(err, "Failed to decode series")
+		}
+		series = append(series, &ts)
+	}
+	return series, nil
+}
+
+// SeriesSync writes the series to the store, replacing any existing values
+func (s *Driver) SeriesSync(b []*store.Series) error {
+	const q = `
+		INSERT INTO series (kind, series_key, data, updated_on) 
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE data = VALUES(data), updated_on = VALUES(updated_on)`
+	tx, err := s.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "Failed to being series sync tx")
+	}
+	stmt, err2 := tx.Prepare(q)
+	if err2 != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err2, "Failed to prepare series sync tx")
+	}
+	now := time.Now()
+	for _, ts := range b {
+		data, errEnc := json.Marshal(ts)
+		if errEnc == nil {
+			_, errEnc = stmt.Exec(string(ts.Kind), ts.Key, data, now)
+		}
+		if errEnc != nil {
+			if err := tx.Rollback(); err != nil {
+				log.Errorf("Failed to roll back series sync tx")
+			}
+			return errors.Wrap(errEnc, "Failed to exec series sync tx")
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "Failed to commit series sync tx")
+	}
+	return nil
+}
+
+// SeriesDelete removes the series of the kind and key
+func (s *Driver) SeriesDelete(kind store.SeriesKind, key string) error {
+	const q = `DELETE FROM series WHERE kind = ? AND series_key = ?`
+	if _, err := s.db.Exec(q, string(kind), key); err != nil {
+		return errors.Wrap(err, "Failed to delete series")
+	}
+	return nil
+}
+
+// nullJSON returns the JSON value or nil when empty so it is stored as NULL
+func nullJSON(v json.RawMessage) interface{} {
+	if len(v) == 0 {
+		return nil
+	}
+	return string(v)
+}
+
+// AuditAdd records the entry, assigning the next AuditID
+func (s *Driver) AuditAdd(e *store.AuditEntry) error {
+	const q = `
+		INSERT INTO audit_log 
+		    (created_on, actor, address, action, target, before_value, after_value) 
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+	res, err := s.db.Exec(q, e.CreatedOn, e.Actor, e.Address, e.Action, e.Target, nullJSON(e.Before),
+		nullJSON(e.After))
+	if err != nil {
+		return errors.Wrap(err, "Failed to add audit entry")
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return errors.Wrap(err, "Failed to get audit_id")
+	}
+	e.AuditID = uint64(id)
+	return nil
+}
+
+// AuditList returns the entries matching the filter, newest first
+func (s *Driver) AuditList(f store.AuditFilter) ([]*store.AuditEntry, error) {
+	var (
+		where []string
+		args  []interface{}
+	)
+	if f.Actor != "" {
+		where = append(where, "actor = ?")
+		args = append(args, f.Actor)
+	}
+	if f.Action != "" {
+		where = append(where, "(action = ? OR action LIKE ?)")
+		args = append(args, f.Action, f.Action+".%")
+	}
+	if f.Target != "" {
+		where = append(where, "target = ?")
+		args = append(args, f.Target)
+	}
+	if !f.Since.IsZero() {
+		where = append(where, "created_on >= ?")
+		args = append(args, f.Since)
+	}
+	if !f.Until.IsZero() {
+		where = append(where, "created_on < ?")
+		args = append(args, f.Until)
+	}
+	q := `
+		SELECT audit_id, created_on, actor, address, action, target, 
+		       COALESCE(before_value, ''), COALESCE(after_value, '') 
+		FROM audit_log`
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+	q += " ORDER BY audit_id DESC"
+	if f.Limit > 0 {
+		q += " LIMIT " + strconv.Itoa(f.Limit)
+	}
+	rows, err := s.db.Query(q, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to select audit entries")
+	}
+	defer func() { _ = rows.Close() }()
+	var entries []*store.AuditEntry
+	for rows.Next() {
+		var (
+			e             store.AuditEntry
+			before, after string
+		)
+		if err := rows.Scan(&e.AuditID, &e.CreatedOn, &e.Actor, &e.Address, &e.Action, &e.Target,
+			&before, &after); err != nil {
+			return nil, errors.Wrap(err, "Failed to fetch audit entry")
+		}
+		if before != "" {
+			e.Before = json.RawMessage(before)
+		}
+		if after != "" {
+			e.After = json.RawMessage(after)
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
 // Conn returns the underlying database driver
 func (s *Driver) Conn() interface{} {
 	return s.db
@@ -411,17 +635,20 @@ func (s *Driver) Conn() interface{} {
 
 // WhiteListDelete removes a client from the global whitelist
 func (s *Driver) WhiteListDelete(client *store.WhiteListClient) error {
-	const q = `DELETE FROM whitelist WHERE client_prefix = ?`
-	if _, err := s.db.Exec(q, client.ClientPrefix); err != nil {
+	const q = `DELETE FROM whitelist WHERE client_code = ? AND min_version = ? AND max_version = ?`
+	if _, err := s.db.Exec(q, client.ClientCode, client.MinVersion, client.MaxVersion); err != nil {
 		return errors.Wrap(err, "Failed to delete client whitelist")
 	}
 	return nil
 }
 
-// WhiteListAdd will insert a new client prefix into the allowed clients list
+// WhiteListAdd will insert a new client into the allowed clients list
 func (s *Driver) WhiteListAdd(client *store.WhiteListClient) error {
-	const q = ` INSERT INTO whitelist (client_prefix, client_name) VALUES (?, ?);`
-	if _, err := s.db.Exec(q, client.ClientPrefix, client.ClientName); err != nil {
+	const q = `
+		INSERT INTO whitelist (client_code, client_name, min_version, max_version, blacklist) 
+		VALUES (?, ?, ?, ?, ?);`
+	if _, err := s.db.Exec(q, client.ClientCode, client.ClientName, client.MinVersion,
+		client.MaxVersion, client.Blacklist); err != nil {
 		return errors.Wrap(err, "Failed to insert new whitelist entry")
 	}
 	return nil
@@ -430,7 +657,7 @@ func (s *Driver) WhiteListAdd(client *store.WhiteListClient) error {
 // WhiteListGetAll fetches all known whitelisted clients
 func (s *Driver) WhiteListGetAll() ([]*store.WhiteListClient, error) {
 	var wl []*store.WhiteListClient
-	const q = `SELECT client_prefix, client_name FROM whitelist;`
+	const q = `SELECT client_code, client_name, min_version, max_version, blacklist FROM whitelist;`
 	if err := s.db.Select(&wl, q); err != nil {
 		return nil, errors.Wrap(err, "Failed to select client whitelists")
 	}
@@ -442,6 +669,7 @@ func (s *Driver) TorrentGet(hash store.InfoHash, deletedOk bool) (*store.Torrent
 	const q = `
 		SELECT 
 			info_hash,
+			info_hash_v2,
            	total_uploaded,
            	total_downloaded,
            	total_completed,
@@ -476,9 +704,10 @@ func (s *Driver) TorrentAdd(t *store.Torrent) error {
 	t.CreatedOn = util.Now()
 	t.UpdatedOn = t.CreatedOn
 	const q = `
-		INSERT INTO torrent (info_hash, multi_up, multi_dn, title, created_on, updated_on) 
-		VALUES (?, ?, ?, ?, ?, ?);`
-	_, err := s.db.Exec(q, t.InfoHash.Bytes(), t.MultiUp, t.MultiDn, t.Title, t.CreatedOn, t.UpdatedOn)
+		INSERT INTO torrent (info_hash, info_hash_v2, multi_up, multi_dn, title, created_on, updated_on) 
+		VALUES (?, ?, ?, ?, ?, ?, ?);`
+	_, err := s.db.Exec(q, t.InfoHash.Bytes(), &t.InfoHashV2, t.MultiUp, t.MultiDn, t.Title,
+		t.CreatedOn, t.UpdatedOn)
 	if err != nil {
 		myErr, ok := err.(*mysql.MySQLError)
 		if ok { // MySQL error
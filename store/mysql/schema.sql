@@ -25,6 +25,7 @@ CREATE TABLE IF NOT EXISTS `role` (
   `multi_down` decimal(5,2) NOT NULL DEFAULT -1.00,
   `download_enabled` tinyint(1) NOT NULL DEFAULT 1,
   `upload_enabled` tinyint(1) NOT NULL DEFAULT 1,
+  `max_peers` int(11) NOT NULL DEFAULT 0,
   `created_on` timestamp NOT NULL DEFAULT current_timestamp(),
   `updated_on` timestamp NOT NULL DEFAULT current_timestamp(),
   PRIMARY KEY (`role_id`),
@@ -41,6 +42,7 @@ CREATE TABLE IF NOT EXISTS `role` (
 /*!40101 SET character_set_client = utf8 */;
 CREATE TABLE IF NOT EXISTS `torrent` (
   `info_hash` binary(20) NOT NULL,
+  `info_hash_v2` binary(32) DEFAULT NULL,
   `total_uploaded` bigint(20) unsigned NOT NULL DEFAULT 0,
   `total_downloaded` bigint(20) unsigned NOT NULL DEFAULT 0,
   `total_completed` smallint(5) unsigned NOT NULL DEFAULT 0,
@@ -58,7 +60,9 @@ CREATE TABLE IF NOT EXISTS `torrent` (
   `total_downloaded_real` bigint(20) unsigned NOT NULL DEFAULT 0,
   `total_uploaded_real` bigint(20) unsigned NOT NULL DEFAULT 0,
   PRIMARY KEY (`info_hash`),
-  CONSTRAINT `chk_ih_len` CHECK (octet_length(`info_hash`) = 20)
+  UNIQUE KEY `torrent_info_hash_v2_uindex` (`info_hash_v2`),
+  CONSTRAINT `chk_ih_len` CHECK (octet_length(`info_hash`) = 20),
+  CONSTRAINT `chk_ih_v2_len` CHECK (`info_hash_v2` is null or octet_length(`info_hash_v2`) = 32)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
@@ -113,11 +117,63 @@ CREATE TABLE IF NOT EXISTS `user_multi` (
 /*!40101 SET @saved_cs_client     = @@character_set_client */;
 /*!40101 SET character_set_client = utf8 */;
 CREATE TABLE IF NOT EXISTS `whitelist` (
-  `client_prefix` char(8) NOT NULL,
+  `client_code` varchar(8) NOT NULL,
   `client_name` varchar(20) NOT NULL,
-  PRIMARY KEY (`client_prefix`)
+  `min_version` varchar(20) NOT NULL DEFAULT '',
+  `max_version` varchar(20) NOT NULL DEFAULT '',
+  `blacklist` tinyint(1) NOT NULL DEFAULT 0,
+  PRIMARY KEY (`client_code`, `min_version`, `max_version`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 /*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `series`
+--
+
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE IF NOT EXISTS `series` (
+  `kind` varchar(10) NOT NULL,
+  `series_key` varchar(64) NOT NULL,
+  `data` mediumblob NOT NULL,
+  `updated_on` datetime NOT NULL DEFAULT current_timestamp(),
+  PRIMARY KEY (`kind`, `series_key`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `audit_log`
+--
+
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE IF NOT EXISTS `audit_log` (
+  `audit_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `created_on` datetime(6) NOT NULL DEFAULT current_timestamp(6),
+  `actor` varchar(64) NOT NULL,
+  `address` varchar(64) NOT NULL DEFAULT '',
+  `action` varchar(32) NOT NULL,
+  `target` varchar(128) NOT NULL DEFAULT '',
+  `before_value` mediumtext DEFAULT NULL,
+  `after_value` mediumtext DEFAULT NULL,
+  PRIMARY KEY (`audit_id`),
+  KEY `audit_log_created_on` (`created_on`),
+  KEY `audit_log_target` (`target`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Migrate whitelists using exact 8 char peer_id prefixes to client codes with version ranges.
+-- The existing prefixes are converted to client codes by the tracker when loading the whitelist.
+--
+
+ALTER TABLE `whitelist`
+  CHANGE COLUMN IF EXISTS `client_prefix` `client_code` varchar(8) NOT NULL,
+  ADD COLUMN IF NOT EXISTS `min_version` varchar(20) NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS `max_version` varchar(20) NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS `blacklist` tinyint(1) NOT NULL DEFAULT 0,
+  DROP PRIMARY KEY,
+  ADD PRIMARY KEY (`client_code`, `min_version`, `max_version`);
 /*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
 
 /*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
@@ -8,8 +8,6 @@ import (
 	"github.com/leighmacdonald/mika/util"
 	"github.com/pkg/errors"
 	"net"
-	"strconv"
-	"strings"
 	"sync"
 	"time"
 )
@@ -17,6 +15,16 @@ import (
 // PeerID is the client supplied unique identifier for a peer
 type PeerID [20]byte
 
+// Results of checking if a peer accepts incoming connections
+const (
+	// ConnectableUnknown is used for peers which have not been checked
+	ConnectableUnknown uint32 = iota
+	// ConnectableYes is used for peers which completed a handshake
+	ConnectableYes
+	// ConnectableNo is used for peers which could not be connected to, likely due to NAT
+	ConnectableNo
+)
+
 // PeerIDFromString translates a string into a binary PeerID
 func PeerIDFromString(s string) PeerID {
 	var buf [20]byte
@@ -72,6 +80,10 @@ type Peer struct {
 	Uploaded uint64 `db:"total_uploaded" redis:"total_uploaded" json:"total_uploaded"`
 	// Total amount downloaded as reported by client
 	Downloaded uint64 `db:"total_downloaded" redis:"total_downloaded" json:"total_downloaded"`
+	// ReportedUploaded and ReportedDownloaded are the session totals sent by the client in its
+	// last announce, used to find the transfer since the previous announce
+	ReportedUploaded   uint64 `db:"-" json:"-"`
+	ReportedDownloaded uint64 `db:"-" json:"-"`
 	// Clients reported bytes left of the download
 	Left uint32 `db:"total_left" redis:"total_left" json:"total_left"`
 	// Total active swarm participation time
@@ -102,13 +114,26 @@ type Peer struct {
 	ASN         uint32      `db:"asn" json:"asn"`
 	AS          string      `db:"as_name" json:"as_name"`
 	UserID      uint32      `db:"user_id" redis:"user_id" json:"user_id"`
-	// Client is the user-agent header sent
+	// Client is the client name and version decoded from the peer_id
 	Client string `db:"client" json:"client"`
+	// ClientCode is the client code decoded from the peer_id, eg: qB or the raw prefix
+	// of unknown clients
+	ClientCode string `db:"client_code" json:"client_code"`
 	// TODO Do we actually care about these times? Announce times likely enough
 	//CreatedOn time.Time `db:"created_on" redis:"created_on" json:"created_on"`
 	//UpdatedOn time.Time `db:"updated_on" redis:"updated_on" json:"updated_on"`
 	CryptoLevel consts.CryptoLevel `db:"crypto_level" json:"crypto_level"`
 	Paused      bool
+	// WebRTC peers are browser based WebTorrent clients connected over a websocket
+	WebRTC bool `json:"webrtc"`
+	// Connectable is one of ConnectableUnknown, ConnectableYes or ConnectableNo. It is updated
+	// asynchronously so must be accessed atomically
+	Connectable uint32 `json:"connectable"`
+	// Key is the key sent by the client when joining the swarm. Announces for the peer must
+	// use the same key.
+	Key string `json:"-"`
+	// Spoofed is set when the client in the peer_id does not match the User-Agent header
+	Spoofed bool `json:"spoofed"`
 }
 
 // Expired checks if the peer last lost contact with us
@@ -204,12 +229,24 @@ func (s Swarm) Get(peerID PeerID) (*Peer, error) {
 	return p, nil
 }
 
-// Get will copy a peer into the peer pointer passed in if it exists.
+// GetN returns up to n peers from the swarm
 func (s Swarm) GetN(n int) ([]*Peer, error) {
+	return s.Select(n, nil)
+}
+
+// Select returns up to n peers from the swarm, skipping any peers that skip returns true for.
+// Skipped peers, such as the requesting peer itself, do not count towards n.
+func (s Swarm) Select(n int, skip func(p *Peer) bool) ([]*Peer, error) {
+	if n <= 0 {
+		return nil, nil
+	}
 	s.RLock()
 	defer s.RUnlock()
 	var peerSet []*Peer
 	for _, p := range s.Peers {
+		if skip != nil && skip(p) {
+			continue
+		}
 		peerSet = append(peerSet, p)
 		if len(peerSet) >= n {
 			break
@@ -249,112 +286,8 @@ type UpdateState struct {
 	Paused    bool
 }
 
-type BTClient struct {
-	Name     string
-	Major    int
-	Minor    int
-	Patch    int
-	SubPatch int
-}
-
-func (b BTClient) String() string {
-	return fmt.Sprintf("%s %d.%d.%d.%d", b.Name, b.Major, b.Minor, b.Patch, b.SubPatch)
-}
-
-const shadowCharSet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz.-"
-
-// ClientString transforms the peer id into a client and version description
-//
-// Shadow Style:
-// Each character in the version string represents a number from 0 to 63.
-// '0'=0, ..., '9'=9, 'A'=10, ..., 'Z'=35, 'a'=36, ..., 'z'=61, '.'=62, '-'=63.
-func ClientString(peerID PeerID) BTClient {
-	var (
-		azStyle bool
-		prefix  string
-		val     uint64
-		err     error
-		cl      BTClient
-	)
-	done := false
-	for i, v := range peerID {
-		if i == 0 {
-			azStyle = v == '-' && peerID[7] == '-'
-		}
-		if azStyle {
-			switch i {
-			case 1:
-				prefix += string(v)
-				continue
-			case 2:
-				prefix += string(v)
-				n, found := clientNames[prefix]
-				if !found {
-					err = consts.ErrInvalidPeerID
-					break
-				}
-				cl.Name = n
-			case 3:
-				val, err = strconv.ParseUint(string(v), 10, 8)
-				if err != nil {
-					break
-				}
-				cl.Major = int(val)
-			case 4:
-				val, err = strconv.ParseUint(string(v), 10, 8)
-				if err != nil {
-					break
-				}
-				cl.Minor = int(val)
-			case 5:
-				val, err = strconv.ParseUint(string(v), 10, 8)
-				if err != nil {
-					break
-				}
-				cl.Patch = int(val)
-			case 6:
-				val, err = strconv.ParseUint(string(v), 10, 8)
-				if err != nil {
-					break
-				}
-				cl.SubPatch = int(val)
-				done = true
-			}
-		} else {
-			switch i {
-			case 0:
-				n, found := clientNames[string(v)]
-				if !found {
-					err = consts.ErrInvalidPeerID
-					break
-				}
-				cl.Name = n
-			case 1:
-				cl.Major = strings.Index(shadowCharSet, string(v))
-			case 2:
-				cl.Minor = strings.Index(shadowCharSet, string(v))
-			case 3:
-				cl.Patch = strings.Index(shadowCharSet, string(v))
-			case 4:
-				done = true
-				sp := strings.Index(shadowCharSet, string(v))
-				if sp >= 63 {
-					done = true
-					break
-				}
-				cl.SubPatch = sp
-			}
-		}
-		if done || err != nil {
-			if err != nil {
-				cl.Name = "Unknown"
-			}
-			break
-		}
-	}
-	return cl
-}
-
+// clientNames maps the client codes of Azureus (-XX1234-) and Shadow (S58B-----) style peer ids
+// to the name of the client
 var clientNames = map[string]string{
 	"7T": "aTorrent", // Android
 	"AB": "AnyEvent::BitTorrent",
@@ -369,6 +302,7 @@ var clientNames = map[string]string{
 	"BC": "BitComet",
 	"BE": "Baretorrent",
 	"BF": "Bitflu",
+	"BI": "BiglyBT",
 	"BG": "BTG",            // uses Rasterbar libtorrent)
 	"BL": "BitCometLite",   // uses 6 digit version number OR BitBlinder
 	"BP": "BitTorrent Pro", // Azureus + spyware
@@ -387,6 +321,7 @@ var clientNames = map[string]string{
 	"FC": "FileCroc",
 	"FD": "Free Download Manager", // versions >= 5.1.12
 	"FT": "FoxTorrent",
+	"FW": "FrostWire",
 	"FX": "Freebox BitTorrent",
 	"GS": "GSTorrent",
 	"HK": "Hekate",
@@ -1,22 +1,88 @@
 package store
 
 import (
-	"fmt"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
 
 func TestClientString(t *testing.T) {
 	type client struct {
-		peerID PeerID
+		peerID string
+		code   string
 		client string
 	}
 	clients := []client{
-		{peerID: PeerIDFromString("S58B-----XXXXXXXXXXX"), client: fmt.Sprintf("%s 5.8.11.0", clientNames["S"])},
-		{peerID: PeerIDFromString("-qB4170-u-rGseINmloG"), client: fmt.Sprintf("%s 4.1.7.0", clientNames["qB"])},
-		{peerID: PeerIDFromString("--------u-rGseINmloG"), client: "Unknown 0.0.0.0"},
+		// Azureus style
+		{"-AZ5760-GqSyPxDgrTOG", "AZ", "Azureus 5.7.6.0"},
+		{"-BI2410-fBb3zG1vMTuU", "BI", "BiglyBT 2.4.1.0"},
+		{"-qB4170-u-rGseINmloG", "qB", "qBittorrent 4.1.7.0"},
+		{"-qB4330-L3.u~W!tVm_c", "qB", "qBittorrent 4.3.3.0"},
+		{"-qB4A20-ZIBwqpYi(TvB", "qB", "qBittorrent 4.10.2.0"},
+		{"-TR2940-k8hj0wgej6ch", "TR", "Transmission 2.94.0.0"},
+		{"-TR300Z-wz1o2qvmcs8j", "TR", "Transmission 3.0.0.0"},
+		{"-TR4050-3kzpw7xzuwd8", "TR", "Transmission 4.0.5.0"},
+		{"-DE13F0-zbKi4sOkAdwY", "DE", "DelugeTorrent 1.3.15.0"},
+		{"-DE203s-A3dd.~k9ieHL", "DE", "DelugeTorrent 2.0.3.0"},
+		{"-lt0D80-\x8b\xb0\x12\xfa\x9c\x01\xe8'\xa3\xe9\xc7", "lt", "libTorrent 0.13.8.0"},
+		{"-LT1270-gqwKv8XlDd1O", "LT", "libtorrent 1.2.7.0"},
+		{"-UT3550-\xa4b\xc4\xa3\xa5\x1b\x0c\xe9\xaa\xc2\x80\xb3", "UT", "µTorrent 3.5.5.0"},
+		{"-UT2210-b\xb8\x8d\x1d\x9a\x87\x01\x06\xd0\x0e\xba\x14", "UT", "µTorrent 2.2.1.0"},
+		{"-UM1870-\xd1\x99\x07\xc0\x90\x8d\x01\x1f\x8c\xa7\x8c\xa8", "UM", "µTorrent for Mac 1.8.7.0"},
+		{"-KT5010-Db6f8Rg0DHsn", "KT", "KTorrent 5.0.1.0"},
+		{"-BC0201-\x1b\x8a\x94\x01\x83\xd2\x0e\xe5\x93\xb6\x17\x07", "BC", "BitComet 2.1.0.0"},
+		{"-FW6000-2Uc4!qGfs2d2", "FW", "FrostWire 6.0.0.0"},
+		{"-SD0100-\x8d\xc6\x06\x18\xf3\xa0\x8f\x9a\x1d\xad\x01\x02", "SD", "Thunder 0.1.0.0"},
+		{"-XL0012-\x14\xa8\x9dFa\xbc\xc0\x8f\x1c\x89\x8a\x90", "XL", "Xunlei 0.0.1.2"},
+		// Mainline style
+		{"M7-2-0--7cf2b4c1e6a3", "M", "Mainline 7.2.0.0"},
+		{"M4-20-8-6b5e0b18f3f3", "M", "Mainline 4.20.8.0"},
+		{"M3-4-2--9a6c1f04b5a0", "M", "Mainline 3.4.2.0"},
+		// Shadow style
+		{"S58B-----XXXXXXXXXXX", "S", "Shadow's client 5.8.11.0"},
+		{"T03I--00zn0H3pVsDrGA", "T", "BitTornado 0.3.18.0"},
+		{"A310--001v5Gysr4NxNK", "A", "ABC 3.1.0.0"},
+		// BitComet & BitLord
+		{"exbc\x00\x4cAqo1n1Q0DaFc0a7z", "exbc", "BitComet 0.76.0.0"},
+		{"exbc\x00\x3fLORDCz4N8u4V8Y1N", "LORD", "BitLord 0.63.0.0"},
+		{"FUTB\x00\x50yG0Sd8UZrmKT6qWO", "FUTB", "BitComet 0.80.0.0"},
+		// XBT
+		{"XBT054d-wWoyVFpQwcQ8", "XBT", "XBT Client 0.5.4.0"},
+		{"XBT063--Yr8u9SXWBnZ4", "XBT", "XBT Client 0.6.3.0"},
+		// Opera
+		{"OP7685f2c1495b1d07d8", "OP", "Opera 7685.0.0.0"},
+		// MLdonkey
+		{"-ML2.7.2-kgjjfkd1234", "ML", "MLdonkey 2.7.2.0"},
+		// Unknown
+		{"--------u-rGseINmloG", "--------", "Unknown (--------)"},
+		{"-ZZ1234-u-rGseINmloG", "-ZZ1234-", "Unknown (-ZZ1234-)"},
+		{"-qB4.20-u-rGseINmloG", "-qB4.20-", "Unknown (-qB4.20-)"},
+		{"\x00\x01\x02\x03\x04\x05\x06\x07abcdefghijkl", "????????", "Unknown (????????)"},
+		{"Sxx-----u-rGseINmloG", "Sxx-----", "Unknown (Sxx-----)"},
 	}
 	for _, c := range clients {
-		require.Equal(t, c.client, ClientString(c.peerID).String())
+		cl := ClientString(PeerIDFromString(c.peerID))
+		require.Equal(t, c.client, cl.String(), c.peerID)
+		require.Equal(t, c.code, cl.Code, c.peerID)
 	}
 }
+
+func TestSwarmSelect(t *testing.T) {
+	s := NewSwarm()
+	var peers []*Peer
+	for i := 0; i < 5; i++ {
+		p := GenerateTestPeer()
+		s.Add(p)
+		peers = append(peers, p)
+	}
+	all, err := s.GetN(10)
+	require.NoError(t, err)
+	require.Len(t, all, 5)
+	none, err := s.GetN(0)
+	require.NoError(t, err)
+	require.Len(t, none, 0)
+	// Skipped peers do not count towards the limit
+	selected, err := s.Select(4, func(p *Peer) bool { return p.PeerID == peers[0].PeerID })
+	require.NoError(t, err)
+	require.Len(t, selected, 4)
+	require.NotContains(t, selected, peers[0])
+}
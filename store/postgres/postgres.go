@@ -4,6 +4,7 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/jackc/pgx/v4"
 	"github.com/leighmacdonald/mika/config"
@@ -12,6 +13,7 @@ import (
 	"github.com/leighmacdonald/mika/util"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"strings"
 	"time"
 )
 
@@ -25,38 +27,221 @@ type Driver struct {
 	ctx context.Context
 }
 
+// TorrentSave will update the settings and totals of the torrent
 func (d *Driver) TorrentSave(torrent *store.Torrent) error {
-	return nil
+	return d.TorrentUpdate(torrent)
 }
 
 func (d *Driver) Migrate() error { return nil }
 
+// Users returns all users in the store, including deleted users
 func (d *Driver) Users() (store.Users, error) {
-	panic("implement me")
+	const q = `
+		SELECT 
+		    user_id, role_id, passkey, download_enabled, is_deleted, downloaded, uploaded, announces 
+		FROM 
+		    users`
+	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(30*time.Second))
+	defer cancel()
+	rows, err := d.db.Query(c, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to fetch users")
+	}
+	defer rows.Close()
+	users := store.Users{}
+	for rows.Next() {
+		var u store.User
+		if err := rows.Scan(&u.UserID, &u.RoleID, &u.Passkey, &u.DownloadEnabled, &u.IsDeleted,
+			&u.Downloaded, &u.Uploaded, &u.Announces); err != nil {
+			return nil, errors.Wrap(err, "Failed to scan user")
+		}
+		users[u.Passkey] = &u
+	}
+	return users, rows.Err()
 }
 
+// Torrents returns all torrents in the store, including deleted torrents
 func (d *Driver) Torrents() (store.Torrents, error) {
-	panic("implement me")
+	const q = `
+		SELECT 
+			info_hash::bytea, info_hash_v2::bytea, total_uploaded, total_downloaded, total_completed, 
+			is_deleted, is_enabled, reason, multi_up, multi_dn, announces, seeders, leechers
+		FROM 
+		    torrent`
+	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(30*time.Second))
+	defer cancel()
+	rows, err := d.db.Query(c, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to fetch torrents")
+	}
+	defer rows.Close()
+	torrents := store.Torrents{}
+	for rows.Next() {
+		var t store.Torrent
+		var b, b2 []byte
+		if err := rows.Scan(&b, &b2, &t.Uploaded, &t.Downloaded, &t.Snatches, &t.IsDeleted, &t.IsEnabled,
+			&t.Reason, &t.MultiUp, &t.MultiDn, &t.Announces, &t.Seeders, &t.Leechers); err != nil {
+			return nil, errors.Wrap(err, "Failed to scan torrent")
+		}
+		copy(t.InfoHash[:], b)
+		copy(t.InfoHashV2[:], b2)
+		torrents[t.InfoHash] = &t
+	}
+	return torrents, rows.Err()
 }
 
+// RoleSave will update an existing role, or add it if it does not have a role_id yet
 func (d *Driver) RoleSave(role *store.Role) error {
-	panic("implement me")
+	if role.RoleID == 0 {
+		return d.RoleAdd(role)
+	}
+	const q = `
+		UPDATE 
+		    role 
+		SET 
+		    remote_id = $1, role_name = $2, priority = $3, multi_up = $4, multi_down = $5, 
+		    download_enabled = $6, upload_enabled = $7, max_peers = $8, updated_on = $9
+		WHERE 
+		    role_id = $10`
+	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(5*time.Second))
+	defer cancel()
+	role.UpdatedOn = util.Now()
+	commandTag, err := d.db.Exec(c, q, role.RemoteID, role.RoleName, role.Priority, role.MultiUp, role.MultiDown,
+		role.DownloadEnabled, role.UploadEnabled, role.MaxPeers, role.UpdatedOn, role.RoleID)
+	if err != nil {
+		return errors.Wrap(err, "Failed to save role")
+	}
+	if commandTag.RowsAffected() != 1 {
+		return consts.ErrInvalidRole
+	}
+	return nil
 }
 
+const roleColumns = `role_id, remote_id, role_name, priority, multi_up, multi_down, download_enabled, 
+		    upload_enabled, max_peers, created_on, updated_on`
+
+func scanRole(row pgx.Row, r *store.Role) error {
+	return row.Scan(&r.RoleID, &r.RemoteID, &r.RoleName, &r.Priority, &r.MultiUp, &r.MultiDown,
+		&r.DownloadEnabled, &r.UploadEnabled, &r.MaxPeers, &r.CreatedOn, &r.UpdatedOn)
+}
+
+// Roles returns all known roles
 func (d *Driver) Roles() (store.Roles, error) {
-	panic("implement me")
+	q := fmt.Sprintf(`SELECT %s FROM role`, roleColumns)
+	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(5*time.Second))
+	defer cancel()
+	rows, err := d.db.Query(c, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to fetch roles")
+	}
+	defer rows.Close()
+	roles := store.Roles{}
+	for rows.Next() {
+		var r store.Role
+		if err := scanRole(rows, &r); err != nil {
+			return nil, errors.Wrap(err, "Failed to scan role")
+		}
+		roles[r.RoleID] = &r
+	}
+	return roles, rows.Err()
 }
 
+// RoleByID returns the role matching the role_id
 func (d *Driver) RoleByID(roleID uint32) (*store.Role, error) {
-	panic("implement me")
+	q := fmt.Sprintf(`SELECT %s FROM role WHERE role_id = $1`, roleColumns)
+	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(5*time.Second))
+	defer cancel()
+	var r store.Role
+	if err := scanRole(d.db.QueryRow(c, q, roleID), &r); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, consts.ErrInvalidRole
+		}
+		return nil, errors.Wrap(err, "Failed to fetch role")
+	}
+	return &r, nil
 }
 
+// RoleAdd inserts the role, setting the new role_id on success
 func (d *Driver) RoleAdd(role *store.Role) error {
-	panic("implement me")
+	const q = `
+		INSERT INTO role 
+		    (remote_id, role_name, priority, multi_up, multi_down, download_enabled, upload_enabled, 
+		     max_peers, created_on, updated_on) 
+		VALUES 
+		    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING role_id`
+	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(5*time.Second))
+	defer cancel()
+	if role.CreatedOn.IsZero() {
+		role.CreatedOn = util.Now()
+	}
+	role.UpdatedOn = util.Now()
+	if err := d.db.QueryRow(c, q, role.RemoteID, role.RoleName, role.Priority, role.MultiUp, role.MultiDown,
+		role.DownloadEnabled, role.UploadEnabled, role.MaxPeers, role.CreatedOn, role.UpdatedOn).
+		Scan(&role.RoleID); err != nil {
+		return errors.Wrap(err, "Failed to create role")
+	}
+	return nil
 }
 
-func (d *Driver) RoleDelete(roleID uint32) error {
-	panic("implement me")
+func (d *Driver) RoleDelete(roleID uint32, reassignTo uint32) (int, error) {
+	const (
+		// The roles and the users assigned to the role are locked until the transaction completes
+		qRoles    = `SELECT role_id FROM role WHERE role_id IN ($1, $2) FOR UPDATE`
+		qCount    = `SELECT COUNT(*) FROM (SELECT 1 FROM users WHERE role_id = $1 FOR UPDATE) AS assigned`
+		qReassign = `UPDATE users SET role_id = $1 WHERE role_id = $2`
+		qDelete   = `DELETE FROM role WHERE role_id = $1`
+	)
+	if reassignTo == roleID {
+		return 0, consts.ErrInvalidRole
+	}
+	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(5*time.Second))
+	defer cancel()
+	tx, err := d.db.Begin(c)
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to begin role delete transaction")
+	}
+	defer func() { _ = tx.Rollback(c) }()
+	rows, err := tx.Query(c, qRoles, roleID, reassignTo)
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to lock roles")
+	}
+	found := map[uint32]bool{}
+	for rows.Next() {
+		var id uint32
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, errors.Wrap(err, "Failed to scan role")
+		}
+		found[id] = true
+	}
+	rows.Close()
+	if !found[roleID] || (reassignTo > 0 && !found[reassignTo]) {
+		return 0, consts.ErrInvalidRole
+	}
+	var assigned int
+	if err := tx.QueryRow(c, qCount, roleID).Scan(&assigned); err != nil {
+		return 0, errors.Wrap(err, "Failed to count role users")
+	}
+	if assigned > 0 {
+		if reassignTo == 0 {
+			return 0, errors.Wrapf(consts.ErrRoleInUse, "Found %d users with role", assigned)
+		}
+		if _, err := tx.Exec(c, qReassign, reassignTo, roleID); err != nil {
+			return 0, errors.Wrap(err, "Failed to reassign role users")
+		}
+	}
+	commandTag, err := tx.Exec(c, qDelete, roleID)
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to delete role")
+	}
+	if commandTag.RowsAffected() != 1 {
+		return 0, consts.ErrInvalidRole
+	}
+	if err := tx.Commit(c); err != nil {
+		return 0, errors.Wrap(err, "Failed to commit role delete transaction")
+	}
+	return assigned, nil
 }
 
 func (d *Driver) UserSave(user *store.User) error {
@@ -83,18 +268,22 @@ func (d *Driver) UserSave(user *store.User) error {
 	return nil
 }
 
-// Sync batch updates the backing store with the new UserStats provided
+// UserSync writes the current state of the batch of users in a single transaction
 func (d *Driver) UserSync(batch []*store.User) error {
 	const txName = "userSync"
 	const q = `
 		UPDATE 
 			users
 		SET
-			downloaded = (downloaded + $1),
-		    uploaded = (uploaded + $2),
-		    announces = (announces + $3)
+			role_id = $1,
+		    passkey = $2,
+		    is_deleted = $3,
+		    download_enabled = $4,
+			downloaded = $5,
+		    uploaded = $6,
+		    announces = $7
 		WHERE
-			passkey = $4
+			user_id = $8
 `
 	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(time.Second*10))
 	defer cancel()
@@ -108,8 +297,9 @@ func (d *Driver) UserSync(batch []*store.User) error {
 		return errors.Wrap(err, "postgres.Store.Sync Failed to being transaction")
 	}
 
-	for passkey, stats := range batch {
-		if _, err := tx.Exec(c, txName, stats.Downloaded, stats.Uploaded, stats.Announces, passkey); err != nil {
+	for _, u := range batch {
+		if _, err := tx.Exec(c, txName, u.RoleID, u.Passkey, u.IsDeleted, u.DownloadEnabled,
+			u.Downloaded, u.Uploaded, u.Announces, u.UserID); err != nil {
 			return errors.Wrapf(err, "postgres.Store.Sync failed to Exec tx")
 		}
 	}
@@ -125,11 +315,12 @@ func (d *Driver) UserAdd(user *store.User) error {
 	defer cancel()
 	const q = `
 		INSERT INTO users 
-		    (user_id, passkey, download_enabled, is_deleted, downloaded, uploaded, announces) 
+		    (role_id, passkey, download_enabled, is_deleted, downloaded, uploaded, announces) 
 		VALUES
-		    ($1, $2, $3, $4, $5, $6, $7)`
-	_, err := d.db.Exec(c, q, user.UserID, user.Passkey, user.DownloadEnabled, user.IsDeleted,
-		user.Downloaded, user.Uploaded, user.Announces)
+		    ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING user_id`
+	err := d.db.QueryRow(c, q, user.RoleID, user.Passkey, user.DownloadEnabled, user.IsDeleted,
+		user.Downloaded, user.Uploaded, user.Announces).Scan(&user.UserID)
 	if err != nil {
 		return errors.Wrap(err, "Failed to add user to store")
 	}
@@ -209,15 +400,16 @@ func (d *Driver) TorrentUpdate(torrent *store.Torrent) error {
 		    reason = $7,
 		    multi_up = $8,
 		    multi_dn = $9,
-		    announces = $10
+		    announces = $10,
+		    info_hash_v2 = $11
 		WHERE
-			info_hash = $11
+			info_hash = $1
 			`
 	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(5*time.Second))
 	defer cancel()
 	_, err := d.db.Exec(c, q, torrent.InfoHash.Bytes(), torrent.Snatches,
 		torrent.Uploaded, torrent.Downloaded, torrent.IsDeleted, torrent.IsEnabled,
-		torrent.Reason, torrent.MultiUp, torrent.MultiDn, torrent.Announces)
+		torrent.Reason, torrent.MultiUp, torrent.MultiDn, torrent.Announces, &torrent.InfoHashV2)
 	if err != nil {
 		return errors.Wrapf(err, "Failed to update torrent: %s", torrent.InfoHash.String())
 	}
@@ -265,6 +457,157 @@ func (d *Driver) TorrentSync(batch []*store.Torrent) error {
 	return nil
 }
 
+// nullJSON returns the JSON value or nil when empty so it is stored as NULL
+func nullJSON(v json.RawMessage) interface{} {
+	if len(v) == 0 {
+		return nil
+	}
+	return string(v)
+}
+
+// AuditAdd records the entry, assigning the next AuditID
+func (d *Driver) AuditAdd(e *store.AuditEntry) error {
+	const q = `
+		INSERT INTO audit_log (created_on, actor, address, action, target, before_value, after_value) 
+		VALUES ($1, $2, $3, $4, $5, $6::jsonb, $7::jsonb)
+		RETURNING audit_id`
+	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(5*time.Second))
+	defer cancel()
+	if err := d.db.QueryRow(c, q, e.CreatedOn, e.Actor, e.Address, e.Action, e.Target, nullJSON(e.Before),
+		nullJSON(e.After)).Scan(&e.AuditID); err != nil {
+		return errors.Wrap(err, "Failed to add audit entry")
+	}
+	return nil
+}
+
+// AuditList returns the entries matching the filter, newest first
+func (d *Driver) AuditList(f store.AuditFilter) ([]*store.AuditEntry, error) {
+	var (
+		where []string
+		args  []interface{}
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if f.Actor != "" {
+		where = append(where, "actor = "+arg(f.Actor))
+	}
+	if f.Action != "" {
+		where = append(where, fmt.Sprintf("(action = %s OR action LIKE %s)", arg(f.Action), arg(f.Action+".%")))
+	}
+	if f.Target != "" {
+		where = append(where, "target = "+arg(f.Target))
+	}
+	if !f.Since.IsZero() {
+		where = append(where, "created_on >= "+arg(f.Since))
+	}
+	if !f.Until.IsZero() {
+		where = append(where, "created_on < "+arg(f.Until))
+	}
+	q := `
+		SELECT audit_id, created_on, actor, address, action, target, 
+		       COALESCE(before_value::text, ''), COALESCE(after_value::text, '') 
+		FROM audit_log`
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+	q += " ORDER BY audit_id DESC"
+	if f.Limit > 0 {
+		q += " LIMIT " + arg(f.Limit)
+	}
+	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(30*time.Second))
+	defer cancel()
+	rows, err := d.db.Query(c, q, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to select audit entries")
+	}
+	defer rows.Close()
+	var entries []*store.AuditEntry
+	for rows.Next() {
+		var (
+			e             store.AuditEntry
+			before, after string
+		)
+		if err := rows.Scan(&e.AuditID, &e.CreatedOn, &e.Actor, &e.Address, &e.Action, &e.Target,
+			&before, &after); err != nil {
+			return nil, errors.Wrap(err, "Failed to fetch audit entry")
+		}
+		if before != "" {
+			e.Before = json.RawMessage(before)
+		}
+		if after != "" {
+			e.After = json.RawMessage(after)
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+// Series returns all of the stored torrent and user time series
+func (d *Driver) Series() ([]*store.Series, error) {
+	const q = `SELECT data FROM series`
+	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(30*time.Second))
+	defer cancel()
+	rows, err := d.db.Query(c, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to select series")
+	}
+	defer rows.Close()
+	var series []*store.Series
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, errors.Wrap(err, "Failed to fetch series")
+		}
+		var ts store.Series
+		if err := json.Unmarshal([]byte(data), &ts); err != nil {
+			return nil, errors.Wrap(err, "Failed to decode series")
+		}
+		series = append(series, &ts)
+	}
+	return series, rows.Err()
+}
+
+// SeriesSync writes the series to the store, replacing any existing values
+func (d *Driver) SeriesSync(b []*store.Series) error {
+	const q = `
+		INSERT INTO series (kind, series_key, data, updated_on) 
+		VALUES ($1, $2, $3::jsonb, now())
+		ON CONFLICT (kind, series_key) DO UPDATE SET data = excluded.data, updated_on = excluded.updated_on`
+	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(time.Second*10))
+	defer cancel()
+	tx, err := d.db.Begin(c)
+	if err != nil {
+		return errors.Wrap(err, "Failed to begin series sync tx")
+	}
+	defer func() { _ = tx.Rollback(c) }()
+	for _, ts := range b {
+		data, errEnc := json.Marshal(ts)
+		if errEnc != nil {
+			return errors.Wrap(errEnc, "Failed to encode series")
+		}
+		if _, err := tx.Exec(c, q, string(ts.Kind), ts.Key, string(data)); err != nil {
+			return errors.Wrap(err, "Failed to exec series sync tx")
+		}
+	}
+	if err := tx.Commit(c); err != nil {
+		return errors.Wrap(err, "Failed to commit series sync tx")
+	}
+	return nil
+}
+
+// SeriesDelete removes the series of the kind and key
+func (d *Driver) SeriesDelete(kind store.SeriesKind, key string) error {
+	const q = `DELETE FROM series WHERE kind = $1 AND series_key = $2`
+	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(5*time.Second))
+	defer cancel()
+	if _, err := d.db.Exec(c, q, string(kind), key); err != nil {
+		return errors.Wrap(err, "Failed to delete series")
+	}
+	return nil
+}
+
 // Conn returns the underlying database driverInit
 func (d *Driver) Conn() interface{} {
 	return d.db
@@ -272,11 +615,11 @@ func (d *Driver) Conn() interface{} {
 
 // Add inserts a new torrent into the backing store
 func (d *Driver) TorrentAdd(t *store.Torrent) error {
-	const q = `INSERT INTO torrent (info_hash) VALUES($1::bytea)`
+	const q = `INSERT INTO torrent (info_hash, info_hash_v2) VALUES($1::bytea, $2::bytea)`
 	//log.Println(t.InfoHash.Bytes())
 	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(5*time.Second))
 	defer cancel()
-	commandTag, err := d.db.Exec(c, q, t.InfoHash.Bytes())
+	commandTag, err := d.db.Exec(c, q, t.InfoHash.Bytes(), &t.InfoHashV2)
 	if err != nil {
 		return err
 	}
@@ -313,7 +656,7 @@ func (d *Driver) TorrentDelete(ih store.InfoHash, dropRow bool) error {
 func (d *Driver) TorrentGet(ih store.InfoHash, deletedOk bool) (*store.Torrent, error) {
 	const q = `
 		SELECT 
-			info_hash::bytea, total_uploaded, total_downloaded, total_completed, 
+			info_hash::bytea, info_hash_v2::bytea, total_uploaded, total_downloaded, total_completed, 
 			is_deleted, is_enabled, reason, multi_up, multi_dn, announces, seeders, leechers
 		FROM 
 		    torrent 
@@ -322,9 +665,10 @@ func (d *Driver) TorrentGet(ih store.InfoHash, deletedOk bool) (*store.Torrent,
 	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(5*time.Second))
 	defer cancel()
 	var t store.Torrent
-	var b []byte
+	var b, b2 []byte
 	err := d.db.QueryRow(c, q, ih.Bytes()).Scan(
 		&b, // TODO implement pgx custom types to map automatically
+		&b2,
 		&t.Uploaded,
 		&t.Downloaded,
 		&t.Snatches,
@@ -338,6 +682,7 @@ func (d *Driver) TorrentGet(ih store.InfoHash, deletedOk bool) (*store.Torrent,
 		&t.Leechers,
 	)
 	copy(t.InfoHash[:], b)
+	copy(t.InfoHashV2[:], b2)
 	if err != nil {
 		if err.Error() == "no rows in result set" {
 			return nil, consts.ErrInvalidInfoHash
@@ -359,10 +704,10 @@ func (d *Driver) Close() error {
 
 // WhiteListDelete removes a client from the global whitelist
 func (d *Driver) WhiteListDelete(client *store.WhiteListClient) error {
-	const q = `DELETE FROM whitelist WHERE client_prefix = $1`
+	const q = `DELETE FROM whitelist WHERE client_code = $1 AND min_version = $2 AND max_version = $3`
 	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(5*time.Second))
 	defer cancel()
-	commandTag, err := d.db.Exec(c, q, client.ClientPrefix)
+	commandTag, err := d.db.Exec(c, q, client.ClientCode, client.MinVersion, client.MaxVersion)
 	if err != nil {
 		return errors.Wrap(err, "Failed to delete client whitelist")
 	}
@@ -372,12 +717,15 @@ func (d *Driver) WhiteListDelete(client *store.WhiteListClient) error {
 	return nil
 }
 
-// WhiteListAdd will insert a new client prefix into the allowed clients list
+// WhiteListAdd will insert a new client into the allowed clients list
 func (d *Driver) WhiteListAdd(client *store.WhiteListClient) error {
-	const q = `INSERT INTO whitelist (client_prefix, client_name) VALUES ($1, $2)`
+	const q = `
+		INSERT INTO whitelist (client_code, client_name, min_version, max_version, blacklist) 
+		VALUES ($1, $2, $3, $4, $5)`
 	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(5*time.Second))
 	defer cancel()
-	commandTag, err := d.db.Exec(c, q, client.ClientPrefix, client.ClientName)
+	commandTag, err := d.db.Exec(c, q, client.ClientCode, client.ClientName, client.MinVersion,
+		client.MaxVersion, client.Blacklist)
 	if err != nil {
 		return errors.Wrap(err, "Failed to insert new whitelist entry")
 	}
@@ -390,7 +738,7 @@ func (d *Driver) WhiteListAdd(client *store.WhiteListClient) error {
 // WhiteListGetAll fetches all known whitelisted clients
 func (d *Driver) WhiteListGetAll() ([]*store.WhiteListClient, error) {
 	var wl []*store.WhiteListClient
-	const q = `SELECT client_prefix, client_name FROM whitelist`
+	const q = `SELECT client_code, client_name, min_version, max_version, blacklist FROM whitelist`
 	c, cancel := context.WithDeadline(d.ctx, time.Now().Add(5*time.Second))
 	defer cancel()
 	rows, err := d.db.Query(c, q)
@@ -400,7 +748,8 @@ func (d *Driver) WhiteListGetAll() ([]*store.WhiteListClient, error) {
 	defer rows.Close()
 	for rows.Next() {
 		var client store.WhiteListClient
-		err = rows.Scan(&client.ClientPrefix, &client.ClientName)
+		err = rows.Scan(&client.ClientCode, &client.ClientName, &client.MinVersion,
+			&client.MaxVersion, &client.Blacklist)
 		if err != nil {
 			return nil, errors.Wrap(err, "Failed to fetch client whitelist")
 		}
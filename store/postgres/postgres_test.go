@@ -25,7 +25,7 @@ func TestTorrentDriver(t *testing.T) {
 
 func clearDB(db *pgx.Conn) {
 	ctx := context.Background()
-	for _, table := range []string{"peers", "torrent", "users", "whitelist"} {
+	for _, table := range []string{"audit_log", "series", "peers", "torrent", "users", "role", "whitelist"} {
 		q := fmt.Sprintf(`drop table if exists %s cascade;`, table)
 		if _, err := db.Exec(ctx, q); err != nil {
 			log.Panicf("Failed to prep database: %s", err.Error())
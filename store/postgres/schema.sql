@@ -11,6 +11,7 @@ END $$;
 create table torrent
 (
     info_hash bytea check (octet_length(info_hash) = 20) not null primary key,
+    info_hash_v2 bytea check (octet_length(info_hash_v2) = 32) unique,
     total_uploaded int default 0 not null,
     total_downloaded int default 0 not null,
     total_completed smallint default 0 not null,
@@ -24,10 +25,30 @@ create table torrent
     leechers int default 0 not null
 );
 
+create table role
+(
+    role_id SERIAL
+        primary key,
+    remote_id bigint default 0 not null,
+    role_name varchar(64) not null
+        constraint role_name_uindex unique,
+    priority int not null
+        constraint role_priority_uindex unique,
+    multi_up decimal(5,2) default -1.00 not null,
+    multi_down decimal(5,2) default -1.00 not null,
+    download_enabled bool default 't' not null,
+    upload_enabled bool default 't' not null,
+    max_peers int default 0 not null,
+    created_on timestamptz default now() not null,
+    updated_on timestamptz default now() not null
+);
+
 create table users
 (
     user_id SERIAL
         primary key,
+    role_id int not null
+        references role (role_id),
     passkey varchar(20) not null,
     download_enabled bool default 't' not null,
     is_deleted bool default 'f' not null,
@@ -62,7 +83,50 @@ create table peers
 
 create table whitelist
 (
-    client_prefix varchar(10) not null
-        primary key,
-    client_name varchar(20) not null
-);
\ No newline at end of file
+    client_code varchar(8) not null,
+    client_name varchar(20) not null,
+    min_version varchar(20) default '' not null,
+    max_version varchar(20) default '' not null,
+    blacklist   boolean     default false not null,
+    constraint whitelist_pkey primary key (client_code, min_version, max_version)
+);
+
+create table if not exists series
+(
+    kind       varchar(10)               not null,
+    series_key varchar(64)               not null,
+    data       jsonb                     not null,
+    updated_on timestamptz default now() not null,
+    constraint series_pkey primary key (kind, series_key)
+);
+
+create table if not exists audit_log
+(
+    audit_id     bigserial                 not null
+        constraint audit_log_pkey primary key,
+    created_on   timestamptz default now() not null,
+    actor        varchar(64)               not null,
+    address      varchar(64) default ''    not null,
+    action       varchar(32)               not null,
+    target       varchar(128) default ''   not null,
+    before_value jsonb,
+    after_value  jsonb
+);
+
+create index if not exists audit_log_created_on_idx on audit_log (created_on);
+create index if not exists audit_log_target_idx on audit_log (target);
+
+-- Migrate whitelists using exact 8 char peer_id prefixes to client codes with version ranges.
+-- The existing prefixes are converted to client codes by the tracker when loading the whitelist.
+DO $$ BEGIN
+    alter table whitelist rename column client_prefix to client_code;
+EXCEPTION
+    WHEN undefined_column THEN null;
+END $$;
+
+alter table whitelist
+    add column if not exists min_version varchar(20) default '' not null,
+    add column if not exists max_version varchar(20) default '' not null,
+    add column if not exists blacklist   boolean     default false not null,
+    drop constraint if exists whitelist_pkey,
+    add constraint whitelist_pkey primary key (client_code, min_version, max_version);
\ No newline at end of file
@@ -1,6 +1,7 @@
 package redis
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/go-redis/redis/v7"
 	"github.com/leighmacdonald/mika/config"
@@ -10,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -26,6 +28,14 @@ const (
 	prefixRole      = "r"
 	prefixUserID    = "user_id_pk"
 	prefixRoleID    = "role_id_pk"
+	// prefixRoleUsers sets hold the passkeys of the users assigned to each role
+	prefixRoleUsers = "role_users"
+	// keySeries is a hash of the JSON encoded series by kind:key
+	keySeries = "series"
+	// keyAudit is a list of the JSON encoded audit entries, oldest first
+	keyAudit = "audit"
+	// txRetries is the number of times a watched transaction is retried when the keys change
+	txRetries = 10
 )
 
 func whiteListKey(prefix string) string {
@@ -56,6 +66,10 @@ func roleIDKey(roleID uint32) string {
 	return fmt.Sprintf("%s:%d", prefixRole, roleID)
 }
 
+func roleUsersKey(roleID uint32) string {
+	return fmt.Sprintf("%s:%d", prefixRoleUsers, roleID)
+}
+
 // Driver is the redis backed store.StoreI implementation
 type Driver struct {
 	client  *redis.Client
@@ -63,20 +77,84 @@ type Driver struct {
 	peerTTL time.Duration
 }
 
+// TorrentSave will update the settings and totals of an existing torrent
 func (d *Driver) TorrentSave(torrent *store.Torrent) error {
-	panic("implement me")
+	return d.TorrentUpdate(torrent)
 }
 
+// Migrate builds the role_users index of users written before the index was added
 func (d *Driver) Migrate() error {
+	iter := d.client.Scan(0, prefixUser+":*", 1000).Iterator()
+	for iter.Next() {
+		v, err := d.client.HMGet(iter.Val(), "passkey", "role_id").Result()
+		if err != nil {
+			return errors.Wrapf(err, "Failed to fetch user: %s", iter.Val())
+		}
+		passkey, _ := v[0].(string)
+		roleID, _ := v[1].(string)
+		if passkey == "" || roleID == "" {
+			continue
+		}
+		if err := d.client.SAdd(roleUsersKey(util.StringToUInt32(roleID, 0)), passkey).Err(); err != nil {
+			return errors.Wrap(err, "Failed to index user role")
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return errors.Wrap(err, "Failed to scan user keys")
+	}
 	return nil
 }
 
+// watch runs fn in a transaction watching the keys, retrying when the keys are changed before
+// the transaction is executed
+func (d *Driver) watch(fn func(tx *redis.Tx) error, keys ...string) error {
+	for i := 0; i < txRetries; i++ {
+		err := d.client.Watch(fn, keys...)
+		if err != redis.TxFailedErr {
+			return err
+		}
+	}
+	return redis.TxFailedErr
+}
+
+// Users returns all users in the store, including deleted users
 func (d *Driver) Users() (store.Users, error) {
-	panic("implement me")
+	keys, err := d.client.Keys(prefixUser + ":*").Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to fetch user keys")
+	}
+	users := store.Users{}
+	for _, key := range keys {
+		v, err := d.client.HGetAll(key).Result()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to fetch user: %s", key)
+		}
+		var u store.User
+		resultToUser(v, &u)
+		users[u.Passkey] = &u
+	}
+	return users, nil
 }
 
+// Torrents returns all torrents in the store, including deleted torrents
 func (d *Driver) Torrents() (store.Torrents, error) {
-	panic("implement me")
+	keys, err := d.client.Keys(prefixTorrent + ":*").Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to fetch torrent keys")
+	}
+	torrents := store.Torrents{}
+	for _, key := range keys {
+		var ih store.InfoHash
+		if err := store.InfoHashFromHex(&ih, strings.TrimPrefix(key, prefixTorrent+":")); err != nil {
+			return nil, errors.Wrapf(err, "Invalid torrent key: %s", key)
+		}
+		t, err := d.TorrentGet(ih, true)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to fetch torrent: %s", key)
+		}
+		torrents[t.InfoHash] = t
+	}
+	return torrents, nil
 }
 
 func (d *Driver) RoleSave(role *store.Role) error {
@@ -110,6 +188,7 @@ func resultToRole(r map[string]string, role *store.Role) {
 	role.MultiDown = util.StringToFloat64(r["multi_down"], 1.0)
 	role.DownloadEnabled = util.StringToBool(r["download_enabled"], true)
 	role.UploadEnabled = util.StringToBool(r["upload_enabled"], true)
+	role.MaxPeers = util.StringToInt32(r["max_peers"], 0)
 	role.CreatedOn = util.StringToTime(r["created_on"])
 	role.UpdatedOn = util.StringToTime(r["updated_on"])
 }
@@ -160,46 +239,106 @@ func (d *Driver) RoleAdd(role *store.Role) error {
 	return nil
 }
 
-func (d *Driver) RoleDelete(roleID uint32) error {
-	r, err := d.client.Del(roleIDKey(roleID)).Result()
+// RoleDelete removes the role, optionally moving any users assigned to it over to the reassignTo
+// role. The role keys and the role_users index of the role are watched so users assigned to the
+// role while it is being deleted cause the check and reassignment to be retried.
+func (d *Driver) RoleDelete(roleID uint32, reassignTo uint32) (int, error) {
+	if reassignTo == roleID {
+		return 0, consts.ErrInvalidRole
+	}
+	roleKeys := []string{roleIDKey(roleID)}
+	if reassignTo > 0 {
+		roleKeys = append(roleKeys, roleIDKey(reassignTo))
+	}
+	moved := 0
+	err := d.watch(func(tx *redis.Tx) error {
+		exists, err := tx.Exists(roleKeys...).Result()
+		if err != nil {
+			return errors.Wrap(err, "Could not lookup role")
+		}
+		if int(exists) != len(roleKeys) {
+			return consts.ErrInvalidRole
+		}
+		assigned, err := tx.SMembers(roleUsersKey(roleID)).Result()
+		if err != nil {
+			return errors.Wrap(err, "Could not fetch role users")
+		}
+		if len(assigned) > 0 && reassignTo == 0 {
+			return errors.Wrapf(consts.ErrRoleInUse, "Found %d users with role", len(assigned))
+		}
+		_, err = tx.TxPipelined(func(pipe redis.Pipeliner) error {
+			for _, passkey := range assigned {
+				pipe.HSet(userKey(passkey), "role_id", reassignTo)
+				pipe.SAdd(roleUsersKey(reassignTo), passkey)
+			}
+			pipe.Del(roleUsersKey(roleID), roleIDKey(roleID))
+			return nil
+		})
+		moved = len(assigned)
+		return err
+	}, append(roleKeys, roleUsersKey(roleID))...)
 	if err != nil {
-		return errors.Wrap(err, "Could not delete role")
+		if errors.Is(err, consts.ErrInvalidRole) || errors.Is(err, consts.ErrRoleInUse) {
+			return 0, err
+		}
+		return 0, errors.Wrap(err, "Could not delete role")
 	}
-	if r <= 0 {
-		return consts.ErrInvalidRole
+	return moved, nil
+}
+
+// usersWrite writes the users and keeps the role_users index up to date. The user and role keys
+// are watched so the role of a user cannot be changed to a role which is being deleted.
+func (d *Driver) usersWrite(b []*store.User) error {
+	var keys []string
+	roleIDs := make(map[uint32]bool)
+	for _, u := range b {
+		keys = append(keys, userKey(u.Passkey))
+		if !roleIDs[u.RoleID] {
+			roleIDs[u.RoleID] = true
+			keys = append(keys, roleIDKey(u.RoleID))
+		}
 	}
-	return nil
+	return d.watch(func(tx *redis.Tx) error {
+		for roleID := range roleIDs {
+			exists, err := tx.Exists(roleIDKey(roleID)).Result()
+			if err != nil {
+				return errors.Wrap(err, "Could not lookup role")
+			}
+			if exists == 0 {
+				return errors.Wrapf(consts.ErrInvalidRole, "Unknown role_id: %d", roleID)
+			}
+		}
+		current := make([]string, len(b))
+		for i, u := range b {
+			rid, err := tx.HGet(userKey(u.Passkey), "role_id").Result()
+			if err != nil && err != redis.Nil {
+				return errors.Wrap(err, "Could not fetch user role")
+			}
+			current[i] = rid
+		}
+		_, err := tx.TxPipelined(func(pipe redis.Pipeliner) error {
+			for i, u := range b {
+				if current[i] != "" && current[i] != strconv.FormatUint(uint64(u.RoleID), 10) {
+					pipe.SRem(roleUsersKey(util.StringToUInt32(current[i], 0)), u.Passkey)
+				}
+				pipe.HSet(userKey(u.Passkey), userMap(u))
+				pipe.SAdd(roleUsersKey(u.RoleID), u.Passkey)
+				pipe.Set(userIDKey(u.UserID), u.Passkey, 0)
+			}
+			return nil
+		})
+		return err
+	}, keys...)
 }
 
-// Sync batch updates the backing store with the new UserStats provided
-// TODO leverage cache layer so we can pipeline the updates w/o query first
+// UserSync writes the current state of the batch of users in a single transaction
 func (d *Driver) UserSync(b []*store.User) error {
-	//for passkey, stats := range b {
-	//	old, err := d.client.HGetAll(userKey(passkey)).Result()
-	//	if err != nil {
-	//		return errors.Wrap(err, "Failed to get user from redis")
-	//	}
-	//	var downloaded uint64
-	//	var uploaded uint64
-	//	var announces uint32
-	//	downloadedStr, found := old["downloaded"]
-	//	if found {
-	//		downloaded = util.StringToUInt64(downloadedStr, 0)
-	//	}
-	//	uploadedStr, found := old["uploaded"]
-	//	if found {
-	//		uploaded = util.StringToUInt64(uploadedStr, 0)
-	//	}
-	//	announcesStr, found := old["announces"]
-	//	if found {
-	//		announces = util.StringToUInt32(announcesStr, 0)
-	//	}
-	//	d.client.HSet(userKey(passkey), map[string]interface{}{
-	//		"downloaded": downloaded + stats.Downloaded,
-	//		"uploaded":   uploaded + stats.Uploaded,
-	//		"announces":  announces + stats.Announces,
-	//	})
-	//}
+	if len(b) == 0 {
+		return nil
+	}
+	if err := d.usersWrite(b); err != nil {
+		return errors.Wrap(err, "Failed to sync users")
+	}
 	return nil
 }
 
@@ -229,11 +368,26 @@ func roleMap(r *store.Role) map[string]interface{} {
 		"multi_down":       r.MultiDown,
 		"download_enabled": r.DownloadEnabled,
 		"upload_enabled":   r.UploadEnabled,
+		"max_peers":        r.MaxPeers,
 		"created_on":       r.CreatedOn.Format(time.RFC1123Z),
 		"updated_on":       r.UpdatedOn.Format(time.RFC1123Z),
 	}
 }
 
+func resultToUser(v map[string]string, user *store.User) {
+	user.Passkey = v["passkey"]
+	user.UserID = util.StringToUInt32(v["user_id"], 0)
+	user.RoleID = util.StringToUInt32(v["role_id"], 0)
+	user.RemoteID = util.StringToUInt64(v["remote_id"], 0)
+	user.Downloaded = util.StringToUInt64(v["downloaded"], 0)
+	user.Uploaded = util.StringToUInt64(v["uploaded"], 0)
+	user.Announces = util.StringToUInt32(v["announces"], 0)
+	user.DownloadEnabled = util.StringToBool(v["download_enabled"], false)
+	user.IsDeleted = util.StringToBool(v["is_deleted"], false)
+	user.CreatedOn = util.StringToTime(v["created_on"])
+	user.UpdatedOn = util.StringToTime(v["updated_on"])
+}
+
 // Add inserts a user into redis via at the string provided by the userKey function
 // This additionally sets the passkey->user_id mapping
 func (d *Driver) UserAdd(u *store.User) error {
@@ -247,11 +401,8 @@ func (d *Driver) UserAdd(u *store.User) error {
 	u.CreatedOn = util.Now()
 	u.UpdatedOn = util.Now()
 	u.UserID = id
-	if err := d.client.HSet(userKey(u.Passkey), userMap(u)).Err(); err != nil {
-		return err
-	}
-	if err2 := d.client.Set(userIDKey(u.UserID), u.Passkey, 0).Err(); err2 != nil {
-		return errors.Wrap(err2, "Failed to add user to store")
+	if err := d.usersWrite([]*store.User{u}); err != nil {
+		return errors.Wrap(err, "Failed to add user to store")
 	}
 	return nil
 }
@@ -263,17 +414,7 @@ func (d *Driver) UserGetByPasskey(passkey string) (*store.User, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to retrieve user by passkey")
 	}
-	user.Passkey = v["passkey"]
-	user.UserID = util.StringToUInt32(v["user_id"], 0)
-	user.RoleID = util.StringToUInt32(v["role_id"], 0)
-	user.RemoteID = util.StringToUInt64(v["remote_id"], 0)
-	user.Downloaded = util.StringToUInt64(v["downloaded"], 0)
-	user.Uploaded = util.StringToUInt64(v["uploaded"], 0)
-	user.Announces = util.StringToUInt32(v["announces"], 0)
-	user.DownloadEnabled = util.StringToBool(v["download_enabled"], false)
-	user.IsDeleted = util.StringToBool(v["is_deleted"], false)
-	user.CreatedOn = util.StringToTime(v["created_on"])
-	user.UpdatedOn = util.StringToTime(v["updated_on"])
+	resultToUser(v, &user)
 	if !user.Valid() {
 		return nil, consts.ErrInvalidState
 	}
@@ -295,12 +436,23 @@ func (d *Driver) UserGetByID(userID uint32) (*store.User, error) {
 
 // Delete drops a user from redis.
 func (d *Driver) UserDelete(user *store.User) error {
-	if err := d.client.Del(userKey(user.Passkey)).Err(); err != nil {
+	err := d.watch(func(tx *redis.Tx) error {
+		rid, err := tx.HGet(userKey(user.Passkey), "role_id").Result()
+		if err != nil && err != redis.Nil {
+			return errors.Wrap(err, "Could not fetch user role")
+		}
+		_, err = tx.TxPipelined(func(pipe redis.Pipeliner) error {
+			pipe.Del(userKey(user.Passkey), userIDKey(user.UserID))
+			if rid != "" {
+				pipe.SRem(roleUsersKey(util.StringToUInt32(rid, 0)), user.Passkey)
+			}
+			return nil
+		})
+		return err
+	}, userKey(user.Passkey))
+	if err != nil {
 		return errors.Wrap(err, "Could not remove user from store")
 	}
-	if err := d.client.Del(userIDKey(user.UserID)).Err(); err != nil {
-		return errors.Wrap(err, "Could not remove user pk index from store")
-	}
 	return nil
 }
 
@@ -309,11 +461,8 @@ func (d *Driver) UserSave(user *store.User) error {
 	if err != nil || exists == 0 {
 		return err
 	}
-	pipe := d.client.TxPipeline()
-	pipe.HSet(userKey(user.Passkey), userMap(user))
-	pipe.Set(userIDKey(user.UserID), user.Passkey, 0)
 	// TODO handle changing passkeys properly, this will not remove the old
-	if _, err := pipe.Exec(); err != nil {
+	if err := d.usersWrite([]*store.User{user}); err != nil {
 		return errors.Wrap(err, "Failed to add user to store")
 	}
 	return nil
@@ -356,7 +505,7 @@ func (d *Driver) Conn() interface{} {
 
 // WhiteListDelete removes a client from the global whitelist
 func (d *Driver) WhiteListDelete(client *store.WhiteListClient) error {
-	res, err := d.client.Del(whiteListKey(client.ClientPrefix)).Result()
+	res, err := d.client.Del(whiteListKey(client.Key())).Result()
 	if err != nil {
 		return errors.Wrap(err, "Failed to remove whitelisted client")
 	}
@@ -366,40 +515,56 @@ func (d *Driver) WhiteListDelete(client *store.WhiteListClient) error {
 	return nil
 }
 
-// WhiteListAdd will insert a new client prefix into the allowed clients list
+// WhiteListAdd will insert a new client into the allowed clients list
 func (d *Driver) WhiteListAdd(client *store.WhiteListClient) error {
 	valueMap := map[string]interface{}{
-		"client_prefix": client.ClientPrefix,
-		"client_name":   client.ClientName,
+		"client_code": client.ClientCode,
+		"client_name": client.ClientName,
+		"min_version": client.MinVersion,
+		"max_version": client.MaxVersion,
+		"blacklist":   client.Blacklist,
 	}
-	err := d.client.HSet(whiteListKey(client.ClientPrefix), valueMap).Err()
+	err := d.client.HSet(whiteListKey(client.Key()), valueMap).Err()
 	if err != nil {
-		return errors.Wrapf(err, "failed to add new whitelisted client prefix: %s", client.ClientPrefix)
+		return errors.Wrapf(err, "failed to add new whitelisted client: %s", client.Key())
 	}
 	return nil
 }
 
 // WhiteListGetAll fetches all known whitelisted clients
 func (d *Driver) WhiteListGetAll() ([]*store.WhiteListClient, error) {
-	prefixes, err := d.client.Keys(fmt.Sprintf("%s*", prefixWhitelist)).Result()
+	keys, err := d.client.Keys(fmt.Sprintf("%s*", prefixWhitelist)).Result()
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to fetch whitelist keys")
 	}
 	var wl []*store.WhiteListClient
-	for _, prefix := range prefixes {
-		valueMap, err := d.client.HGetAll(whiteListKey(prefix)).Result()
+	for _, key := range keys {
+		valueMap, err := d.client.HGetAll(key).Result()
 		if err != nil {
-			return nil, errors.Wrapf(err, "Failed to fetch whitelist value for: %s", whiteListKey(prefix))
+			return nil, errors.Wrapf(err, "Failed to fetch whitelist value for: %s", key)
+		}
+		code, found := valueMap["client_code"]
+		if !found {
+			// Entries created before client codes were used store the 8 char peer_id prefix,
+			// these are converted when the whitelist is loaded
+			code = valueMap["client_prefix"]
 		}
 		wl = append(wl, &store.WhiteListClient{
-			ClientPrefix: valueMap["client_prefix"],
-			ClientName:   valueMap["client_name"],
+			ClientCode: code,
+			ClientName: valueMap["client_name"],
+			MinVersion: valueMap["min_version"],
+			MaxVersion: valueMap["max_version"],
+			Blacklist:  util.StringToBool(valueMap["blacklist"], false),
 		})
 	}
 	return wl, nil
 }
 
 func torrentMap(t *store.Torrent) map[string]interface{} {
+	ihV2 := ""
+	if !t.InfoHashV2.IsZero() {
+		ihV2 = t.InfoHashV2.String()
+	}
 	return map[string]interface{}{
 		"total_completed":  t.Snatches,
 		"total_downloaded": t.Downloaded,
@@ -408,6 +573,7 @@ func torrentMap(t *store.Torrent) map[string]interface{} {
 		"multi_up":         t.MultiUp,
 		"multi_dn":         t.MultiDn,
 		"info_hash":        t.InfoHash.String(),
+		"info_hash_v2":     ihV2,
 		"is_deleted":       t.IsDeleted,
 		"is_enabled":       t.IsEnabled,
 		"announces":        t.Announces,
@@ -460,6 +626,11 @@ func (d *Driver) TorrentGet(hash store.InfoHash, deletedOk bool) (*store.Torrent
 		return nil, consts.ErrInvalidInfoHash
 	}
 	t.InfoHash = infoHash
+	if ihV2Str := v["info_hash_v2"]; ihV2Str != "" {
+		if err := store.InfoHashV2FromHex(&t.InfoHashV2, ihV2Str); err != nil {
+			return nil, errors.Wrap(err, "Failed to decode info_hash_v2")
+		}
+	}
 	t.Snatches = util.StringToUInt32(v["total_completed"], 0)
 	t.Uploaded = util.StringToUInt64(v["total_uploaded"], 0)
 	t.Downloaded = util.StringToUInt64(v["total_downloaded"], 0)
@@ -478,11 +649,102 @@ func (d *Driver) Name() string {
 	return driverName
 }
 
+// Stats returns the state of the connection pool
+func (d *Driver) Stats() map[string]float64 {
+	st := d.client.PoolStats()
+	return map[string]float64{
+		"hits":        float64(st.Hits),
+		"misses":      float64(st.Misses),
+		"timeouts":    float64(st.Timeouts),
+		"total_conns": float64(st.TotalConns),
+		"idle_conns":  float64(st.IdleConns),
+		"stale_conns": float64(st.StaleConns),
+	}
+}
+
 // Reap will loop through the peers removing any stale entries from active swarms
 func (d *Driver) Reap() []store.PeerHash {
 	return nil
 }
 
+// Series returns all of the stored torrent and user time series
+func (d *Driver) Series() ([]*store.Series, error) {
+	values, err := d.client.HGetAll(keySeries).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to fetch series")
+	}
+	var series []*store.Series
+	for field, data := range values {
+		var ts store.Series
+		if err := json.Unmarshal([]byte(data), &ts); err != nil {
+			return nil, errors.Wrapf(err, "Failed to decode series: %s", field)
+		}
+		series = append(series, &ts)
+	}
+	return series, nil
+}
+
+// SeriesSync writes the series to the store, replacing any existing values
+func (d *Driver) SeriesSync(b []*store.Series) error {
+	if len(b) == 0 {
+		return nil
+	}
+	fields := make(map[string]interface{}, len(b))
+	for _, ts := range b {
+		data, err := json.Marshal(ts)
+		if err != nil {
+			return errors.Wrap(err, "Failed to encode series")
+		}
+		fields[fmt.Sprintf("%s:%s", ts.Kind, ts.Key)] = data
+	}
+	if err := d.client.HSet(keySeries, fields).Err(); err != nil {
+		return errors.Wrap(err, "Failed to sync series")
+	}
+	return nil
+}
+
+// SeriesDelete removes the series of the kind and key
+func (d *Driver) SeriesDelete(kind store.SeriesKind, key string) error {
+	if err := d.client.HDel(keySeries, fmt.Sprintf("%s:%s", kind, key)).Err(); err != nil {
+		return errors.Wrap(err, "Failed to delete series")
+	}
+	return nil
+}
+
+// AuditAdd records the entry, assigning the next AuditID
+func (d *Driver) AuditAdd(e *store.AuditEntry) error {
+	newID, err := d.client.Incr(keyAudit + "_id_seq").Result()
+	if err != nil {
+		return errors.Wrap(err, "Failed to get next audit_id")
+	}
+	e.AuditID = uint64(newID)
+	data, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "Failed to encode audit entry")
+	}
+	if err := d.client.RPush(keyAudit, data).Err(); err != nil {
+		return errors.Wrap(err, "Failed to add audit entry")
+	}
+	return nil
+}
+
+// AuditList returns the entries matching the filter, newest first
+func (d *Driver) AuditList(f store.AuditFilter) ([]*store.AuditEntry, error) {
+	values, err := d.client.LRange(keyAudit, 0, -1).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to fetch audit entries")
+	}
+	entries := make([]*store.AuditEntry, len(values))
+	for i, data := range values {
+		var e store.AuditEntry
+		if err := json.Unmarshal([]byte(data), &e); err != nil {
+			return nil, errors.Wrap(err, "Failed to decode audit entry")
+		}
+		entries[i] = &e
+	}
+	return store.FilterAudit(entries, f), nil
+}
+
 func (d *Driver) findKeys(prefix string) []string {
 	v, err := d.client.Keys(prefix).Result()
 	if err != nil {
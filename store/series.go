@@ -0,0 +1,346 @@
+package store
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Resolution is the width of the buckets of a Ring
+type Resolution int
+
+const (
+	// Minute buckets
+	Minute Resolution = iota
+	// Hour buckets
+	Hour
+	// Day buckets, aligned to midnight UTC
+	Day
+)
+
+// Resolutions are all of the resolutions kept by a Series
+var Resolutions = []Resolution{Minute, Hour, Day}
+
+// Duration returns the width of each bucket
+func (r Resolution) Duration() time.Duration {
+	switch r {
+	case Hour:
+		return time.Hour
+	case Day:
+		return 24 * time.Hour
+	default:
+		return time.Minute
+	}
+}
+
+// String returns the name of the resolution, eg: hour
+func (r Resolution) String() string {
+	switch r {
+	case Hour:
+		return "hour"
+	case Day:
+		return "day"
+	default:
+		return "minute"
+	}
+}
+
+// SeriesKind is the type of object a Series records
+type SeriesKind string
+
+const (
+	// SeriesTorrent series are keyed by the hex encoded infohash
+	SeriesTorrent SeriesKind = "torrent"
+	// SeriesUser series are keyed by the user_id
+	SeriesUser SeriesKind = "user"
+)
+
+// SeriesPoint holds the activity of a single bucket. Uploaded and downloaded are the actual
+// transfer, without multipliers, for torrents and the credited transfer for users.
+type SeriesPoint struct {
+	// Time is the start of the bucket
+	Time       time.Time `json:"time"`
+	Announces  uint64    `json:"announces"`
+	Snatches   uint64    `json:"snatches"`
+	Uploaded   uint64    `json:"uploaded"`
+	Downloaded uint64    `json:"downloaded"`
+	// Seeders and Leechers are the size of the swarm as of the last announce within the bucket
+	Seeders  uint32 `json:"seeders"`
+	Leechers uint32 `json:"leechers"`
+}
+
+// SeriesDelta is a change recorded into each of the rings of a Series
+type SeriesDelta struct {
+	Announces  uint64
+	Snatches   uint64
+	Uploaded   uint64
+	Downloaded uint64
+	// Swarm is set when Seeders and Leechers should be recorded
+	Swarm    bool
+	Seeders  uint32
+	Leechers uint32
+}
+
+// Ring is a fixed size ring buffer of the most recent buckets of a single resolution
+type Ring struct {
+	Resolution Resolution `json:"resolution"`
+	// Points are the buckets in the order they were written, wrapping around at Head
+	Points []SeriesPoint `json:"points"`
+	// Head is the index of the newest point
+	Head int `json:"head"`
+}
+
+// NewRing creates an empty ring holding size buckets
+func NewRing(res Resolution, size int) *Ring {
+	return &Ring{Resolution: res, Points: make([]SeriesPoint, size), Head: size - 1}
+}
+
+// add records the delta in the bucket containing t, starting a new bucket if required.
+// Deltas older than the newest bucket are added to the newest bucket.
+func (r *Ring) add(t time.Time, d SeriesDelta) {
+	if len(r.Points) == 0 {
+		return
+	}
+	start := t.UTC().Truncate(r.Resolution.Duration())
+	if start.After(r.Points[r.Head].Time) {
+		r.Head = (r.Head + 1) % len(r.Points)
+		r.Points[r.Head] = SeriesPoint{Time: start}
+	}
+	p := &r.Points[r.Head]
+	p.Announces += d.Announces
+	p.Snatches += d.Snatches
+	p.Uploaded += d.Uploaded
+	p.Downloaded += d.Downloaded
+	if d.Swarm {
+		p.Seeders = d.Seeders
+		p.Leechers = d.Leechers
+	}
+}
+
+// ordered returns the non empty points, oldest first
+func (r *Ring) ordered() []SeriesPoint {
+	var points []SeriesPoint
+	for i := 1; i <= len(r.Points); i++ {
+		p := r.Points[(r.Head+i)%len(r.Points)]
+		if !p.Time.IsZero() {
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// resize returns a ring of the new size keeping the newest points
+func (r *Ring) resize(size int) *Ring {
+	nr := NewRing(r.Resolution, size)
+	points := r.ordered()
+	if len(points) > size {
+		points = points[len(points)-size:]
+	}
+	for _, p := range points {
+		nr.Head = (nr.Head + 1) % size
+		nr.Points[nr.Head] = p
+	}
+	return nr
+}
+
+// merge adds the points of o into the ring, summing the buckets which share the same time.
+// Only the newest points which fit within the ring are kept.
+func (r *Ring) merge(o *Ring) {
+	byTime := make(map[time.Time]SeriesPoint)
+	for _, p := range append(r.ordered(), o.ordered()...) {
+		m := byTime[p.Time]
+		m.Time = p.Time
+		m.Announces += p.Announces
+		m.Snatches += p.Snatches
+		m.Uploaded += p.Uploaded
+		m.Downloaded += p.Downloaded
+		m.Seeders += p.Seeders
+		m.Leechers += p.Leechers
+		byTime[p.Time] = m
+	}
+	points := make([]SeriesPoint, 0, len(byTime))
+	for _, p := range byTime {
+		points = append(points, p)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+	if len(points) > len(r.Points) {
+		points = points[len(points)-len(r.Points):]
+	}
+	size := len(r.Points)
+	r.Points = make([]SeriesPoint, size)
+	r.Head = size - 1
+	for _, p := range points {
+		r.Head = (r.Head + 1) % size
+		r.Points[r.Head] = p
+	}
+}
+
+// Range returns the count most recent buckets up to and including the bucket containing now,
+// oldest first. Buckets without any announces are filled in with zero values, carrying forward
+// the swarm size of the previous bucket.
+func (r *Ring) Range(now time.Time, count int) []SeriesPoint {
+	if count <= 0 || count > len(r.Points) {
+		count = len(r.Points)
+	}
+	width := r.Resolution.Duration()
+	end := now.UTC().Truncate(width)
+	start := end.Add(-time.Duration(count-1) * width)
+	byTime := make(map[time.Time]SeriesPoint)
+	var prev *SeriesPoint
+	for _, p := range r.ordered() {
+		if p.Time.Before(start) {
+			last := p
+			prev = &last
+			continue
+		}
+		byTime[p.Time] = p
+	}
+	points := make([]SeriesPoint, 0, count)
+	for t := start; !t.After(end); t = t.Add(width) {
+		p, found := byTime[t]
+		if !found {
+			p = SeriesPoint{Time: t}
+			if prev != nil {
+				p.Seeders = prev.Seeders
+				p.Leechers = prev.Leechers
+			}
+		}
+		points = append(points, p)
+		prev = &points[len(points)-1]
+	}
+	return points
+}
+
+// Series holds the rolling activity of a single torrent or user at each Resolution
+type Series struct {
+	mu    *sync.RWMutex
+	Kind  SeriesKind
+	Key   string
+	Rings map[Resolution]*Ring
+	// Keeps track of how often the values have changed since the last sync
+	Writes uint32
+}
+
+// NewSeries creates a series with rings of the size given for each resolution, resolutions
+// with a size of 0 are not recorded
+func NewSeries(kind SeriesKind, key string, sizes map[Resolution]int) *Series {
+	s := &Series{mu: &sync.RWMutex{}, Kind: kind, Key: key, Rings: make(map[Resolution]*Ring)}
+	for res, size := range sizes {
+		if size > 0 {
+			s.Rings[res] = NewRing(res, size)
+		}
+	}
+	return s
+}
+
+// TorrentSeriesKey returns the key of the series for the torrent
+func TorrentSeriesKey(ih InfoHash) string {
+	return ih.String()
+}
+
+// UserSeriesKey returns the key of the series for the user
+func UserSeriesKey(userID uint32) string {
+	return strconv.FormatUint(uint64(userID), 10)
+}
+
+// Add records the delta in each ring
+func (s *Series) Add(t time.Time, d SeriesDelta) {
+	s.mu.Lock()
+	for _, r := range s.Rings {
+		r.add(t, d)
+	}
+	s.mu.Unlock()
+	atomic.AddUint32(&s.Writes, 1)
+}
+
+// Merge adds the activity of o into the series. The swarm sizes of buckets recorded by both
+// are summed as they were separate swarms at the time. Resolutions which are not recorded
+// by the series are ignored.
+func (s *Series) Merge(o *Series) {
+	o.mu.RLock()
+	s.mu.Lock()
+	for res, r := range s.Rings {
+		if or, found := o.Rings[res]; found {
+			r.merge(or)
+		}
+	}
+	s.mu.Unlock()
+	o.mu.RUnlock()
+	atomic.AddUint32(&s.Writes, 1)
+}
+
+// Range returns the count most recent buckets of the resolution, see Ring.Range
+func (s *Series) Range(res Resolution, now time.Time, count int) ([]SeriesPoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, found := s.Rings[res]
+	if !found {
+		return nil, errors.Errorf("%s resolution is not recorded", res)
+	}
+	return r.Range(now, count), nil
+}
+
+// Resize changes the number of buckets kept for each resolution, keeping the newest points.
+// Resolutions with a size of 0 are removed.
+func (s *Series) Resize(sizes map[Resolution]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rings := make(map[Resolution]*Ring)
+	for res, size := range sizes {
+		if size <= 0 {
+			continue
+		}
+		if r, found := s.Rings[res]; found {
+			if len(r.Points) == size {
+				rings[res] = r
+			} else {
+				rings[res] = r.resize(size)
+			}
+			continue
+		}
+		rings[res] = NewRing(res, size)
+	}
+	s.Rings = rings
+}
+
+type seriesJSON struct {
+	Kind  SeriesKind `json:"kind"`
+	Key   string     `json:"key"`
+	Rings []*Ring    `json:"rings"`
+}
+
+// MarshalJSON encodes the series, this is the format used by the stores to persist the series
+func (s *Series) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sj := seriesJSON{Kind: s.Kind, Key: s.Key}
+	for _, res := range Resolutions {
+		if r, found := s.Rings[res]; found {
+			sj.Rings = append(sj.Rings, r)
+		}
+	}
+	return json.Marshal(sj)
+}
+
+// UnmarshalJSON decodes a series encoded with MarshalJSON
+func (s *Series) UnmarshalJSON(b []byte) error {
+	var sj seriesJSON
+	if err := json.Unmarshal(b, &sj); err != nil {
+		return err
+	}
+	s.mu = &sync.RWMutex{}
+	s.Kind = sj.Kind
+	s.Key = sj.Key
+	s.Rings = make(map[Resolution]*Ring)
+	for _, r := range sj.Rings {
+		if len(r.Points) == 0 || r.Head < 0 || r.Head >= len(r.Points) {
+			return errors.Errorf("invalid %s ring for series %s %s", r.Resolution, sj.Kind, sj.Key)
+		}
+		s.Rings[r.Resolution] = r
+	}
+	return nil
+}
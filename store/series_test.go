@@ -0,0 +1,112 @@
+package store
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRing(t *testing.T) {
+	start := time.Date(2020, 6, 1, 12, 0, 30, 0, time.UTC)
+	r := NewRing(Minute, 3)
+	r.add(start, SeriesDelta{Announces: 1, Uploaded: 10, Swarm: true, Seeders: 2, Leechers: 3})
+	r.add(start.Add(10*time.Second), SeriesDelta{Announces: 1, Snatches: 1, Swarm: true, Seeders: 3, Leechers: 2})
+	points := r.Range(start, 0)
+	require.Len(t, points, 3)
+	require.Equal(t, start.Truncate(time.Minute), points[2].Time)
+	require.EqualValues(t, 2, points[2].Announces)
+	require.EqualValues(t, 1, points[2].Snatches)
+	require.EqualValues(t, 10, points[2].Uploaded)
+	require.EqualValues(t, 3, points[2].Seeders)
+	require.EqualValues(t, 0, points[0].Announces)
+
+	// Quiet buckets carry forward the swarm size
+	later := start.Add(2 * time.Minute)
+	points = r.Range(later, 2)
+	require.Len(t, points, 2)
+	require.Equal(t, later.Truncate(time.Minute), points[1].Time)
+	require.EqualValues(t, 0, points[1].Announces)
+	require.EqualValues(t, 3, points[1].Seeders)
+	require.EqualValues(t, 2, points[1].Leechers)
+
+	// Buckets older than the size of the ring are overwritten
+	for i := 1; i <= 3; i++ {
+		r.add(start.Add(time.Duration(i)*time.Minute), SeriesDelta{Announces: uint64(i)})
+	}
+	ordered := r.ordered()
+	require.Len(t, ordered, 3)
+	require.EqualValues(t, 1, ordered[0].Announces)
+	require.EqualValues(t, 3, ordered[2].Announces)
+
+	resized := r.resize(2)
+	require.Equal(t, ordered[1:], resized.ordered())
+	grown := r.resize(5)
+	require.Equal(t, ordered, grown.ordered())
+	require.Len(t, grown.Points, 5)
+}
+
+func TestSeries(t *testing.T) {
+	now := time.Now()
+	s := NewSeries(SeriesUser, UserSeriesKey(10), map[Resolution]int{Minute: 60, Hour: 24, Day: 0})
+	require.Len(t, s.Rings, 2)
+	s.Add(now, SeriesDelta{Announces: 1, Uploaded: 100})
+	require.EqualValues(t, 1, s.Writes)
+	for _, res := range []Resolution{Minute, Hour} {
+		points, err := s.Range(res, now, 0)
+		require.NoError(t, err)
+		require.EqualValues(t, 100, points[len(points)-1].Uploaded)
+	}
+	_, err := s.Range(Day, now, 0)
+	require.Error(t, err)
+
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+	var decoded Series
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	require.Equal(t, SeriesUser, decoded.Kind)
+	require.Equal(t, "10", decoded.Key)
+	require.Equal(t, s.Rings, decoded.Rings)
+
+	decoded.Resize(map[Resolution]int{Minute: 10, Day: 7})
+	require.Len(t, decoded.Rings, 2)
+	require.Len(t, decoded.Rings[Minute].Points, 10)
+	points, err := decoded.Range(Minute, now, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 100, points[0].Uploaded)
+
+	require.Error(t, json.Unmarshal([]byte(`{"kind":"user","key":"1","rings":[{"resolution":0,"points":[],"head":0}]}`),
+		&decoded))
+}
+
+func TestSeriesMerge(t *testing.T) {
+	start := time.Date(2020, 6, 1, 12, 0, 30, 0, time.UTC)
+	sizes := map[Resolution]int{Minute: 3, Hour: 2}
+	dst := NewSeries(SeriesTorrent, "dst", sizes)
+	dst.Add(start, SeriesDelta{Announces: 1, Uploaded: 100, Swarm: true, Seeders: 2, Leechers: 1})
+	dst.Add(start.Add(time.Minute), SeriesDelta{Announces: 1, Uploaded: 10})
+	src := NewSeries(SeriesTorrent, "src", map[Resolution]int{Minute: 5, Day: 2})
+	src.Add(start.Add(-2*time.Minute), SeriesDelta{Announces: 5})
+	src.Add(start.Add(-time.Minute), SeriesDelta{Announces: 4})
+	src.Add(start, SeriesDelta{Announces: 2, Uploaded: 50, Swarm: true, Seeders: 3, Leechers: 0})
+	dst.Merge(src)
+	require.EqualValues(t, 3, dst.Writes)
+	require.Len(t, dst.Rings, 2, "Resolutions not recorded by the destination should be ignored")
+
+	// Only the newest points fitting within the ring are kept
+	points := dst.Rings[Minute].ordered()
+	require.Len(t, points, 3)
+	require.EqualValues(t, 4, points[0].Announces)
+	require.Equal(t, start.Truncate(time.Minute), points[1].Time)
+	require.EqualValues(t, 3, points[1].Announces)
+	require.EqualValues(t, 150, points[1].Uploaded)
+	require.EqualValues(t, 5, points[1].Seeders)
+	require.EqualValues(t, 1, points[1].Leechers)
+	require.EqualValues(t, 10, points[2].Uploaded)
+
+	// Further activity continues after the newest merged bucket
+	dst.Add(start.Add(2*time.Minute), SeriesDelta{Announces: 7})
+	points = dst.Rings[Minute].ordered()
+	require.EqualValues(t, 7, points[2].Announces)
+	require.EqualValues(t, 3, points[0].Announces)
+}
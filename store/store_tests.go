@@ -1,9 +1,12 @@
 package store
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/leighmacdonald/golib"
 	"github.com/leighmacdonald/mika/consts"
 	"github.com/leighmacdonald/mika/util"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"log"
 	"math/rand"
@@ -91,9 +94,21 @@ func TestStore(t *testing.T, s Store) {
 	require.NoError(t, s.TorrentDelete(torrentA.InfoHash, true))
 	_, err := s.TorrentGet(torrentA.InfoHash, false)
 	require.Equal(t, consts.ErrInvalidInfoHash, err)
+
+	hybrid := GenerateTestTorrent()
+	ihV2, _ := util.GenRandomBytes(32)
+	require.NoError(t, InfoHashV2FromBytes(&hybrid.InfoHashV2, ihV2))
+	require.NoError(t, s.TorrentAdd(&hybrid))
+	fetchedHybrid, errHybrid := s.TorrentGet(hybrid.InfoHash, false)
+	require.NoError(t, errHybrid)
+	require.Equal(t, hybrid.InfoHashV2, fetchedHybrid.InfoHashV2)
+	require.True(t, fetchedHybrid.IsHybrid())
+	require.NoError(t, s.TorrentDelete(hybrid.InfoHash, true))
 	wlClients := []*WhiteListClient{
-		{ClientPrefix: "UT", ClientName: "uTorrent"},
-		{ClientPrefix: "qT", ClientName: "QBittorrent"},
+		{ClientCode: "UT", ClientName: "uTorrent"},
+		{ClientCode: "qB", ClientName: "QBittorrent"},
+		{ClientCode: "qB", ClientName: "QBittorrent 4.1", MinVersion: "4.1", MaxVersion: "4.1.9"},
+		{ClientCode: "XL", ClientName: "Xunlei", MinVersion: "1.2", MaxVersion: "2.0.1", Blacklist: true},
 	}
 	for _, c := range wlClients {
 		require.NoError(t, s.WhiteListAdd(c))
@@ -101,6 +116,11 @@ func TestStore(t *testing.T, s Store) {
 	clients, err3 := s.WhiteListGetAll()
 	require.NoError(t, err3)
 	require.Equal(t, len(wlClients), len(clients))
+	for _, c := range clients {
+		if c.Key() == wlClients[3].Key() {
+			require.Equal(t, wlClients[3], c)
+		}
+	}
 	require.NoError(t, s.WhiteListDelete(wlClients[0]))
 	clientsUpdated, _ := s.WhiteListGetAll()
 	require.Equal(t, len(wlClients)-1, len(clientsUpdated))
@@ -129,6 +149,7 @@ func TestStore(t *testing.T, s Store) {
 			MultiDown:       1.0,
 			DownloadEnabled: true,
 			UploadEnabled:   true,
+			MaxPeers:        25,
 		},
 		{
 			RoleName:        "Master",
@@ -145,7 +166,9 @@ func TestStore(t *testing.T, s Store) {
 	fetchedRoles, err := s.Roles()
 	require.NoError(t, err, "failed to fetch roles")
 	require.Equal(t, len(roles), len(fetchedRoles))
-	require.NoError(t, s.RoleDelete(roles[3].RoleID))
+	require.Equal(t, roles[2].MaxPeers, fetchedRoles[roles[2].RoleID].MaxPeers)
+	_, errDel := s.RoleDelete(roles[3].RoleID, 0)
+	require.NoError(t, errDel)
 	fetchedRolesDeleted, err := s.Roles()
 	require.NoError(t, err, "failed to fetch roles")
 	require.Equal(t, len(roles)-1, len(fetchedRolesDeleted))
@@ -169,21 +192,17 @@ func TestStore(t *testing.T, s Store) {
 	require.NoError(t, err)
 	require.Equal(t, users[0].RoleID, fetchedUserPasskey.RoleID)
 
-	//batchUpdate := []*User{
-	//	{
-	//		Passkey:    users[0].Passkey,
-	//		Uploaded:   1000,
-	//		Downloaded: 2000,
-	//		Announces:  10,
-	//	},
-	//}
-	//require.NoError(t, s.UserSync(batchUpdate))
-	//time.Sleep(100 * time.Millisecond)
-	//var updatedUser User
-	//require.NoError(t, s.UserGetByPasskey(&updatedUser, users[0].Passkey))
-	//require.Equal(t, uint64(1000)+users[0].Uploaded, updatedUser.Uploaded)
-	//require.Equal(t, uint64(2000)+users[0].Downloaded, updatedUser.Downloaded)
-	//require.Equal(t, uint32(10)+users[0].Announces, updatedUser.Announces)
+	fetchedUserID.Uploaded = 1000
+	fetchedUserID.Downloaded = 2000
+	fetchedUserID.Announces = 10
+	fetchedUserID.DownloadEnabled = false
+	require.NoError(t, s.UserSync([]*User{fetchedUserID}))
+	syncedUser, err := s.UserGetByID(users[0].UserID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1000), syncedUser.Uploaded)
+	require.Equal(t, uint64(2000), syncedUser.Downloaded)
+	require.Equal(t, uint32(10), syncedUser.Announces)
+	require.False(t, syncedUser.DownloadEnabled)
 
 	newUser := GenerateTestUser()
 	newUser.RoleID = roles[0].RoleID
@@ -197,8 +216,174 @@ func TestStore(t *testing.T, s Store) {
 	require.Equal(t, newUser.Downloaded, fetchedNewUser.Downloaded)
 	require.Equal(t, newUser.Uploaded, fetchedNewUser.Uploaded)
 	require.Equal(t, newUser.Announces, fetchedNewUser.Announces)
+
+	_, errMissing := s.RoleDelete(roles[3].RoleID, 0)
+	require.True(t, errors.Is(errMissing, consts.ErrInvalidRole), "Deleted a missing role")
+	_, errReassignMissing := s.RoleDelete(roles[0].RoleID, roles[3].RoleID)
+	require.True(t, errors.Is(errReassignMissing, consts.ErrInvalidRole), "Reassigned users to a missing role")
+	_, errInUse := s.RoleDelete(roles[0].RoleID, 0)
+	require.True(t, errors.Is(errInUse, consts.ErrRoleInUse), "Deleted role still assigned to users")
+	moved, errReassign := s.RoleDelete(roles[0].RoleID, roles[1].RoleID)
+	require.NoError(t, errReassign)
+	require.Equal(t, 2, moved)
+	reassignedUser, err := s.UserGetByID(newUser.UserID)
+	require.NoError(t, err)
+	require.Equal(t, roles[1].RoleID, reassignedUser.RoleID)
+
+	sizes := map[Resolution]int{Minute: 5, Hour: 3}
+	now := time.Now()
+	torrentSeries := NewSeries(SeriesTorrent, TorrentSeriesKey(hybrid.InfoHash), sizes)
+	torrentSeries.Add(now, SeriesDelta{Announces: 2, Uploaded: 1000, Swarm: true, Seeders: 4, Leechers: 1})
+	userSeries := NewSeries(SeriesUser, UserSeriesKey(newUser.UserID), sizes)
+	userSeries.Add(now, SeriesDelta{Announces: 1, Downloaded: 500})
+	require.NoError(t, s.SeriesSync([]*Series{torrentSeries, userSeries}))
+	userSeries.Add(now, SeriesDelta{Announces: 1, Downloaded: 250})
+	require.NoError(t, s.SeriesSync([]*Series{userSeries}))
+	fetchedSeries, err := s.Series()
+	require.NoError(t, err)
+	require.Equal(t, 2, len(fetchedSeries))
+	for _, fs := range fetchedSeries {
+		points, errRange := fs.Range(Minute, now, 1)
+		require.NoError(t, errRange)
+		switch fs.Kind {
+		case SeriesTorrent:
+			require.Equal(t, torrentSeries.Key, fs.Key)
+			require.EqualValues(t, 1000, points[0].Uploaded)
+			require.EqualValues(t, 4, points[0].Seeders)
+		case SeriesUser:
+			require.Equal(t, userSeries.Key, fs.Key)
+			require.EqualValues(t, 750, points[0].Downloaded)
+			require.EqualValues(t, 2, points[0].Announces)
+		}
+		_, errRange = fs.Range(Day, now, 1)
+		require.Error(t, errRange)
+	}
+	require.NoError(t, s.SeriesDelete(SeriesUser, userSeries.Key))
+	require.NoError(t, s.SeriesDelete(SeriesUser, userSeries.Key), "Deleting a missing series should be ignored")
+	fetchedSeries, err = s.Series()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(fetchedSeries))
+	require.Equal(t, SeriesTorrent, fetchedSeries[0].Kind)
+
+	auditStart := time.Now().Add(-time.Minute).Truncate(time.Second)
+	entries := []*AuditEntry{
+		{CreatedOn: auditStart, Actor: "default", Address: "127.0.0.1", Action: "user.add",
+			Target: "user:1", After: []byte(`{"user_name":"a"}`)},
+		{CreatedOn: auditStart.Add(time.Second), Actor: "frontend", Action: "user.save", Target: "user:1",
+			Before: []byte(`{"user_name":"a"}`), After: []byte(`{"user_name":"b"}`)},
+		{CreatedOn: auditStart.Add(2 * time.Second), Actor: "default", Action: "torrent.delete",
+			Target: "torrent:x", Before: []byte(`{"title":"x"}`)},
+	}
+	for _, e := range entries {
+		require.NoError(t, s.AuditAdd(e))
+		require.NotZero(t, e.AuditID)
+	}
+	require.True(t, entries[2].AuditID > entries[0].AuditID)
+	all, err := s.AuditList(AuditFilter{Since: auditStart})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	require.Equal(t, entries[2].AuditID, all[0].AuditID)
+	require.Equal(t, "torrent.delete", all[0].Action)
+	require.JSONEq(t, `{"title":"x"}`, string(all[0].Before))
+	require.Empty(t, all[0].After)
+	require.Equal(t, "127.0.0.1", all[2].Address)
+	require.True(t, all[2].CreatedOn.Equal(auditStart))
+	byAction, err := s.AuditList(AuditFilter{Action: "user", Since: auditStart})
+	require.NoError(t, err)
+	require.Len(t, byAction, 2)
+	byActor, err := s.AuditList(AuditFilter{Actor: "default", Target: "user:1", Since: auditStart})
+	require.NoError(t, err)
+	require.Len(t, byActor, 1)
+	require.Equal(t, entries[0].AuditID, byActor[0].AuditID)
+	limited, err := s.AuditList(AuditFilter{Since: auditStart, Until: auditStart.Add(2 * time.Second), Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, limited, 1)
+	require.Equal(t, entries[1].AuditID, limited[0].AuditID)
 }
 
 func init() {
 	rand.Seed(time.Now().UnixNano())
 }
+
+// TestCopy tests copying all data from src into the empty dst store. src must also be empty.
+func TestCopy(t *testing.T, src Store, dst Store) {
+	var roles []Role
+	for i := 0; i < 2; i++ {
+		r := GenerateTestRole()
+		require.NoError(t, src.RoleAdd(&r))
+		roles = append(roles, r)
+	}
+	var users []User
+	for i := 0; i < 5; i++ {
+		u := GenerateTestUser()
+		u.RoleID = roles[i%len(roles)].RoleID
+		require.NoError(t, src.UserAdd(&u))
+		users = append(users, u)
+	}
+	var torrents []Torrent
+	for i := 0; i < 5; i++ {
+		tor := GenerateTestTorrent()
+		tor.Snatches = uint32(i)
+		tor.Uploaded = uint64(i * 1000)
+		require.NoError(t, src.TorrentAdd(&tor))
+		require.NoError(t, src.TorrentSave(&tor))
+		torrents = append(torrents, tor)
+	}
+	wl := WhiteListClient{ClientCode: "TT", ClientName: "Test Client", MinVersion: "1.0"}
+	require.NoError(t, src.WhiteListAdd(&wl))
+	sizes := map[Resolution]int{Minute: 5, Hour: 3}
+	now := time.Now().UTC()
+	torrentSeries := NewSeries(SeriesTorrent, TorrentSeriesKey(torrents[0].InfoHash), sizes)
+	torrentSeries.Add(now, SeriesDelta{Announces: 2, Uploaded: 1000})
+	userSeries := NewSeries(SeriesUser, UserSeriesKey(users[1].UserID), sizes)
+	userSeries.Add(now, SeriesDelta{Announces: 1, Downloaded: 500})
+	require.NoError(t, src.SeriesSync([]*Series{torrentSeries, userSeries}))
+	for i, action := range []string{"user.add", "user.save"} {
+		e := AuditEntry{
+			CreatedOn: now.Add(time.Duration(i-2) * time.Hour).Truncate(time.Second),
+			Actor:     "test",
+			Address:   "127.0.0.1",
+			Action:    action,
+			Target:    fmt.Sprintf("user:%d", users[0].UserID),
+			After:     json.RawMessage(fmt.Sprintf(`{"user_id": %d}`, users[0].UserID)),
+		}
+		require.NoError(t, src.AuditAdd(&e))
+	}
+
+	var reports []CopyProgress
+	res, err := Copy(src, dst, func(p CopyProgress) {
+		reports = append(reports, p)
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, res.Counts[TableRoles])
+	require.Equal(t, 5, res.Counts[TableUsers])
+	require.Equal(t, 5, res.Counts[TableTorrents])
+	require.Equal(t, 1, res.Counts[TableWhiteList])
+	require.Equal(t, 2, res.Counts[TableSeries])
+	require.Equal(t, 2, res.Counts[TableAuditLog])
+	require.Len(t, res.UserIDs, 5)
+	require.Len(t, reports, 6)
+	entries, err := dst.AuditList(AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "user.save", entries[0].Action, "entries must keep their order")
+	require.Equal(t, now.Add(-time.Hour).Truncate(time.Second).Unix(), entries[0].CreatedOn.Unix())
+	dstSeries, err := dst.Series()
+	require.NoError(t, err)
+	keys := make(map[string]bool)
+	for _, s := range dstSeries {
+		keys[string(s.Kind)+":"+s.Key] = true
+	}
+	require.True(t, keys["torrent:"+TorrentSeriesKey(torrents[0].InfoHash)])
+	require.True(t, keys["user:"+UserSeriesKey(res.UserIDs[users[1].UserID])],
+		"user series must use the new user_id")
+
+	results, err := Verify(src, dst)
+	require.NoError(t, err)
+	for _, r := range results {
+		require.True(t, r.OK(), "%s does not match", r.Table)
+	}
+
+	_, err = Copy(src, dst, nil)
+	require.Error(t, err, "copying into a non-empty store must fail")
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/leighmacdonald/mika/consts"
 	"github.com/leighmacdonald/mika/util"
 	log "github.com/sirupsen/logrus"
-	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -46,8 +46,11 @@ func (ph PeerHash) PeerID() PeerID {
 type InfoHash [20]byte
 
 // InfoHashFromString returns a binary infohash from the info string
+//
+// A full 32 byte v2 infohash is also accepted, in which case it is truncated to 20 bytes
+// as per BEP52
 func InfoHashFromString(infoHash *InfoHash, s string) error {
-	if len(s) != 20 {
+	if len(s) != 20 && len(s) != 32 {
 		return consts.ErrInvalidInfoHash
 	}
 	copy(infoHash[:], s)
@@ -55,8 +58,11 @@ func InfoHashFromString(infoHash *InfoHash, s string) error {
 }
 
 // InfoHashFromHex returns a binary infohash from a byte array
+//
+// A full 64 character v2 infohash is also accepted, in which case it is truncated to 20 bytes
+// as per BEP52
 func InfoHashFromHex(infoHash *InfoHash, h string) error {
-	if len(h) != 40 {
+	if len(h) != 40 && len(h) != 64 {
 		return consts.ErrInvalidInfoHash
 	}
 	b, err := hex.DecodeString(h)
@@ -123,10 +129,87 @@ func (ih *InfoHash) RawString() string {
 	return string(ih.Bytes())
 }
 
+// InfoHashV2 is the full 32 byte SHA-256 identifier of a BitTorrent v2 (BEP52) torrent
+type InfoHashV2 [32]byte
+
+// InfoHashV2FromBytes returns a binary v2 infohash from a byte array
+func InfoHashV2FromBytes(infoHash *InfoHashV2, b []byte) error {
+	if len(b) != 32 {
+		return consts.ErrInvalidInfoHash
+	}
+	copy(infoHash[:], b)
+	return nil
+}
+
+// InfoHashV2FromHex returns a binary v2 infohash from a hex encoded string
+func InfoHashV2FromHex(infoHash *InfoHashV2, h string) error {
+	if len(h) != 64 {
+		return consts.ErrInvalidInfoHash
+	}
+	b, err := hex.DecodeString(h)
+	if err != nil {
+		return err
+	}
+	copy(infoHash[:], b)
+	return nil
+}
+
+// Value implements the database.Valuer interface. Torrents without a v2 infohash are stored as NULL.
+func (ih *InfoHashV2) Value() (driver.Value, error) {
+	if ih.IsZero() {
+		return nil, nil
+	}
+	return ih.Bytes(), nil
+}
+
+// Scan implements the sql.Scanner interface for conversion to our custom type
+func (ih *InfoHashV2) Scan(v interface{}) error {
+	if v == nil {
+		*ih = InfoHashV2{}
+		return nil
+	}
+	vt, ok := v.([]byte)
+	if !ok {
+		return errors.New("failed to convert value to v2 infohash")
+	}
+	cnt := copy(ih[:], vt)
+	if cnt != 32 {
+		return fmt.Errorf("invalid data length received: %d, expected 32", cnt)
+	}
+	return nil
+}
+
+// Bytes returns the raw bytes of the v2 info_hash
+func (ih InfoHashV2) Bytes() []byte {
+	return ih[:]
+}
+
+// String implements fmt.Stringer, returning the base16 encoded v2 infohash.
+func (ih InfoHashV2) String() string {
+	return fmt.Sprintf("%x", ih[:])
+}
+
+// IsZero returns true when no v2 infohash is set
+func (ih InfoHashV2) IsZero() bool {
+	return ih == InfoHashV2{}
+}
+
+// Truncated returns the first 20 bytes of the v2 infohash. This is the form used by clients
+// when announcing and scraping v2 torrents.
+func (ih InfoHashV2) Truncated() InfoHash {
+	var buf InfoHash
+	copy(buf[:], ih[0:20])
+	return buf
+}
+
 // Torrent is the core struct for our torrent being tracked
 type Torrent struct {
+	// InfoHash is the key used for the torrents swarm. This is the v1 SHA-1 infohash for v1 and hybrid
+	// torrents and the truncated v2 infohash for v2 only torrents.
 	InfoHash InfoHash `db:"info_hash" json:"info_hash"`
-	Snatches uint32   `db:"total_completed" json:"total_completed"`
+	// InfoHashV2 is the full v2 infohash for v2 and hybrid torrents, otherwise its left zeroed
+	InfoHashV2 InfoHashV2 `db:"info_hash_v2" json:"info_hash_v2"`
+	Snatches   uint32     `db:"total_completed" json:"total_completed"`
 	// This is stored as MB to reduce storage costs
 	Uploaded uint64 `db:"total_uploaded" json:"total_uploaded"`
 	// This is stored as MB to reduce storage costs
@@ -161,14 +244,21 @@ type Torrent struct {
 }
 
 func (t *Torrent) Log() *log.Entry {
+	// The counters are updated concurrently by announces to the swarm
 	return log.WithFields(log.Fields{
-		"seeders":  t.Seeders,
-		"leechers": t.Leechers,
-		"snatches": t.Snatches,
-		"ann":      t.Announces,
+		"seeders":  atomic.LoadUint32(&t.Seeders),
+		"leechers": atomic.LoadUint32(&t.Leechers),
+		"snatches": atomic.LoadUint32(&t.Snatches),
+		"ann":      atomic.LoadUint64(&t.Announces),
 	})
 }
 
+// IsHybrid returns true for torrents that have both v1 and v2 infohashes. Clients of hybrid torrents
+// may announce using either hash, both of which must resolve to the same swarm.
+func (t *Torrent) IsHybrid() bool {
+	return !t.InfoHashV2.IsZero() && t.InfoHash != t.InfoHashV2.Truncated()
+}
+
 type TorrentUpdate struct {
 	Keys        []string
 	ReleaseName string  `json:"release_name"`
@@ -262,12 +352,67 @@ type Torrents map[InfoHash]*Torrent
 // WhiteListClient defines a whitelisted bittorrent client allowed to participate
 // in swarms. This is not a foolproof solution as its fairly trivial for a motivated
 // attacker to fake this.
+//
+// Clients are matched using the client code decoded from the peer_id, eg: qB for qBittorrent
+// or S for Shadow's client. The optional MinVersion and MaxVersion limit the match to the
+// inclusive range of client versions. Blacklist entries deny matching clients instead.
 type WhiteListClient struct {
-	ClientPrefix string `db:"client_prefix" json:"client_prefix"`
-	ClientName   string `db:"client_name" json:"client_name"`
+	ClientCode string `db:"client_code" json:"client_code"`
+	ClientName string `db:"client_name" json:"client_name"`
+	MinVersion string `db:"min_version" json:"min_version"`
+	MaxVersion string `db:"max_version" json:"max_version"`
+	Blacklist  bool   `db:"blacklist" json:"blacklist"`
+}
+
+// Key returns the unique key of the entry. Multiple entries can exist for a client code
+// as long as their version ranges differ.
+func (wl WhiteListClient) Key() string {
+	if wl.MinVersion == "" && wl.MaxVersion == "" {
+		return wl.ClientCode
+	}
+	return fmt.Sprintf("%s:%s-%s", wl.ClientCode, wl.MinVersion, wl.MaxVersion)
+}
+
+// Validate checks that the client code is known and the version range can be parsed
+func (wl WhiteListClient) Validate() error {
+	if _, found := clientName(wl.ClientCode); !found {
+		return consts.ErrInvalidClient
+	}
+	for _, v := range []string{wl.MinVersion, wl.MaxVersion} {
+		if v == "" {
+			continue
+		}
+		if _, err := ParseVersion(v); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// Match returns true if the client matches this prefix
-func (wl WhiteListClient) Match(client string) bool {
-	return strings.HasPrefix(client, wl.ClientPrefix)
+// Match returns true if the client has the same client code and its version is within
+// the version range. Clients with versions which could not be decoded never match a
+// version range.
+func (wl WhiteListClient) Match(cl BTClient) bool {
+	if cl.Code != wl.ClientCode {
+		return false
+	}
+	if wl.MinVersion == "" && wl.MaxVersion == "" {
+		return true
+	}
+	if cl.Name == ClientUnknown {
+		return false
+	}
+	if wl.MinVersion != "" {
+		minVersion, err := ParseVersion(wl.MinVersion)
+		if err != nil || cl.Compare(minVersion) < 0 {
+			return false
+		}
+	}
+	if wl.MaxVersion != "" {
+		maxVersion, err := ParseVersion(wl.MaxVersion)
+		if err != nil || cl.Compare(maxVersion) > 0 {
+			return false
+		}
+	}
+	return true
 }
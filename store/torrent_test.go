@@ -15,3 +15,25 @@ func TestInfoHash(t *testing.T) {
 	require.Equal(t, hexEncoded, ih1.String())
 	require.Equal(t, bytes, ih1.Bytes())
 }
+
+func TestWhiteListClientMatch(t *testing.T) {
+	qb4170 := ClientString(PeerIDFromString("-qB4170-u-rGseINmloG"))
+	qb4330 := ClientString(PeerIDFromString("-qB4330-u-rGseINmloG"))
+	allVersions := WhiteListClient{ClientCode: "qB"}
+	require.NoError(t, allVersions.Validate())
+	require.True(t, allVersions.Match(qb4170))
+	require.True(t, allVersions.Match(qb4330))
+	require.False(t, allVersions.Match(ClientString(PeerIDFromString("-TR3000-u-rGseINmloG"))))
+
+	ranged := WhiteListClient{ClientCode: "qB", MinVersion: "4.2", MaxVersion: "4.3.3"}
+	require.NoError(t, ranged.Validate())
+	require.NotEqual(t, allVersions.Key(), ranged.Key())
+	require.False(t, ranged.Match(qb4170))
+	require.True(t, ranged.Match(qb4330))
+	ranged.MaxVersion = "4.3.2"
+	require.False(t, ranged.Match(qb4330))
+
+	require.Error(t, WhiteListClient{ClientCode: ""}.Validate())
+	require.Error(t, WhiteListClient{ClientCode: "-qB4330-"}.Validate())
+	require.Error(t, WhiteListClient{ClientCode: "qB", MinVersion: "four"}.Validate())
+}
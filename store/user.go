@@ -49,7 +49,7 @@ func (r Roles) Get(roleID uint32) *Role {
 	return r[roleID]
 }
 
-// WhiteList is a map of whitelisted clients by 8 chars of client prefix
+// WhiteList is a map of whitelisted clients by WhiteListClient.Key
 type WhiteList map[string]*WhiteListClient
 
 // Remove removes a users from a Users slice
@@ -57,6 +57,8 @@ func (users Users) Remove(p *User) {
 	delete(users, p.Passkey)
 }
 
+// Role defines the permissions and limits applied to the users assigned to it. A MaxPeers value
+// of 0 uses the tracker max_peers.
 type Role struct {
 	RoleID          uint32    `json:"role_id" db:"role_id"`
 	RemoteID        uint64    `json:"remote_id" db:"remote_id"`
@@ -66,6 +68,7 @@ type Role struct {
 	MultiDown       float64   `json:"multi_down" db:"multi_down"`
 	DownloadEnabled bool      `json:"download_enabled" db:"download_enabled"`
 	UploadEnabled   bool      `json:"upload_enabled" db:"upload_enabled"`
+	MaxPeers        int32     `json:"max_peers" db:"max_peers"`
 	CreatedOn       time.Time `json:"created_on" db:"created_on"`
 	UpdatedOn       time.Time `json:"updated_on" db:"updated_on"`
 }
@@ -2,8 +2,10 @@ package tracker
 
 import (
 	"bytes"
+	"fmt"
 	"github.com/chihaya/bencode"
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	"github.com/leighmacdonald/mika/config"
 	"github.com/leighmacdonald/mika/consts"
 	"github.com/leighmacdonald/mika/metrics"
@@ -20,7 +22,12 @@ import (
 //
 // TODO use gin binding func?
 type announceRequest struct {
-	Compact bool // Force compact always?
+	// Compact peer lists (BEP23) are sent unless the client explicitly asks for the dictionary
+	// model with compact=0 and compact responses are not being forced by the config.
+	Compact bool
+
+	// NoPeerID omits the peer id from the dictionary model peer lists. Ignored for compact responses.
+	NoPeerID bool
 
 	// The total amount downloaded (since the client sent the 'started' event to the tracker) in
 	// base ten ASCII. While not explicitly stated in the official specification, the consensus is that
@@ -63,6 +70,8 @@ type announceRequest struct {
 	// it indicates only that client can communicate via IPv6.
 	IP   net.IP
 	IPv6 bool
+	// RemoteIP is the address the request was received from, ignoring any client supplied address
+	RemoteIP net.IP
 	// urlencoded 20-byte SHA1 hash of the value of the info key from the Metainfo file. Note that the
 	// value will be a bencoded dictionary, given the definition of the info key above.
 	InfoHash store.InfoHash
@@ -91,6 +100,13 @@ type announceRequest struct {
 	Key string
 
 	CryptoLevel consts.CryptoLevel
+
+	// UserAgent is the User-Agent header sent with the request
+	UserAgent string
+
+	// WebRTC is set for announces received over the WebTorrent websocket protocol. These peers
+	// can only be reached by relaying signalling messages through the tracker.
+	WebRTC bool
 }
 
 // Parse the query string into an announceRequest struct
@@ -121,6 +137,11 @@ func newAnnounce(c *gin.Context) (*announceRequest, errCode) {
 		log.Warnf("Attempt to use non-routable IP value: %s", ipAddr.String())
 		return nil, msgMalformedRequest
 	}
+	remoteIP := ipAddr
+	if config.Tracker.AllowClientIP {
+		// A failure here only means we cannot tell the client its external ip
+		remoteIP, _, _ = getIP(q, false, c)
+	}
 	port := getUint16Key(q, paramPort, 0)
 	if port < 1024 {
 		// Don't allow privileged ports which require root to bind to on unix
@@ -133,34 +154,42 @@ func newAnnounce(c *gin.Context) (*announceRequest, errCode) {
 		cryptoLevel = consts.Supported
 	}
 	return &announceRequest{
-		Compact:     true, // Ignored and always set to true
+		Compact:     config.Tracker.ForceCompact || getBoolKey(q, paramCompact, true),
+		NoPeerID:    getBoolKey(q, paramNoPeerID, false),
 		Corrupt:     getUint32Key(q, paramCorrupt, 0),
 		Downloaded:  getUint32Key(q, paramDownloaded, 0),
 		Event:       consts.ParseAnnounceType(q.Params[paramEvent]),
 		IPv6:        ipv6,
 		IP:          ipAddr,
+		RemoteIP:    remoteIP,
 		InfoHash:    infoHash,
 		Left:        getUint32Key(q, paramLeft, 0),
-		NumWant:     getUintKey(q, paramNumWant, 30),
+		NumWant:     getNumWant(q, 30),
 		PeerID:      store.PeerIDFromString(peerID),
 		Port:        port,
 		Key:         q.Params[paramKey],
+		TrackerID:   q.Params[paramTrackerID],
 		Uploaded:    getUint32Key(q, paramUploaded, 0),
 		CryptoLevel: cryptoLevel,
+		UserAgent:   c.Request.UserAgent(),
 	}, msgOk
 }
 
 // The meaty bits.
-// NOTE we ONLY support compact response formats (binary format) by design even though its
-// technically breaking the protocol specs.
-// There is no reason to support the older less efficient model for private needs
+// Compact response formats (binary format) are used by default. The older dictionary model is only
+// used when requested by the client and the tracker is not configured to force compact responses.
 func announce(c *gin.Context) {
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		// WebTorrent clients use the same announce url
+		wsAnnounce(c)
+		return
+	}
 	// Check that the user is valid before parsing anything
 	start := time.Now()
 	atomic.AddInt64(&metrics.AnnounceTotal, 1)
 	pk := c.Param("passkey")
-	var usr store.User
-	if valid := preFlightChecks(&usr, pk, c); !valid {
+	usr, valid := preFlightChecks(pk)
+	if !valid {
 		oops(c, msgInvalidAuth)
 		atomic.AddInt64(&metrics.AnnounceStatusUnauthorized, 1)
 		return
@@ -172,103 +201,283 @@ func announce(c *gin.Context) {
 		atomic.AddInt64(&metrics.AnnounceStatusMalformed, 1)
 		return
 	}
+	// Users with downloading disabled may only continue seeding
+	if !usr.DownloadEnabled && req.Left > 0 && !config.Tracker.Public {
+		oops(c, msgDownloadDisabled)
+		atomic.AddInt64(&metrics.AnnounceStatusUnauthorized, 1)
+		return
+	}
 	// TODO save this check
 	if !ClientWhitelisted(req.PeerID) {
 		oops(c, msgBadClient)
+		atomic.AddInt64(&metrics.AnnounceStatusUnauthorized, 1)
 		return
 	}
+	spoofed := config.Tracker.ClientSpoofCheck && store.ClientSpoofed(req.PeerID, req.UserAgent)
+	if spoofed {
+		log.Warnf("Client mismatch for user %d, peer_id: %s user-agent: %s",
+			usr.UserID, store.ClientString(req.PeerID).String(), req.UserAgent)
+		atomic.AddInt64(&metrics.AnnounceClientSpoofed, 1)
+		publish(Event{Type: EventCheat, InfoHash: req.InfoHash, PeerID: req.PeerID, UserID: usr.UserID,
+			Reason: fmt.Sprintf("client spoofed, peer_id: %s user-agent: %s",
+				store.ClientString(req.PeerID).String(), req.UserAgent)})
+		if config.Tracker.ClientSpoofReject {
+			oops(c, msgClientSpoofed)
+			return
+		}
+	}
 	if pk == "" && config.Tracker.Public {
 		// Use client key to track user stats for public mode
 		pk = req.Key
 	}
 	// Get & Validate the torrent associated with the info_hash supplies
-	tor, errGet := TorrentGet(req.InfoHash, false)
-	if errGet != nil || tor.IsDeleted {
-		if !errors.Is(errGet, consts.ErrInvalidInfoHash) {
-			log.Errorf("Error fetching torrent: %v", errGet)
-			oops(c, msgGenericError)
-			return
-		}
-		if config.Tracker.AutoRegister {
-			newTor := store.NewTorrent(req.InfoHash)
-			if err := TorrentAdd(&newTor); err != nil {
-				log.Errorf("Failed to auto register torrent: %s", err.Error())
-				oops(c, msgGenericError)
-				return
-			}
-			tor = &newTor
-		} else {
-			log.Debugf("No torrent found matching: %x", req.InfoHash.Bytes())
-			oops(c, msgInvalidInfoHash)
-			atomic.AddInt64(&metrics.AnnounceStatusInvalidInfoHash, 1)
-			return
-		}
+	tor, code := announceTorrent(req.InfoHash)
+	if code != msgOk {
+		oops(c, code)
+		return
 	}
 	// If disabled and reason is set, the reason is returned to the client
 	// This is mostly useful for when a torrent has been "trumped" by another torrent so it
 	// should be downloaded instead
-	//
-	// TODO send this as a "warning message" field of a normal announce response instead?
-	if !tor.IsEnabled && tor.Reason != "" {
+	disabled := !tor.IsEnabled && tor.Reason != ""
+	if disabled && !config.Tracker.DisabledTorrentWarning {
 		log.Debugf("Torrent found but is disabled: %x", req.InfoHash.Bytes())
 		c.Data(int(msgInvalidInfoHash), gin.MIMEPlain, responseError(tor.Reason))
 		return
 	}
-	peer, err := tor.Peers.Get(req.PeerID)
-	if err != nil {
-		if err == consts.ErrInvalidPeerID {
-			// Create a new peer for the swarm
-			peer = store.NewPeer(usr.UserID, req.PeerID, req.IP, req.Port)
-			// Dont add download/upload stats because they would be doubled if applied in the
-			// state update. Left is set because its always a static value being set and a (safe) data race
-			// can occur for counting seeder/leecher states
-			peer.Client = store.ClientString(req.PeerID).String()
-			// TODO allow this to be updated in the perm storage when a client changes settings
-			peer.CryptoLevel = req.CryptoLevel
-			l := geodb.GetLocation(peer.IP)
-			peer.Location = l.LatLong
-			peer.ASN = l.ASN
-			peer.AS = l.AS
-			peer.CountryCode = l.ISOCode
-			tor.Peers.Add(peer)
-		} else {
-			oops(c, msgGenericError)
+	peer, code := announcePeer(tor, usr, req)
+	if code != msgOk {
+		oops(c, code)
+		return
+	}
+	peer.Spoofed = spoofed
+	metrics.AddAnnounceClient(peer.Client)
+	stripPeers := disabled
+	if retryIn := announceRetryIn(req, peer); retryIn > 0 {
+		atomic.AddInt64(&metrics.AnnounceStatusThrottled, 1)
+		if !config.Tracker.AnnounceThrottleStrip {
+			c.Data(int(msgClientRequestTooFast), gin.MIMEPlain, responseTooFast(retryIn))
 			return
 		}
+		log.Debugf("Stripping peers from early announce: %s", peer.PeerID.String())
+		stripPeers = true
 	} else {
 		peer.AnnounceLast = time.Now()
 	}
+	// TODO IP.To16() != nil validation for v4 in v6 addresses
+	v4 := !req.IPv6 || (req.IPv6 && !config.Tracker.IPv6Only)
+	var peersFound []*store.Peer
+	if !stripPeers {
+		var err2 error
+		peersFound, err2 = tor.Peers.Select(maxPeers(usr, req.NumWant), func(p *store.Peer) bool {
+			if skipPeer(p, peer.PeerID, req.CryptoLevel) || (p.IPv6 && !req.IPv6) || (!p.IPv6 && !v4) {
+				return true
+			}
+			return config.Tracker.ConnectableCheck && skipUnconnectable(p, peer)
+		})
+		if err2 != nil {
+			log.Errorf("Could not read peers from swarm: %s", err2.Error())
+			oops(c, msgGenericError)
+			return
+		}
+	}
 	atomic.SwapUint32(&peer.Left, req.Left)
-	peersFound, err2 := tor.Peers.GetN(config.Tracker.MaxPeers)
-	if err2 != nil {
-		log.Errorf("Could not read peers from swarm: %s", err2.Error())
-		oops(c, msgGenericError)
-		return
+	dict := announceResponse(tor, req)
+	if disabled {
+		addWarning(dict, tor.Reason)
 	}
-	dict := bencode.Dict{
-		"complete":     tor.Seeders,
-		"incomplete":   tor.Leechers,
-		"interval":     int(config.Tracker.AnnounceIntervalParsed.Seconds()),
-		"min interval": int(config.Tracker.AnnounceIntervalMinimumParsed.Seconds()),
+	hnr := hitAndRun(req, peer)
+	if hnr {
+		publish(Event{Type: EventHitAndRun, InfoHash: tor.InfoHash, PeerID: peer.PeerID, UserID: usr.UserID})
 	}
-	// TODO IP.To16() != nil validation for v4 in v6 addresses
-	if !req.IPv6 || (req.IPv6 && !config.Tracker.IPv6Only) {
-		dict["peers"] = makeCompactPeers(peersFound, peer.PeerID, false, req.CryptoLevel)
+	if config.Tracker.HNRWarning && hnr {
+		addWarning(dict, fmt.Sprintf("Stopped before the hit and run threshold (%s)", config.Tracker.HNRThreshold))
 	}
-	if req.IPv6 {
-		dict["peers6"] = makeCompactPeers(peersFound, peer.PeerID, true, req.CryptoLevel)
+	if !req.Compact {
+		// The dictionary model has no separate v6 list
+		dict["peers"] = makeDictPeers(peersFound, peer.PeerID, v4, req.IPv6, req.NoPeerID, req.CryptoLevel)
+	} else {
+		if v4 {
+			dict["peers"] = makeCompactPeers(peersFound, peer.PeerID, false, req.CryptoLevel)
+		}
+		if req.IPv6 {
+			dict["peers6"] = makeCompactPeers(peersFound, peer.PeerID, true, req.CryptoLevel)
+		}
 	}
 	var outBytes bytes.Buffer
 	if err := bencode.NewEncoder(&outBytes).Encode(dict); err != nil {
 		oops(c, msgGenericError)
 		return
 	}
-	updateStates(req, peer, tor, &usr)
+	updateStates(req, peer, tor, usr)
 	c.Data(int(msgOk), gin.MIMEPlain, outBytes.Bytes())
-	metrics.AddAnnounceTime(time.Since(start).Nanoseconds())
+	metrics.AnnounceLatency.ObserveSince(start)
 	tor.Log().Debug("Announced")
 }
 
+// announceResponse creates the response dict for a successful announce, without any peers
+func announceResponse(tor *store.Torrent, req *announceRequest) bencode.Dict {
+	dict := bencode.Dict{
+		"complete":     atomic.LoadUint32(&tor.Seeders),
+		"incomplete":   atomic.LoadUint32(&tor.Leechers),
+		"interval":     int(config.Tracker.AnnounceIntervalParsed.Seconds()),
+		"min interval": int(config.Tracker.AnnounceIntervalMinimumParsed.Seconds()),
+	}
+	if config.Tracker.TrackerID != "" {
+		if req.TrackerID != "" && req.TrackerID != config.Tracker.TrackerID {
+			log.Debugf("Client sent unknown tracker id: %s", req.TrackerID)
+		}
+		dict["tracker id"] = config.Tracker.TrackerID
+	}
+	if config.Tracker.ExternalIP && len(req.RemoteIP) > 0 {
+		// BEP24 uses the compact 4 or 16 byte form of the address
+		if ip4 := req.RemoteIP.To4(); ip4 != nil {
+			dict["external ip"] = string(ip4)
+		} else {
+			dict["external ip"] = string(req.RemoteIP.To16())
+		}
+	}
+	return dict
+}
+
+// addWarning sets the warning message of a response. Unlike a failure reason, clients will
+// still process the rest of the response normally.
+func addWarning(dict bencode.Dict, msg string) {
+	if existing, found := dict["warning message"]; found {
+		msg = existing.(string) + ", " + msg
+	}
+	dict["warning message"] = msg
+}
+
+// hitAndRun returns true when a peer which has downloaded data stops before the HNRThreshold
+// has passed since it joined the swarm
+func hitAndRun(req *announceRequest, peer *store.Peer) bool {
+	if req.Event != consts.STOPPED {
+		return false
+	}
+	downloaded := atomic.LoadUint64(&peer.Downloaded) + uint64(req.Downloaded)
+	return downloaded > 0 && time.Since(peer.AnnounceFirst) < config.Tracker.HNRThresholdParsed
+}
+
+// maxPeers returns the number of peers to send to the user, the lesser of the requested numwant
+// and the peer limit of the users role, or the tracker max_peers if the role has no limit
+func maxPeers(usr *store.User, numWant uint) int {
+	limit := config.Tracker.MaxPeers
+	if usr.Role != nil && usr.Role.MaxPeers > 0 {
+		limit = int(usr.Role.MaxPeers)
+	}
+	return util.Min(int(numWant), limit)
+}
+
+// announceTorrent fetches the torrent being announced, registering it first if auto
+// registration is enabled
+func announceTorrent(infoHash store.InfoHash) (*store.Torrent, errCode) {
+	tor, errGet := TorrentGet(infoHash, false)
+	if errGet == nil && !tor.IsDeleted {
+		return tor, msgOk
+	}
+	if errGet != nil && !errors.Is(errGet, consts.ErrInvalidInfoHash) {
+		log.Errorf("Error fetching torrent: %v", errGet)
+		atomic.AddInt64(&metrics.AnnounceStatusError, 1)
+		return nil, msgGenericError
+	}
+	if !config.Tracker.AutoRegister {
+		log.Debugf("No torrent found matching: %x", infoHash.Bytes())
+		atomic.AddInt64(&metrics.AnnounceStatusInvalidInfoHash, 1)
+		return nil, msgInvalidInfoHash
+	}
+	newTor := store.NewTorrent(infoHash)
+	if err := TorrentAdd(&newTor); err != nil {
+		log.Errorf("Failed to auto register torrent: %s", err.Error())
+		atomic.AddInt64(&metrics.AnnounceStatusError, 1)
+		return nil, msgGenericError
+	}
+	publish(Event{Type: EventTorrentAutoRegistered, InfoHash: newTor.InfoHash})
+	return &newTor, msgOk
+}
+
+// announcePeer fetches the announcing peer from the swarm, creating and adding a new peer
+// to the swarm if its not already a member
+func announcePeer(tor *store.Torrent, usr *store.User, req *announceRequest) (*store.Peer, errCode) {
+	peer, err := tor.Peers.Get(req.PeerID)
+	if err == nil {
+		// Peers are bound to the user and key they joined the swarm with, this stops others
+		// from updating, removing or being credited for the peer by replaying its peer_id.
+		// Peers which joined without a key must continue to announce without one.
+		reason := ""
+		if peer.UserID != usr.UserID {
+			reason = "peer user mismatch"
+		} else if req.Key != peer.Key {
+			reason = "peer key mismatch"
+		}
+		if reason != "" {
+			log.Debugf("Rejected announce for peer %s: %s", peer.PeerID.String(), reason)
+			atomic.AddInt64(&metrics.AnnounceStatusUnauthorized, 1)
+			publish(Event{Type: EventCheat, InfoHash: tor.InfoHash, PeerID: req.PeerID, UserID: usr.UserID,
+				Reason: reason})
+			return nil, msgInvalidPeerKey
+		}
+		// Only a peer which has proven its identity with its key may change its address, peers
+		// without a key keep the address they joined with
+		if peer.Key != "" && (!peer.IP.Equal(req.IP) || peer.Port != req.Port) {
+			setPeerAddr(peer, req.IP, req.Port)
+			if config.Tracker.ConnectableCheck && !peer.WebRTC {
+				atomic.StoreUint32(&peer.Connectable, store.ConnectableUnknown)
+				connChecker.check(tor.InfoHash, peer)
+			}
+		}
+		return peer, msgOk
+	}
+	if err != consts.ErrInvalidPeerID {
+		return nil, msgGenericError
+	}
+	// Create a new peer for the swarm
+	peer = store.NewPeer(usr.UserID, req.PeerID, req.IP, req.Port)
+	// Dont add download/upload stats because they would be doubled if applied in the
+	// state update. Left is set because its always a static value being set and a (safe) data race
+	// can occur for counting seeder/leecher states
+	cl := store.ClientString(req.PeerID)
+	peer.Client = cl.String()
+	peer.ClientCode = cl.Code
+	// TODO allow this to be updated in the perm storage when a client changes settings
+	peer.CryptoLevel = req.CryptoLevel
+	peer.WebRTC = req.WebRTC
+	peer.Key = req.Key
+	setPeerAddr(peer, req.IP, req.Port)
+	tor.Peers.Add(peer)
+	userPeerAdd(usr.UserID, tor.InfoHash, peer.PeerID)
+	if config.Tracker.ConnectableCheck && !peer.WebRTC {
+		connChecker.check(tor.InfoHash, peer)
+	}
+	return peer, msgOk
+}
+
+// setPeerAddr updates the address of the peer along with the location info derived from it
+func setPeerAddr(peer *store.Peer, ip net.IP, port uint16) {
+	peer.IP = ip
+	peer.Port = port
+	peer.IPv6 = ip.To4() == nil
+	l := geodb.GetLocation(ip)
+	peer.Location = l.LatLong
+	peer.ASN = l.ASN
+	peer.AS = l.AS
+	peer.CountryCode = l.ISOCode
+}
+
+// announceRetryIn returns how long the peer must wait before its next regular announce is
+// allowed, or 0 if the announce is allowed. Announces with an event and the first announce
+// of a peer are always allowed.
+func announceRetryIn(req *announceRequest, peer *store.Peer) time.Duration {
+	if req.Event != consts.ANNOUNCE || atomic.LoadUint32(&peer.Announces) == 0 {
+		return 0
+	}
+	elapsed := time.Since(peer.AnnounceLast)
+	if elapsed >= config.Tracker.AnnounceIntervalMinimumParsed {
+		return 0
+	}
+	return config.Tracker.AnnounceIntervalMinimumParsed - elapsed
+}
+
 func updateStates(req *announceRequest, peer *store.Peer, tor *store.Torrent, user *store.User) {
 	switch req.Event {
 	case consts.PAUSED:
@@ -276,7 +485,7 @@ func updateStates(req *announceRequest, peer *store.Peer, tor *store.Torrent, us
 			atomic.AddUint32(&tor.Seeders, 1)
 		}
 	case consts.STARTED:
-		if peer.Left == 0 {
+		if atomic.LoadUint32(&peer.Left) == 0 {
 			atomic.AddUint32(&tor.Seeders, 1)
 		} else {
 			atomic.AddUint32(&tor.Leechers, 1)
@@ -284,24 +493,17 @@ func updateStates(req *announceRequest, peer *store.Peer, tor *store.Torrent, us
 	case consts.COMPLETED:
 		atomic.AddUint32(&tor.Snatches, 1)
 		atomic.AddUint32(&tor.Seeders, 1)
-		if tor.Leechers >= 1 {
-			atomic.SwapUint32(&tor.Leechers, tor.Leechers-1)
-		}
+		decrUint32(&tor.Leechers)
 	case consts.STOPPED:
-		// Paused considered a seeder
-		if peer.Paused || peer.Left == 0 {
-			if tor.Seeders > 0 {
-				atomic.SwapUint32(&tor.Seeders, tor.Seeders-1)
-			}
-		} else {
-			if tor.Leechers > 0 {
-				atomic.SwapUint32(&tor.Leechers, tor.Leechers-1)
-			}
-		}
-		tor.Peers.Remove(peer.PeerID)
-		//if err := peerDelete(u.InfoHash, u.PeerID); err != nil {
-		//	log.Errorf("Could not remove peer from swarm: %s", err.Error())
-		//}
+		removePeer(tor, peer)
+	}
+	switch req.Event {
+	case consts.STARTED:
+		publish(Event{Type: EventPeerStarted, InfoHash: tor.InfoHash, PeerID: peer.PeerID, UserID: peer.UserID})
+	case consts.COMPLETED:
+		publish(Event{Type: EventPeerCompleted, InfoHash: tor.InfoHash, PeerID: peer.PeerID, UserID: peer.UserID})
+	case consts.STOPPED:
+		publish(Event{Type: EventPeerStopped, InfoHash: tor.InfoHash, PeerID: peer.PeerID, UserID: peer.UserID})
 	}
 	atomic.AddInt64(&metrics.AnnounceStatusOK, 1)
 	atomic.AddUint32(&peer.Announces, 1)
@@ -315,6 +517,82 @@ func updateStates(req *announceRequest, peer *store.Peer, tor *store.Torrent, us
 	atomic.AddUint64(&tor.DownloadedReal, uint64(req.Downloaded))
 	atomic.AddUint32(&tor.Writes, 1)
 	atomic.AddUint32(&user.Writes, 1)
+	uploaded := reportedDelta(&peer.ReportedUploaded, uint64(req.Uploaded), req.Event)
+	downloaded := reportedDelta(&peer.ReportedDownloaded, uint64(req.Downloaded), req.Event)
+	recordSeries(req, tor, user, uploaded, downloaded)
+}
+
+// reportedDelta stores the session total reported by the client and returns the change since the
+// previous announce. Clients start a new session, counting from 0, with the started event or when
+// they restart without sending it, in which case the whole total is new.
+func reportedDelta(addr *uint64, total uint64, event consts.AnnounceType) uint64 {
+	prev := atomic.SwapUint64(addr, total)
+	if event == consts.STARTED || total < prev {
+		return total
+	}
+	return total - prev
+}
+
+// decrUint32 atomically decrements the counter, stopping at 0
+func decrUint32(addr *uint32) {
+	for {
+		v := atomic.LoadUint32(addr)
+		if v == 0 || atomic.CompareAndSwapUint32(addr, v, v-1) {
+			return
+		}
+	}
+}
+
+// removePeer drops the peer from the torrents swarm and the user peer index, decrementing
+// the seeder or leecher count it was contributing to
+func removePeer(tor *store.Torrent, peer *store.Peer) {
+	// Paused considered a seeder
+	if peer.Paused || atomic.LoadUint32(&peer.Left) == 0 {
+		decrUint32(&tor.Seeders)
+	} else {
+		decrUint32(&tor.Leechers)
+	}
+	tor.Peers.Remove(peer.PeerID)
+	userPeerRemove(peer.UserID, tor.InfoHash, peer.PeerID)
+}
+
+// skipPeer returns true for peers which should not be sent to the announcing peer
+func skipPeer(peer *store.Peer, skipID store.PeerID, cl consts.CryptoLevel) bool {
+	if cl == consts.Required {
+		if !(peer.CryptoLevel == consts.Required || peer.CryptoLevel == consts.Supported) {
+			return true
+		}
+	}
+	if peer.WebRTC {
+		// WebRTC peers are not reachable over plain TCP/UDP
+		return true
+	}
+	// Skip the peers own peer_id
+	return peer.PeerID == skipID
+}
+
+// makeDictPeers generates the original dictionary model peer list with each peer
+// having its own dict containing the ip, port and optionally the peer id.
+func makeDictPeers(swarm []*store.Peer, skipID store.PeerID, v4 bool, v6 bool, noPeerID bool,
+	cl consts.CryptoLevel) []bencode.Dict {
+	peers := make([]bencode.Dict, 0, len(swarm))
+	for _, peer := range swarm {
+		if skipPeer(peer, skipID, cl) {
+			continue
+		}
+		if (peer.IPv6 && !v6) || (!peer.IPv6 && !v4) {
+			continue
+		}
+		p := bencode.Dict{
+			"ip":   peer.IP.String(),
+			"port": peer.Port,
+		}
+		if !noPeerID {
+			p["peer id"] = peer.PeerID.RawString()
+		}
+		peers = append(peers, p)
+	}
+	return peers
 }
 
 // Generate a compact peer field array containing the byte representations
@@ -322,13 +600,7 @@ func updateStates(req *announceRequest, peer *store.Peer, tor *store.Torrent, us
 func makeCompactPeers(swarm []*store.Peer, skipID store.PeerID, v6 bool, cl consts.CryptoLevel) []byte {
 	var buf bytes.Buffer
 	for _, peer := range swarm {
-		if cl == consts.Required {
-			if !(peer.CryptoLevel == consts.Required || peer.CryptoLevel == consts.Supported) {
-				continue
-			}
-		}
-		if peer.PeerID == skipID {
-			// Skip the peers own peer_id
+		if skipPeer(peer, skipID, cl) {
 			continue
 		}
 		if v6 && peer.IPv6 {
@@ -1,12 +1,21 @@
 package tracker
 
 import (
+	"bytes"
 	"fmt"
+	"github.com/chihaya/bencode"
+	"github.com/leighmacdonald/mika/config"
 	"github.com/leighmacdonald/mika/consts"
+	"github.com/leighmacdonald/mika/metrics"
 	"github.com/leighmacdonald/mika/store"
 	_ "github.com/leighmacdonald/mika/store/mysql"
 	"github.com/stretchr/testify/require"
+	"net"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestBitTorrentHandler_Announce(t *testing.T) {
@@ -157,3 +166,366 @@ func TestBitTorrentHandler_Announce(t *testing.T) {
 		}
 	}
 }
+
+func TestAnnounceResponseFormats(t *testing.T) {
+	rh := NewBitTorrentHandler()
+	tor := store.GenerateTestTorrent()
+	require.NoError(t, TorrentAdd(&tor))
+	peerV4 := store.GenerateTestPeer()
+	peerV4.IP = net.ParseIP("12.34.56.1")
+	peerV4.Port = 5001
+	peerV6 := store.GenerateTestPeer()
+	peerV6.IP = net.ParseIP("2600::1")
+	peerV6.IPv6 = true
+	peerV6.Port = 5002
+	tor.Peers.Add(peerV4)
+	tor.Peers.Add(peerV6)
+
+	announce := func(v url.Values) bencode.Dict {
+		w := performRequest(rh, "GET", fmt.Sprintf("/announce/%s?%s", testUsers[0].Passkey, v.Encode()), nil, nil)
+		require.EqualValues(t, msgOk, errCode(w.Code))
+		resp, err := bencode.Unmarshal(w.Body.Bytes())
+		require.NoError(t, err)
+		return resp.(bencode.Dict)
+	}
+	req := testReq{Ih: tor.InfoHash, PID: testLeechers[0].PeerID, IP: "12.34.56.78",
+		Port: "4000", Uploaded: "0", Downloaded: "0", left: "5000", PK: testUsers[0].Passkey}
+
+	// Compact is the default when not specified
+	resp := announce(req.ToValues())
+	require.Equal(t, string(append(peerV4.IP.To4(), 0x13, 0x89)), resp["peers"])
+	require.NotContains(t, resp, "peers6")
+
+	// IPv6 announces get both lists
+	v := req.ToValues()
+	v.Del("ip")
+	v.Set("ipv6", "2600::2")
+	v.Set("compact", "1")
+	resp = announce(v)
+	require.Equal(t, string(append(peerV4.IP.To4(), 0x13, 0x89)), resp["peers"])
+	require.Equal(t, string(append(peerV6.IP.To16(), 0x13, 0x8a)), resp["peers6"])
+
+	// Dictionary model
+	v = req.ToValues()
+	v.Set("compact", "0")
+	resp = announce(v)
+	peers := resp["peers"].(bencode.List)
+	require.Len(t, peers, 1)
+	p := peers[0].(bencode.Dict)
+	require.Equal(t, peerV4.IP.String(), p["ip"])
+	require.EqualValues(t, peerV4.Port, p["port"])
+	require.Equal(t, peerV4.PeerID.RawString(), p["peer id"])
+
+	v.Set("no_peer_id", "1")
+	resp = announce(v)
+	peers = resp["peers"].(bencode.List)
+	require.Len(t, peers, 1)
+	p = peers[0].(bencode.Dict)
+	require.Equal(t, peerV4.IP.String(), p["ip"])
+	require.NotContains(t, p, "peer id")
+
+	// Dictionary model includes v6 peers in the same list
+	v.Del("ip")
+	v.Set("ipv6", "2600::2")
+	resp = announce(v)
+	require.Len(t, resp["peers"].(bencode.List), 2)
+	require.NotContains(t, resp, "peers6")
+
+	config.Tracker.ForceCompact = true
+	defer func() { config.Tracker.ForceCompact = false }()
+	v = req.ToValues()
+	v.Set("compact", "0")
+	resp = announce(v)
+	require.Equal(t, string(append(peerV4.IP.To4(), 0x13, 0x89)), resp["peers"])
+}
+
+func TestAnnounceThrottle(t *testing.T) {
+	config.Tracker.AnnounceIntervalMinimumParsed = time.Minute
+	defer func() { config.Tracker.AnnounceIntervalMinimumParsed = 0 }()
+	rh := NewBitTorrentHandler()
+	tor := store.GenerateTestTorrent()
+	require.NoError(t, TorrentAdd(&tor))
+	seeder := store.GenerateTestPeer()
+	seeder.IP = net.ParseIP("12.34.56.1")
+	tor.Peers.Add(seeder)
+
+	announce := func(event consts.AnnounceType) *httptest.ResponseRecorder {
+		req := testReq{Ih: tor.InfoHash, PID: testLeechers[0].PeerID, IP: "12.34.56.78",
+			Port: "4000", Uploaded: "0", Downloaded: "0", left: "5000", PK: testUsers[0].Passkey, event: string(event)}
+		return performRequest(rh, "GET", fmt.Sprintf("/announce/%s?%s", req.PK, req.ToValues().Encode()), nil, nil)
+	}
+	throttled := atomic.LoadInt64(&metrics.AnnounceStatusThrottled)
+	require.EqualValues(t, msgOk, errCode(announce(consts.STARTED).Code))
+
+	// Regular announces before the minimum interval are rejected
+	w := announce(consts.ANNOUNCE)
+	require.EqualValues(t, msgClientRequestTooFast, errCode(w.Code))
+	resp, err := bencode.Unmarshal(w.Body.Bytes())
+	require.NoError(t, err)
+	d := resp.(bencode.Dict)
+	require.Equal(t, responseStringMap[msgClientRequestTooFast].Error(), d["failure reason"])
+	require.EqualValues(t, 60, d["min interval"])
+	require.EqualValues(t, 1, d["retry in"])
+	require.Equal(t, throttled+1, atomic.LoadInt64(&metrics.AnnounceStatusThrottled))
+
+	// Events are exempt
+	require.EqualValues(t, msgOk, errCode(announce(consts.COMPLETED).Code))
+
+	// Or processed with the peers removed
+	config.Tracker.AnnounceThrottleStrip = true
+	defer func() { config.Tracker.AnnounceThrottleStrip = false }()
+	w = announce(consts.ANNOUNCE)
+	require.EqualValues(t, msgOk, errCode(w.Code))
+	resp, err = bencode.Unmarshal(w.Body.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "", resp.(bencode.Dict)["peers"])
+	require.Equal(t, throttled+2, atomic.LoadInt64(&metrics.AnnounceStatusThrottled))
+
+	// Once the interval has passed peers are returned again
+	peer, err := tor.Peers.Get(testLeechers[0].PeerID)
+	require.NoError(t, err)
+	peer.AnnounceLast = time.Now().Add(-time.Minute)
+	w = announce(consts.ANNOUNCE)
+	require.EqualValues(t, msgOk, errCode(w.Code))
+	resp, err = bencode.Unmarshal(w.Body.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, string(append(seeder.IP.To4(), byte(seeder.Port>>8), byte(seeder.Port))), resp.(bencode.Dict)["peers"])
+}
+
+func TestAnnounceResponseExtras(t *testing.T) {
+	rh := NewBitTorrentHandler()
+	tor := store.GenerateTestTorrent()
+	require.NoError(t, TorrentAdd(&tor))
+	req := testReq{Ih: tor.InfoHash, PID: testLeechers[0].PeerID, IP: "12.34.56.78",
+		Port: "4000", Uploaded: "0", Downloaded: "0", left: "5000", PK: testUsers[0].Passkey}
+	announce := func(v url.Values) bencode.Dict {
+		w := performRequest(rh, "GET", fmt.Sprintf("/announce/%s?%s", req.PK, v.Encode()), nil, nil)
+		require.EqualValues(t, msgOk, errCode(w.Code))
+		resp, err := bencode.Unmarshal(w.Body.Bytes())
+		require.NoError(t, err)
+		return resp.(bencode.Dict)
+	}
+
+	// None of the extras are sent by default
+	resp := announce(req.ToValues())
+	for _, key := range []string{"tracker id", "external ip", "warning message"} {
+		require.NotContains(t, resp, key)
+	}
+
+	config.Tracker.TrackerID = "mika"
+	config.Tracker.ExternalIP = true
+	defer func() {
+		config.Tracker.TrackerID = ""
+		config.Tracker.ExternalIP = false
+	}()
+	v := req.ToValues()
+	v.Set("trackerid", "mika")
+	resp = announce(v)
+	require.Equal(t, "mika", resp["tracker id"])
+	// The address the request came from, not the client supplied ip
+	require.Equal(t, string(net.ParseIP("50.50.50.50").To4()), resp["external ip"])
+
+	// Disabled torrents fail unless configured to send the reason as a warning
+	tor.IsEnabled = false
+	tor.Reason = "Trumped by a newer torrent"
+	defer func() { tor.IsEnabled = true }()
+	w := performRequest(rh, "GET", fmt.Sprintf("/announce/%s?%s", req.PK, req.ToValues().Encode()), nil, nil)
+	require.EqualValues(t, msgInvalidInfoHash, errCode(w.Code))
+	config.Tracker.DisabledTorrentWarning = true
+	defer func() { config.Tracker.DisabledTorrentWarning = false }()
+	resp = announce(req.ToValues())
+	require.Equal(t, tor.Reason, resp["warning message"])
+	require.Equal(t, "", resp["peers"])
+
+	// Stopping before the hnr threshold after downloading
+	config.Tracker.HNRWarning = true
+	defer func() { config.Tracker.HNRWarning = false }()
+	tor.IsEnabled = true
+	v = req.ToValues()
+	v.Set("downloaded", "5000")
+	v.Set("event", string(consts.STOPPED))
+	resp = announce(v)
+	require.Equal(t, fmt.Sprintf("Stopped before the hit and run threshold (%s)", config.Tracker.HNRThreshold),
+		resp["warning message"])
+}
+
+func TestAnnounceResponse(t *testing.T) {
+	tor := store.GenerateTestTorrent()
+	tor.Seeders = 3
+	tor.Leechers = 2
+	config.Tracker.TrackerID = "tracker-01"
+	config.Tracker.ExternalIP = true
+	defer func() {
+		config.Tracker.TrackerID = ""
+		config.Tracker.ExternalIP = false
+	}()
+	for _, ip := range []net.IP{net.ParseIP("12.34.56.78"), net.ParseIP("2600::1")} {
+		dict := announceResponse(&tor, &announceRequest{RemoteIP: ip})
+		addWarning(dict, "first")
+		addWarning(dict, "second")
+		var buf bytes.Buffer
+		require.NoError(t, bencode.NewEncoder(&buf).Encode(dict))
+		decoded, err := bencode.Unmarshal(buf.Bytes())
+		require.NoError(t, err)
+		d := decoded.(bencode.Dict)
+		require.EqualValues(t, 3, d["complete"])
+		require.EqualValues(t, 2, d["incomplete"])
+		require.Equal(t, "tracker-01", d["tracker id"])
+		require.Equal(t, "first, second", d["warning message"])
+		external := net.IP(d["external ip"].(string))
+		require.True(t, ip.Equal(external))
+		require.Equal(t, ip.To4() == nil, len(external) == net.IPv6len)
+	}
+}
+
+func TestAnnounceNumWant(t *testing.T) {
+	rh := NewBitTorrentHandler()
+	tor := store.GenerateTestTorrent()
+	require.NoError(t, TorrentAdd(&tor))
+	for i := 0; i < 5; i++ {
+		p := store.GenerateTestPeer()
+		p.IP = net.ParseIP(fmt.Sprintf("12.34.56.%d", i+1))
+		tor.Peers.Add(p)
+	}
+	role := store.GenerateTestRole()
+	role.Priority = 50
+	role.MaxPeers = 2
+	require.NoError(t, RoleAdd(&role))
+	limited := store.GenerateTestUser()
+	limited.RoleID = role.RoleID
+	require.NoError(t, UserAdd(&limited))
+
+	// Peers are bound to the user which announced them, so each user needs its own peer_id
+	limitedPID := store.GenerateTestPeer().PeerID
+	copy(limitedPID[0:8], testLeechers[0].PeerID[0:8])
+	peerIDs := map[string]store.PeerID{testUsers[0].Passkey: testLeechers[0].PeerID, limited.Passkey: limitedPID}
+	peerCount := func(pk string, numWant string) int {
+		req := testReq{Ih: tor.InfoHash, PID: peerIDs[pk], IP: "12.34.56.78",
+			Port: "4000", Uploaded: "0", Downloaded: "0", left: "5000", PK: pk}
+		v := req.ToValues()
+		if numWant != "" {
+			v.Set("numwant", numWant)
+		}
+		w := performRequest(rh, "GET", fmt.Sprintf("/announce/%s?%s", pk, v.Encode()), nil, nil)
+		require.EqualValues(t, msgOk, errCode(w.Code))
+		resp, err := bencode.Unmarshal(w.Body.Bytes())
+		require.NoError(t, err)
+		return len(resp.(bencode.Dict)["peers"].(string)) / 6
+	}
+	// The requesting peer is in the swarm after the first announce but never counted
+	require.Equal(t, 5, peerCount(testUsers[0].Passkey, ""))
+	require.Equal(t, 5, peerCount(testUsers[0].Passkey, "5"))
+	require.Equal(t, 3, peerCount(testUsers[0].Passkey, "3"))
+	require.Equal(t, 0, peerCount(testUsers[0].Passkey, "0"))
+	require.Equal(t, 0, peerCount(testUsers[0].Passkey, "-1"))
+
+	oldMax := config.Tracker.MaxPeers
+	config.Tracker.MaxPeers = 4
+	defer func() { config.Tracker.MaxPeers = oldMax }()
+	require.Equal(t, 4, peerCount(testUsers[0].Passkey, "50"))
+
+	// Role limits replace the tracker max_peers
+	require.Equal(t, 2, peerCount(limited.Passkey, "50"))
+	require.Equal(t, 1, peerCount(limited.Passkey, "1"))
+}
+
+func TestAnnouncePeerKey(t *testing.T) {
+	rh := NewBitTorrentHandler()
+	tor := store.GenerateTestTorrent()
+	require.NoError(t, TorrentAdd(&tor))
+	// Keep the whitelisted client prefix of a known peer_id
+	pid := store.GenerateTestPeer().PeerID
+	copy(pid[0:8], testLeechers[0].PeerID[0:8])
+
+	announceAs := func(pk string, key string, ip string, event consts.AnnounceType) errCode {
+		req := testReq{Ih: tor.InfoHash, PID: pid, IP: ip, Port: "4000", Uploaded: "0",
+			Downloaded: "0", left: "5000", PK: pk, event: string(event)}
+		v := req.ToValues()
+		if key != "" {
+			v.Set("key", key)
+		}
+		w := performRequest(rh, "GET", fmt.Sprintf("/announce/%s?%s", req.PK, v.Encode()), nil, nil)
+		return errCode(w.Code)
+	}
+	announce := func(key string, ip string, event consts.AnnounceType) errCode {
+		return announceAs(testUsers[0].Passkey, key, ip, event)
+	}
+	require.Equal(t, msgOk, announce("keyA", "12.34.56.78", consts.STARTED))
+
+	// Another user replaying the peer_id and key is rejected
+	require.Equal(t, msgInvalidPeerKey, announceAs(testUsers[1].Passkey, "keyA", "98.76.54.32", consts.ANNOUNCE))
+	require.Equal(t, msgInvalidPeerKey, announceAs(testUsers[1].Passkey, "keyA", "12.34.56.78", consts.STOPPED))
+
+	// Replaying the peer_id with a different, or no, key is rejected and the peer is untouched
+	require.Equal(t, msgInvalidPeerKey, announce("keyB", "98.76.54.32", consts.ANNOUNCE))
+	require.Equal(t, msgInvalidPeerKey, announce("", "98.76.54.32", consts.ANNOUNCE))
+	require.Equal(t, msgInvalidPeerKey, announce("keyB", "98.76.54.32", consts.STOPPED))
+	peer, err := tor.Peers.Get(pid)
+	require.NoError(t, err)
+	require.Equal(t, "12.34.56.78", peer.IP.String())
+
+	// The original key may change the address of the peer
+	require.Equal(t, msgOk, announce("keyA", "98.76.54.32", consts.ANNOUNCE))
+	require.Equal(t, "98.76.54.32", peer.IP.String())
+	require.Equal(t, msgOk, announce("keyA", "98.76.54.32", consts.STOPPED))
+	_, err = tor.Peers.Get(pid)
+	require.Error(t, err)
+
+	// Peers which joined without a key can not have a key attached later and keep their address
+	require.Equal(t, msgOk, announce("", "12.34.56.78", consts.STARTED))
+	require.Equal(t, msgInvalidPeerKey, announce("keyB", "98.76.54.32", consts.ANNOUNCE))
+	require.Equal(t, msgInvalidPeerKey, announce("keyB", "98.76.54.32", consts.STOPPED))
+	require.Equal(t, msgInvalidPeerKey, announce("keyB", "12.34.56.78", consts.STARTED))
+	peer, err = tor.Peers.Get(pid)
+	require.NoError(t, err)
+	require.Equal(t, "12.34.56.78", peer.IP.String())
+	require.Empty(t, peer.Key)
+	require.Equal(t, msgOk, announce("", "98.76.54.32", consts.ANNOUNCE))
+	require.Equal(t, "12.34.56.78", peer.IP.String())
+	require.Equal(t, msgInvalidPeerKey, announceAs(testUsers[1].Passkey, "", "12.34.56.78", consts.STOPPED))
+	_, err = tor.Peers.Get(pid)
+	require.NoError(t, err)
+}
+
+func TestAnnounceClientSpoofed(t *testing.T) {
+	rh := NewBitTorrentHandler()
+	tor := store.GenerateTestTorrent()
+	require.NoError(t, TorrentAdd(&tor))
+	pid := store.PeerIDFromString("-qB4330-u-rGseINmloG")
+	config.Tracker.ClientSpoofCheck = true
+	defer func() { config.Tracker.ClientSpoofCheck = false }()
+
+	announce := func(userAgent string) errCode {
+		req := testReq{Ih: tor.InfoHash, PID: pid, IP: "12.34.56.78", Port: "4000", Uploaded: "0",
+			Downloaded: "0", left: "5000", PK: testUsers[0].Passkey}
+		r := httptest.NewRequest("GET", fmt.Sprintf("/announce/%s?%s", req.PK, req.ToValues().Encode()), nil)
+		r.RemoteAddr = "50.50.50.50:9000"
+		r.Header.Set("User-Agent", userAgent)
+		w := httptest.NewRecorder()
+		rh.ServeHTTP(w, r)
+		return errCode(w.Code)
+	}
+	spoofed := atomic.LoadInt64(&metrics.AnnounceClientSpoofed)
+	sub := Subscribe(EventFilter{InfoHash: tor.InfoHash, Types: []EventType{EventCheat}})
+	defer Unsubscribe(sub)
+	require.Equal(t, msgOk, announce("qBittorrent/4.3.3"))
+	peer, err := tor.Peers.Get(pid)
+	require.NoError(t, err)
+	require.False(t, peer.Spoofed)
+	require.Equal(t, "qBittorrent 4.3.3.0", peer.Client)
+	require.Equal(t, "qB", peer.ClientCode)
+
+	// Mismatches are flagged
+	require.Equal(t, msgOk, announce("Transmission/3.00"))
+	require.True(t, peer.Spoofed)
+	require.Equal(t, spoofed+1, atomic.LoadInt64(&metrics.AnnounceClientSpoofed))
+	require.Len(t, sub.C, 1)
+	require.Equal(t, pid, (<-sub.C).PeerID)
+
+	// Or rejected
+	config.Tracker.ClientSpoofReject = true
+	defer func() { config.Tracker.ClientSpoofReject = false }()
+	require.Equal(t, msgClientSpoofed, announce("Transmission/3.00"))
+	require.Equal(t, msgOk, announce("qBittorrent/4.3.3"))
+	require.False(t, peer.Spoofed)
+}
@@ -0,0 +1,139 @@
+package tracker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"github.com/leighmacdonald/mika/config"
+	"github.com/leighmacdonald/mika/consts"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/leighmacdonald/mika/util"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"sync"
+)
+
+var (
+	// auditFile is the open audit JSONL file, opened on the first write
+	auditFile     *os.File
+	auditFilePath string
+	// auditLastID is the last audit_id written to the file, used to number entries when the
+	// store is disabled
+	auditLastID uint64
+	auditMu     *sync.Mutex
+)
+
+func init() {
+	auditMu = &sync.Mutex{}
+}
+
+// readAuditFile reads the entries of an audit file, oldest first. Lines which cannot be decoded,
+// such as a partial line from a crash during a write, are skipped.
+func readAuditFile(path string) ([]*store.AuditEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "Failed to open audit file")
+	}
+	defer func() { _ = f.Close() }()
+	var entries []*store.AuditEntry
+	r := bufio.NewReader(f)
+	for lineNum := 1; ; lineNum++ {
+		line, errRead := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var e store.AuditEntry
+			if err := json.Unmarshal(line, &e); err != nil {
+				log.Warnf("Skipping invalid audit entry on line %d of %s", lineNum, path)
+			} else {
+				entries = append(entries, &e)
+			}
+		}
+		if errRead == io.EOF {
+			break
+		}
+		if errRead != nil {
+			return nil, errors.Wrap(errRead, "Failed to read audit file")
+		}
+	}
+	return entries, nil
+}
+
+// appendAuditFile writes the entry as a single line of the audit file, assigning the next
+// audit_id when it was not already assigned by the store
+func appendAuditFile(path string, e *store.AuditEntry) error {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	if auditFile == nil || auditFilePath != path {
+		if auditFile != nil {
+			_ = auditFile.Close()
+		}
+		entries, err := readAuditFile(path)
+		if err != nil {
+			return err
+		}
+		auditLastID = 0
+		if len(entries) > 0 {
+			auditLastID = entries[len(entries)-1].AuditID
+		}
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return errors.Wrap(err, "Failed to open audit file")
+		}
+		auditFile = f
+		auditFilePath = path
+	}
+	if e.AuditID == 0 {
+		e.AuditID = auditLastID + 1
+	}
+	auditLastID = e.AuditID
+	b, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "Failed to encode audit entry")
+	}
+	if _, err := auditFile.Write(append(b, '\n')); err != nil {
+		return errors.Wrap(err, "Failed to write audit entry")
+	}
+	return nil
+}
+
+// Audit records an administrative change in the store and the audit file, as configured
+func Audit(e *store.AuditEntry) error {
+	if !config.Audit.Store && config.Audit.File == "" {
+		return nil
+	}
+	if e.CreatedOn.IsZero() {
+		e.CreatedOn = util.Now()
+	}
+	if config.Audit.Store {
+		if err := db.AuditAdd(e); err != nil {
+			return err
+		}
+	}
+	if config.Audit.File != "" {
+		return appendAuditFile(config.Audit.File, e)
+	}
+	return nil
+}
+
+// AuditList returns the recorded changes matching the filter, newest first. Entries are read
+// from the store when enabled, otherwise from the audit file.
+func AuditList(f store.AuditFilter) ([]*store.AuditEntry, error) {
+	switch {
+	case config.Audit.Store:
+		return db.AuditList(f)
+	case config.Audit.File != "":
+		auditMu.Lock()
+		entries, err := readAuditFile(config.Audit.File)
+		auditMu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		return store.FilterAudit(entries, f), nil
+	default:
+		return nil, consts.ErrAuditDisabled
+	}
+}
@@ -0,0 +1,66 @@
+package tracker
+
+import (
+	"encoding/json"
+	"github.com/leighmacdonald/mika/config"
+	"github.com/leighmacdonald/mika/consts"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mika-audit")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	orig := config.Audit
+	defer func() {
+		config.Audit = orig
+		if auditFile != nil {
+			_ = auditFile.Close()
+			auditFile = nil
+		}
+	}()
+
+	config.Audit = config.AuditConfig{Store: false, File: ""}
+	require.NoError(t, Audit(&store.AuditEntry{Actor: "test", Action: "user.add"}))
+	_, err = AuditList(store.AuditFilter{})
+	require.True(t, errors.Is(err, consts.ErrAuditDisabled))
+
+	path := filepath.Join(dir, "audit.jsonl")
+	config.Audit.File = path
+	require.NoError(t, Audit(&store.AuditEntry{Actor: "test", Action: "user.add", Target: "user:1",
+		After: json.RawMessage(`{"user_id":1}`)}))
+	require.NoError(t, Audit(&store.AuditEntry{Actor: "frontend", Action: "user.delete", Target: "user:1",
+		Before: json.RawMessage(`{"user_id":1}`)}))
+	// A partial line from an interrupted write is skipped
+	require.NoError(t, auditFile.Close())
+	auditFile = nil
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"audit_id":3,"act`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	entries, err := AuditList(store.AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "user.delete", entries[0].Action)
+	require.EqualValues(t, 2, entries[0].AuditID)
+	require.False(t, entries[0].CreatedOn.IsZero())
+	require.JSONEq(t, `{"user_id":1}`, string(entries[0].Before))
+
+	entries, err = AuditList(store.AuditFilter{Actor: "test"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "user.add", entries[0].Action)
+
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 2, strings.Count(string(b), "\n"))
+}
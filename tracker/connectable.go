@@ -0,0 +1,201 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"github.com/leighmacdonald/mika/config"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/leighmacdonald/mika/util"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// handshakeHeader is the protocol name length prefix and protocol name which starts
+	// every bittorrent handshake
+	handshakeHeader = []byte("\x13BitTorrent protocol")
+	// connectablePeerID is the peer_id the tracker uses when performing handshakes
+	connectablePeerID = store.PeerIDFromString("-MK0001-connectcheck")
+)
+
+// connectableJob is a queued check of a peers ip:port
+type connectableJob struct {
+	addr     string
+	infoHash store.InfoHash
+	peer     *store.Peer
+}
+
+// connectableResult is a cached check result for an ip:port
+type connectableResult struct {
+	state     uint32
+	checkedOn time.Time
+}
+
+// connectChecker performs connectable checks asynchronously using a bounded set of workers,
+// caching the results for each ip:port
+type connectChecker struct {
+	queue    chan connectableJob
+	results  map[string]connectableResult
+	inFlight map[string]struct{}
+	mu       *sync.Mutex
+	// allowPrivate permits probing private and loopback addresses, only used by tests
+	allowPrivate bool
+}
+
+func newConnectChecker(queueSize int) *connectChecker {
+	return &connectChecker{
+		queue:    make(chan connectableJob, queueSize),
+		results:  make(map[string]connectableResult),
+		inFlight: make(map[string]struct{}),
+		mu:       &sync.Mutex{},
+	}
+}
+
+// check sets the connectable state of the peer from the cache, or queues the peer to be
+// checked when there is no valid cached result. The queue is never waited on, if it is full
+// the peer is left unchecked until it is seen again.
+func (cc *connectChecker) check(infoHash store.InfoHash, peer *store.Peer) {
+	// Peers choose their own address with allow_client_ip, never connect to internal hosts
+	if !cc.allowPrivate && (peer.IP.IsUnspecified() || peer.IP.IsMulticast() || util.IsPrivateIP(peer.IP)) {
+		return
+	}
+	addr := net.JoinHostPort(peer.IP.String(), strconv.Itoa(int(peer.Port)))
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if result, found := cc.results[addr]; found {
+		if time.Since(result.checkedOn) < config.Tracker.ConnectableTTLParsed {
+			atomic.StoreUint32(&peer.Connectable, result.state)
+			return
+		}
+		delete(cc.results, addr)
+	}
+	if _, found := cc.inFlight[addr]; found {
+		return
+	}
+	select {
+	case cc.queue <- connectableJob{addr: addr, infoHash: infoHash, peer: peer}:
+		cc.inFlight[addr] = struct{}{}
+	default:
+		log.Debugf("Connectable queue full, skipping check of %s", addr)
+	}
+}
+
+// work processes queued checks until the context is closed
+func (cc *connectChecker) work(ctx context.Context) {
+	for {
+		select {
+		case job := <-cc.queue:
+			state := store.ConnectableNo
+			if probeConnectable(job.addr, job.infoHash, config.Tracker.ConnectableTimeoutParsed) {
+				state = store.ConnectableYes
+			}
+			atomic.StoreUint32(&job.peer.Connectable, state)
+			cc.mu.Lock()
+			cc.results[job.addr] = connectableResult{state: state, checkedOn: time.Now()}
+			delete(cc.inFlight, job.addr)
+			cc.mu.Unlock()
+			log.Debugf("Connectable check of %s: %v", job.addr, state == store.ConnectableYes)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sweep removes the cached results which have expired
+func (cc *connectChecker) sweep(ttl time.Duration) int {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	removed := 0
+	for addr, result := range cc.results {
+		if time.Since(result.checkedOn) >= ttl {
+			delete(cc.results, addr)
+			removed++
+		}
+	}
+	return removed
+}
+
+// sweepExpired periodically removes the expired results until the context is closed
+func (cc *connectChecker) sweepExpired(ctx context.Context, ttl time.Duration) {
+	interval := ttl
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if removed := cc.sweep(ttl); removed > 0 {
+				log.Debugf("Removed %d expired connectable results", removed)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// run starts the workers, blocking until the context is closed
+func (cc *connectChecker) run(ctx context.Context, workers int) {
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		cc.sweepExpired(ctx, config.Tracker.ConnectableTTLParsed)
+	}()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cc.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// ConnectableWorker runs the connectable check workers until the context is closed
+func ConnectableWorker(ctx context.Context) {
+	workers := config.Tracker.ConnectableWorkers
+	if workers <= 0 {
+		workers = 1
+	}
+	connChecker.run(ctx, workers)
+}
+
+// probeConnectable attempts a bittorrent handshake with the address, returning true if
+// the peer replies with a handshake of its own
+func probeConnectable(addr string, infoHash store.InfoHash, timeout time.Duration) bool {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = conn.Close() }()
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return false
+	}
+	handshake := make([]byte, 0, len(handshakeHeader)+48)
+	handshake = append(handshake, handshakeHeader...)
+	handshake = append(handshake, make([]byte, 8)...) // reserved
+	handshake = append(handshake, infoHash.Bytes()...)
+	handshake = append(handshake, connectablePeerID.Bytes()...)
+	if _, err := conn.Write(handshake); err != nil {
+		return false
+	}
+	resp := make([]byte, len(handshakeHeader))
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return false
+	}
+	return bytes.Equal(resp, handshakeHeader)
+}
+
+// skipUnconnectable returns true for unconnectable peers unless the requesting peer is known
+// to be connectable, two peers behind NAT are unable to connect to each other.
+func skipUnconnectable(peer *store.Peer, requester *store.Peer) bool {
+	return atomic.LoadUint32(&peer.Connectable) == store.ConnectableNo &&
+		atomic.LoadUint32(&requester.Connectable) != store.ConnectableYes
+}
@@ -0,0 +1,117 @@
+package tracker
+
+import (
+	"context"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/leighmacdonald/mika/util"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestPeerListener starts a local listener which replies to handshakes when respond is true,
+// otherwise closing the connection immediately
+func newTestPeerListener(t *testing.T, respond bool) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				defer func() { _ = c.Close() }()
+				if !respond {
+					return
+				}
+				req := make([]byte, len(handshakeHeader)+48)
+				if _, err := io.ReadFull(c, req); err != nil {
+					return
+				}
+				_, _ = c.Write(req)
+			}(conn)
+		}
+	}()
+	return l
+}
+
+func testPeerAt(t *testing.T, addr string) *store.Peer {
+	host, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	p := store.GenerateTestPeer()
+	p.IP = net.ParseIP(host)
+	p.Port = util.StringToUInt16(port, 0)
+	return p
+}
+
+func TestProbeConnectable(t *testing.T) {
+	ih := store.GenerateTestTorrent().InfoHash
+	good := newTestPeerListener(t, true)
+	defer func() { _ = good.Close() }()
+	require.True(t, probeConnectable(good.Addr().String(), ih, time.Second))
+
+	bad := newTestPeerListener(t, false)
+	defer func() { _ = bad.Close() }()
+	require.False(t, probeConnectable(bad.Addr().String(), ih, time.Second))
+
+	closed := newTestPeerListener(t, false)
+	addr := closed.Addr().String()
+	require.NoError(t, closed.Close())
+	require.False(t, probeConnectable(addr, ih, time.Second))
+}
+
+func TestConnectChecker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cc := newConnectChecker(10)
+	// The test peers listen on loopback
+	cc.allowPrivate = true
+	go cc.run(ctx, 2)
+	ih := store.GenerateTestTorrent().InfoHash
+
+	good := newTestPeerListener(t, true)
+	defer func() { _ = good.Close() }()
+	bad := newTestPeerListener(t, false)
+	defer func() { _ = bad.Close() }()
+
+	goodPeer := testPeerAt(t, good.Addr().String())
+	badPeer := testPeerAt(t, bad.Addr().String())
+	cc.check(ih, goodPeer)
+	cc.check(ih, badPeer)
+	require.Eventually(t, func() bool {
+		return atomic.LoadUint32(&goodPeer.Connectable) == store.ConnectableYes &&
+			atomic.LoadUint32(&badPeer.Connectable) == store.ConnectableNo
+	}, time.Second*5, time.Millisecond*10)
+
+	// Results are cached per ip:port, even if the peer is no longer listening
+	require.NoError(t, good.Close())
+	samePeer := testPeerAt(t, good.Addr().String())
+	cc.check(ih, samePeer)
+	require.Equal(t, store.ConnectableYes, atomic.LoadUint32(&samePeer.Connectable))
+	require.Equal(t, 0, cc.sweep(time.Hour))
+	require.Equal(t, 2, cc.sweep(0))
+
+	// Private and loopback addresses are never probed
+	cc.allowPrivate = false
+	for _, addr := range []string{"127.0.0.1:4000", "10.0.0.1:4000", "[::1]:4000", "0.0.0.0:4000"} {
+		cc.check(ih, testPeerAt(t, addr))
+	}
+	cc.mu.Lock()
+	require.Empty(t, cc.inFlight)
+	cc.mu.Unlock()
+
+	// Only connectable peers receive unconnectable peers
+	unknownPeer := store.GenerateTestPeer()
+	for _, p := range []*store.Peer{goodPeer, unknownPeer} {
+		require.False(t, skipUnconnectable(p, goodPeer))
+		require.False(t, skipUnconnectable(p, badPeer))
+		require.False(t, skipUnconnectable(p, unknownPeer))
+	}
+	require.False(t, skipUnconnectable(badPeer, goodPeer))
+	require.True(t, skipUnconnectable(badPeer, badPeer))
+	require.True(t, skipUnconnectable(badPeer, unknownPeer))
+}
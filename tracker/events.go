@@ -0,0 +1,179 @@
+package tracker
+
+import (
+	"github.com/leighmacdonald/mika/metrics"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/pkg/errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EventType identifies the kind of event published on the event bus
+type EventType int
+
+const (
+	// EventPeerStarted is published when a peer joins a swarm
+	EventPeerStarted EventType = iota + 1
+	// EventPeerStopped is published when a peer leaves a swarm
+	EventPeerStopped
+	// EventPeerCompleted is published when a peer finishes downloading and becomes a seeder
+	EventPeerCompleted
+	// EventTorrentAdded is published when a torrent is registered with the tracker
+	EventTorrentAdded
+	// EventTorrentDeleted is published when a torrent is deleted
+	EventTorrentDeleted
+	// EventUserAdded is published when a user is added
+	EventUserAdded
+	// EventUserUpdated is published when a user is saved
+	EventUserUpdated
+	// EventUserDeleted is published when a user is deleted
+	EventUserDeleted
+	// EventCheat is published when a peer is flagged for suspected cheating, the Reason
+	// describes what was detected
+	EventCheat
+	// EventTorrentAutoRegistered is published when an unknown torrent is registered by an
+	// announce with auto_register enabled. EventTorrentAdded is also published for these.
+	EventTorrentAutoRegistered
+	// EventHitAndRun is published when a peer stops before the hnr_threshold has passed
+	EventHitAndRun
+)
+
+var eventTypeNames = map[EventType]string{
+	EventPeerStarted:           "peer_started",
+	EventPeerStopped:           "peer_stopped",
+	EventPeerCompleted:         "peer_completed",
+	EventTorrentAdded:          "torrent_added",
+	EventTorrentDeleted:        "torrent_deleted",
+	EventUserAdded:             "user_added",
+	EventUserUpdated:           "user_updated",
+	EventUserDeleted:           "user_deleted",
+	EventCheat:                 "cheat",
+	EventTorrentAutoRegistered: "torrent_auto_registered",
+	EventHitAndRun:             "hit_and_run",
+}
+
+// String returns the name of the event type, eg: peer_completed
+func (t EventType) String() string {
+	name, found := eventTypeNames[t]
+	if !found {
+		return "unknown"
+	}
+	return name
+}
+
+// ParseEventType returns the EventType matching the name returned by EventType.String()
+func ParseEventType(name string) (EventType, error) {
+	for t, n := range eventTypeNames {
+		if n == name {
+			return t, nil
+		}
+	}
+	return 0, errors.Errorf("unknown event type: %s", name)
+}
+
+// eventBufferSize is the number of events queued for each subscriber before new events
+// are dropped
+const eventBufferSize = 1000
+
+// Event describes a change in the state of a peer, torrent or user
+type Event struct {
+	Type     EventType
+	Time     time.Time
+	InfoHash store.InfoHash
+	PeerID   store.PeerID
+	UserID   uint32
+	Reason   string
+}
+
+// EventFilter selects the events delivered to a subscriber. Zero values match all events.
+type EventFilter struct {
+	Types    []EventType
+	UserID   uint32
+	InfoHash store.InfoHash
+}
+
+// Match returns true if the event should be delivered to subscribers using the filter
+func (f EventFilter) Match(e Event) bool {
+	if f.UserID > 0 && f.UserID != e.UserID {
+		return false
+	}
+	if f.InfoHash != (store.InfoHash{}) && f.InfoHash != e.InfoHash {
+		return false
+	}
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, t := range f.Types {
+		if t == e.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscription receives the events matching its filter on C. Publishing never blocks on a
+// subscriber, events are dropped instead when a subscriber falls too far behind.
+type Subscription struct {
+	C       <-chan Event
+	events  chan Event
+	filter  EventFilter
+	dropped uint64
+}
+
+// Dropped returns the number of events dropped since the last call
+func (s *Subscription) Dropped() uint64 {
+	return atomic.SwapUint64(&s.dropped, 0)
+}
+
+var (
+	subscribers   map[*Subscription]struct{}
+	subscribersMu *sync.RWMutex
+)
+
+func init() {
+	subscribers = make(map[*Subscription]struct{})
+	subscribersMu = &sync.RWMutex{}
+}
+
+// Subscribe registers a new subscription to the event bus. Unsubscribe must be called
+// once the subscriber is done with it.
+func Subscribe(filter EventFilter) *Subscription {
+	events := make(chan Event, eventBufferSize)
+	sub := &Subscription{C: events, events: events, filter: filter}
+	subscribersMu.Lock()
+	subscribers[sub] = struct{}{}
+	subscribersMu.Unlock()
+	return sub
+}
+
+// Unsubscribe removes the subscription from the event bus and closes its channel
+func Unsubscribe(sub *Subscription) {
+	subscribersMu.Lock()
+	if _, found := subscribers[sub]; found {
+		delete(subscribers, sub)
+		close(sub.events)
+	}
+	subscribersMu.Unlock()
+}
+
+// publish delivers the event to all subscribers with a matching filter
+func publish(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	metrics.AddEvent(e.Type.String())
+	subscribersMu.RLock()
+	defer subscribersMu.RUnlock()
+	for sub := range subscribers {
+		if !sub.filter.Match(e) {
+			continue
+		}
+		select {
+		case sub.events <- e:
+		default:
+			atomic.AddUint64(&sub.dropped, 1)
+			atomic.AddInt64(&metrics.EventsDropped, 1)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package tracker
+
+import (
+	"fmt"
+	"github.com/leighmacdonald/mika/consts"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestEventFilterMatch(t *testing.T) {
+	ih := store.GenerateTestTorrent().InfoHash
+	e := Event{Type: EventPeerStarted, InfoHash: ih, UserID: 10}
+	require.True(t, EventFilter{}.Match(e))
+	require.True(t, EventFilter{Types: []EventType{EventPeerStopped, EventPeerStarted}}.Match(e))
+	require.False(t, EventFilter{Types: []EventType{EventPeerStopped}}.Match(e))
+	require.True(t, EventFilter{UserID: 10, InfoHash: ih}.Match(e))
+	require.False(t, EventFilter{UserID: 11}.Match(e))
+	require.False(t, EventFilter{InfoHash: store.GenerateTestTorrent().InfoHash}.Match(e))
+}
+
+func TestSubscribe(t *testing.T) {
+	rh := NewBitTorrentHandler()
+	tor := store.GenerateTestTorrent()
+	usr := store.GenerateTestUser()
+	usr.RoleID = testRoles[0].RoleID
+	require.NoError(t, UserAdd(&usr))
+	sub := Subscribe(EventFilter{InfoHash: tor.InfoHash})
+	defer Unsubscribe(sub)
+	userSub := Subscribe(EventFilter{UserID: usr.UserID, Types: []EventType{EventPeerCompleted}})
+	defer Unsubscribe(userSub)
+
+	require.NoError(t, TorrentAdd(&tor))
+	for _, ev := range []consts.AnnounceType{consts.STARTED, consts.COMPLETED, consts.STOPPED} {
+		req := testReq{Ih: tor.InfoHash, PID: testLeechers[0].PeerID, IP: "12.34.56.78", event: string(ev),
+			Port: "4000", Uploaded: "0", Downloaded: "100", left: "0", PK: usr.Passkey}
+		w := performRequest(rh, "GET", fmt.Sprintf("/announce/%s?%s", req.PK, req.ToValues().Encode()), nil, nil)
+		require.EqualValues(t, msgOk, errCode(w.Code))
+	}
+	require.NoError(t, TorrentDelete(&tor))
+
+	// Stopping before the hnr_threshold is a hit and run
+	expected := []EventType{EventTorrentAdded, EventPeerStarted, EventPeerCompleted, EventHitAndRun,
+		EventPeerStopped, EventTorrentDeleted}
+	require.Len(t, sub.C, len(expected))
+	for _, et := range expected {
+		e := <-sub.C
+		require.Equal(t, et, e.Type)
+		require.Equal(t, tor.InfoHash, e.InfoHash)
+		require.False(t, e.Time.IsZero())
+	}
+	require.Len(t, userSub.C, 1)
+	e := <-userSub.C
+	require.Equal(t, EventPeerCompleted, e.Type)
+	require.Equal(t, testLeechers[0].PeerID, e.PeerID)
+	require.Equal(t, usr.UserID, e.UserID)
+}
+
+func TestParseEventType(t *testing.T) {
+	for et, name := range eventTypeNames {
+		parsed, err := ParseEventType(name)
+		require.NoError(t, err)
+		require.Equal(t, et, parsed)
+		require.Equal(t, name, et.String())
+	}
+	_, err := ParseEventType("invalid")
+	require.Error(t, err)
+	require.Equal(t, "unknown", EventType(0).String())
+}
+
+func TestSubscribeDropped(t *testing.T) {
+	sub := Subscribe(EventFilter{UserID: 999999})
+	for i := 0; i < eventBufferSize+5; i++ {
+		publish(Event{Type: EventUserUpdated, UserID: 999999})
+	}
+	require.Len(t, sub.C, eventBufferSize)
+	require.Equal(t, uint64(5), sub.Dropped())
+	require.Equal(t, uint64(0), sub.Dropped())
+	Unsubscribe(sub)
+	// Remaining buffered events can still be drained after the channel is closed
+	n := 0
+	for range sub.C {
+		n++
+	}
+	require.Equal(t, eventBufferSize, n)
+}
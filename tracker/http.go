@@ -11,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/toorop/gin-logrus"
+	"math"
 	"net"
 	"net/http"
 	"strings"
@@ -29,9 +30,14 @@ const (
 	msgInvalidPeerID        errCode = 151
 	msgInvalidNumWant       errCode = 152
 	msgBadClient            errCode = 153
+	msgTooManyInfoHashes    errCode = 154
+	msgFullScrapeDisabled   errCode = 155
+	msgInvalidPeerKey       errCode = 156
+	msgClientSpoofed        errCode = 157
 	msgOk                   errCode = 200
 	msgInfoHashNotFound     errCode = 480
 	msgInvalidAuth          errCode = 490
+	msgDownloadDisabled     errCode = 491
 	msgClientRequestTooFast errCode = 500
 	msgGenericError         errCode = 900
 	msgMalformedRequest     errCode = 901
@@ -47,10 +53,15 @@ var (
 		msgMissingPort:          errors.New("port missing from request"),
 		msgInvalidPort:          errors.New("Invalid port"),
 		msgInvalidAuth:          errors.New("Invalid passkey"),
+		msgDownloadDisabled:     errors.New("Downloading disabled for user"),
 		msgInvalidInfoHash:      errors.New("Invalid info hash"),
 		msgInvalidPeerID:        errors.New("Peer ID invalid"),
 		msgInvalidNumWant:       errors.New("num_want invalid"),
 		msgBadClient:            errors.New("Client not whitelisted"),
+		msgTooManyInfoHashes:    errors.New("Too many info hashes in scrape request"),
+		msgFullScrapeDisabled:   errors.New("Full scrape is disabled"),
+		msgInvalidPeerKey:       errors.New("Key does not match peer"),
+		msgClientSpoofed:        errors.New("Client does not match user agent"),
 		msgInfoHashNotFound:     errors.New("Unknown infohash"),
 		msgClientRequestTooFast: errors.New("Slow down there jimmy"),
 		msgMalformedRequest:     errors.New("Malformed request"),
@@ -119,25 +130,20 @@ func oops(ctx *gin.Context, errCode errCode) {
 // preFlightChecks ensures our user meets the requirements to make an authorized request
 // THis is used within the request handler itself and not as a middleware because of the
 // slightly higher cost of passing data in through the request context
-func preFlightChecks(usr *store.User, pk string, c *gin.Context) bool {
+func preFlightChecks(pk string) (*store.User, bool) {
 	// Check that the user is valid before parsing anything
 	if config.Tracker.Public {
-		usr.UserID = 1
-		return true
-	} else {
-		if pk == "" {
-			oops(c, msgInvalidAuth)
-			return false
-		}
-		foundUser, err := UserGetByPasskey(pk)
-		if err != nil {
-			log.Debugf("Got invalid passkey")
-			oops(c, msgInvalidAuth)
-			return false
-		}
-		usr = foundUser
-		return usr.Valid()
+		return &store.User{UserID: 1}, true
+	}
+	if pk == "" {
+		return nil, false
 	}
+	usr, err := UserGetByPasskey(pk)
+	if err != nil {
+		log.Debugf("Got invalid passkey")
+		return nil, false
+	}
+	return usr, usr.Valid()
 }
 
 // handleTrackerErrors is used as the default error handler for tracker requests
@@ -174,6 +180,21 @@ func responseError(message string) []byte {
 	return buf.Bytes()
 }
 
+// responseTooFast generates the bencoded error response for announces made before the minimum
+// interval has passed. The BEP31 retry in value is the number of minutes the client should wait
+// before trying again.
+func responseTooFast(retryIn time.Duration) []byte {
+	var buf bytes.Buffer
+	if err := bencode.NewEncoder(&buf).Encode(bencode.Dict{
+		"failure reason": responseStringMap[msgClientRequestTooFast].Error(),
+		"min interval":   int(config.Tracker.AnnounceIntervalMinimumParsed.Seconds()),
+		"retry in":       int(math.Ceil(retryIn.Minutes())),
+	}); err != nil {
+		log.Errorf("Failed to encode error response: %s", err)
+	}
+	return buf.Bytes()
+}
+
 // newRouter creates and returns a newly configured router instance using
 // the default middleware handlers.
 func newRouter() *gin.Engine {
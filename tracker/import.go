@@ -0,0 +1,200 @@
+package tracker
+
+import (
+	"github.com/leighmacdonald/mika/consts"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/leighmacdonald/mika/util"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ImportOptions controls how UserImport and TorrentImport treat the rows provided
+type ImportOptions struct {
+	// Upsert updates existing users (matched by passkey) and torrents (matched by infohash)
+	// instead of rejecting them as duplicates
+	Upsert bool
+	// DryRun validates every row without making any changes
+	DryRun bool
+}
+
+// ImportResult summarises an import. Errors is keyed by the index of the failed row within
+// the batch that was provided.
+type ImportResult struct {
+	Created int
+	Updated int
+	Errors  map[int]error
+}
+
+func newImportResult() ImportResult {
+	return ImportResult{Errors: make(map[int]error)}
+}
+
+// UserImport adds the batch of users to the tracker. Rows which fail validation or which
+// cannot be stored are reported individually and do not stop the remaining rows from being
+// imported. New users are added first, the existing users being updated are then written using
+// a single UserSync batch. The cached users are only changed once the batch has been written.
+func UserImport(batch []*store.User, opts ImportOptions) ImportResult {
+	var (
+		// existing holds the cached user of each of the updated rows
+		existing    []*store.User
+		updated     []*store.User
+		updatedRows []int
+	)
+	res := newImportResult()
+	seen := make(map[string]bool)
+	for i, u := range batch {
+		if u.Passkey == "" {
+			u.Passkey = util.NewPasskey()
+		}
+		if seen[u.Passkey] {
+			res.Errors[i] = errors.Wrap(consts.ErrDuplicate, "Passkey repeated within import")
+			continue
+		}
+		seen[u.Passkey] = true
+		if _, found := roleGet(u.RoleID); !found {
+			res.Errors[i] = consts.ErrInvalidRole
+			continue
+		}
+		usersMu.RLock()
+		cached, found := users[u.Passkey]
+		usersMu.RUnlock()
+		if found && !opts.Upsert {
+			res.Errors[i] = errors.Wrap(consts.ErrDuplicate, "Passkey already exists")
+			continue
+		}
+		if opts.DryRun {
+			if found {
+				res.Updated++
+			} else {
+				res.Created++
+			}
+			continue
+		}
+		if found {
+			existing = append(existing, cached)
+			updated = append(updated, &store.User{
+				UserID:          cached.UserID,
+				RoleID:          u.RoleID,
+				RemoteID:        u.RemoteID,
+				UserName:        u.UserName,
+				Passkey:         cached.Passkey,
+				IsDeleted:       cached.IsDeleted,
+				DownloadEnabled: u.DownloadEnabled,
+				Downloaded:      u.Downloaded,
+				Uploaded:        u.Uploaded,
+				Announces:       u.Announces,
+				CreatedOn:       cached.CreatedOn,
+				UpdatedOn:       util.Now(),
+			})
+			updatedRows = append(updatedRows, i)
+			continue
+		}
+		if err := UserAdd(u); err != nil {
+			res.Errors[i] = err
+			continue
+		}
+		res.Created++
+	}
+	if len(updated) > 0 {
+		if err := userSync(updated); err != nil {
+			for _, i := range updatedRows {
+				res.Errors[i] = errors.Wrap(err, "Failed to save user")
+			}
+		} else {
+			res.Updated += len(updated)
+			for i, u := range updated {
+				cached := existing[i]
+				wasEnabled := cached.DownloadEnabled
+				cached.RoleID = u.RoleID
+				cached.RemoteID = u.RemoteID
+				cached.UserName = u.UserName
+				cached.DownloadEnabled = u.DownloadEnabled
+				cached.Downloaded = u.Downloaded
+				cached.Uploaded = u.Uploaded
+				cached.Announces = u.Announces
+				cached.UpdatedOn = u.UpdatedOn
+				mapRoleToUser(cached)
+				publish(Event{Type: EventUserUpdated, UserID: cached.UserID})
+				if wasEnabled && !cached.DownloadEnabled {
+					evicted := userPeersEvict(cached.UserID, true)
+					cached.Log().WithField("peers", evicted).Debug("Evicted leechers of download disabled user")
+				}
+			}
+		}
+	}
+	log.WithFields(log.Fields{"created": res.Created, "updated": res.Updated, "failed": len(res.Errors),
+		"dry_run": opts.DryRun}).Info("User import complete")
+	return res
+}
+
+// TorrentImport adds the batch of torrents to the tracker. The backing stores only record
+// the infohash and settings when a torrent is added, so the transfer totals of the newly
+// created torrents are written afterwards using a single TorrentSync batch.
+func TorrentImport(batch []*store.Torrent, opts ImportOptions) ImportResult {
+	var created []*store.Torrent
+	res := newImportResult()
+	seen := make(map[store.InfoHash]bool)
+	for i, t := range batch {
+		if t.InfoHash == (store.InfoHash{}) && !t.InfoHashV2.IsZero() {
+			t.InfoHash = t.InfoHashV2.Truncated()
+		}
+		if t.InfoHash == (store.InfoHash{}) {
+			res.Errors[i] = consts.ErrInvalidInfoHash
+			continue
+		}
+		if seen[t.InfoHash] {
+			res.Errors[i] = errors.Wrap(consts.ErrDuplicate, "Infohash repeated within import")
+			continue
+		}
+		seen[t.InfoHash] = true
+		existing, err := TorrentGet(t.InfoHash, true)
+		found := err == nil
+		if found && !opts.Upsert {
+			res.Errors[i] = errors.Wrap(consts.ErrDuplicate, "Infohash already exists")
+			continue
+		}
+		if opts.DryRun {
+			if found {
+				res.Updated++
+			} else {
+				res.Created++
+			}
+			continue
+		}
+		if found {
+			existing.Title = t.Title
+			existing.IsEnabled = t.IsEnabled
+			existing.Reason = t.Reason
+			existing.MultiUp = t.MultiUp
+			existing.MultiDn = t.MultiDn
+			existing.Snatches = t.Snatches
+			existing.Uploaded = t.Uploaded
+			existing.Downloaded = t.Downloaded
+			existing.Announces = t.Announces
+			existing.UpdatedOn = util.Now()
+			if err := db.TorrentSave(existing); err != nil {
+				res.Errors[i] = errors.Wrap(err, "Failed to save torrent")
+				continue
+			}
+			res.Updated++
+			continue
+		}
+		// Live swarm state is never imported
+		t.Seeders = 0
+		t.Leechers = 0
+		if err := TorrentAdd(t); err != nil {
+			res.Errors[i] = err
+			continue
+		}
+		created = append(created, t)
+		res.Created++
+	}
+	if len(created) > 0 {
+		if err := torrentSync(created); err != nil {
+			log.Errorf("Failed to sync imported torrent totals: %v", err)
+		}
+	}
+	log.WithFields(log.Fields{"created": res.Created, "updated": res.Updated, "failed": len(res.Errors),
+		"dry_run": opts.DryRun}).Info("Torrent import complete")
+	return res
+}
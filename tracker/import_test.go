@@ -0,0 +1,100 @@
+package tracker
+
+import (
+	"github.com/leighmacdonald/mika/consts"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestUserImport(t *testing.T) {
+	existing := store.GenerateTestUser()
+	existing.RoleID = testRoles[0].RoleID
+	require.NoError(t, UserAdd(&existing))
+
+	newUser := store.GenerateTestUser()
+	newUser.RoleID = testRoles[0].RoleID
+	newUser.Uploaded = 5000
+	badRole := store.GenerateTestUser()
+	badRole.RoleID = 999999
+	update := existing
+	update.Uploaded = existing.Uploaded + 1000
+	batch := func() []*store.User {
+		nu, br, up := newUser, badRole, update
+		return []*store.User{&nu, &br, &up}
+	}
+
+	res := UserImport(batch(), ImportOptions{DryRun: true, Upsert: true})
+	require.Equal(t, 1, res.Created)
+	require.Equal(t, 1, res.Updated)
+	require.Len(t, res.Errors, 1)
+	require.True(t, errors.Is(res.Errors[1], consts.ErrInvalidRole))
+	_, err := UserGetByPasskey(newUser.Passkey)
+	require.Error(t, err, "dry run must not add users")
+
+	res = UserImport(batch(), ImportOptions{})
+	require.Equal(t, 1, res.Created)
+	require.Equal(t, 0, res.Updated)
+	require.True(t, errors.Is(res.Errors[2], consts.ErrDuplicate))
+	added, err := UserGetByPasskey(newUser.Passkey)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5000), added.Uploaded)
+
+	nu := newUser
+	nu.Uploaded = 6000
+	up := update
+	res = UserImport([]*store.User{&nu, &up}, ImportOptions{Upsert: true})
+	require.Equal(t, 0, res.Created)
+	require.Equal(t, 2, res.Updated)
+	require.Len(t, res.Errors, 0)
+	require.Equal(t, uint64(6000), added.Uploaded)
+	require.Equal(t, update.Uploaded, existing.Uploaded)
+}
+
+func TestUserImportSyncFailed(t *testing.T) {
+	existing := store.GenerateTestUser()
+	existing.RoleID = testRoles[0].RoleID
+	require.NoError(t, UserAdd(&existing))
+	update := existing
+	update.DownloadEnabled = false
+	update.Uploaded = existing.Uploaded + 1000
+
+	withFailingStore(t)
+	res := UserImport([]*store.User{&update}, ImportOptions{Upsert: true})
+	require.Equal(t, 0, res.Updated)
+	require.Len(t, res.Errors, 1)
+	cached, err := UserGetByPasskey(existing.Passkey)
+	require.NoError(t, err)
+	require.True(t, cached.DownloadEnabled, "unsaved changes must not be applied")
+	require.Equal(t, update.Uploaded-1000, cached.Uploaded)
+}
+
+func TestTorrentImport(t *testing.T) {
+	existing := store.GenerateTestTorrent()
+	require.NoError(t, TorrentAdd(&existing))
+
+	newTorrent := store.GenerateTestTorrent()
+	newTorrent.Snatches = 10
+	newTorrent.Seeders = 5
+	update := store.Torrent{InfoHash: existing.InfoHash, Title: "updated", IsEnabled: true, Snatches: 20}
+	dupe := newTorrent
+	batch := []*store.Torrent{&newTorrent, {}, &update, &dupe}
+
+	res := TorrentImport(batch, ImportOptions{Upsert: true})
+	require.Equal(t, 1, res.Created)
+	require.Equal(t, 1, res.Updated)
+	require.True(t, errors.Is(res.Errors[1], consts.ErrInvalidInfoHash))
+	require.True(t, errors.Is(res.Errors[3], consts.ErrDuplicate))
+
+	added, err := TorrentGet(newTorrent.InfoHash, false)
+	require.NoError(t, err)
+	require.Equal(t, uint32(10), added.Snatches)
+	require.Equal(t, uint32(0), added.Seeders)
+	require.Equal(t, "updated", existing.Title)
+	require.Equal(t, uint32(20), existing.Snatches)
+
+	again := store.Torrent{InfoHash: newTorrent.InfoHash}
+	res = TorrentImport([]*store.Torrent{&again}, ImportOptions{})
+	require.True(t, errors.Is(res.Errors[0], consts.ErrDuplicate))
+}
@@ -0,0 +1,40 @@
+package tracker
+
+import (
+	"github.com/leighmacdonald/mika/metrics"
+	"github.com/leighmacdonald/mika/store"
+)
+
+// collectMetrics fills in the cache sizes, swarm size distribution and store driver stats
+// each time the metrics are read. The swarms are counted from a snapshot so announces
+// are not blocked while the metrics are read.
+func collectMetrics(m *metrics.RuntimeMetrics) {
+	usersMu.RLock()
+	m.UsersTotalCached = int64(len(users))
+	usersMu.RUnlock()
+	snapshot := Torrents()
+	sizes := make([]float64, 0, len(snapshot))
+	for _, t := range snapshot {
+		if t.IsDeleted {
+			continue
+		}
+		n := 0
+		if t.Peers != nil {
+			t.Peers.RLock()
+			n = len(t.Peers.Peers)
+			t.Peers.RUnlock()
+		}
+		m.PeersTotalCached += int64(n)
+		sizes = append(sizes, float64(n))
+	}
+	m.TorrentsTotalCached = int64(len(sizes))
+	m.SwarmSizes = metrics.NewHistogramSnapshot(metrics.SwarmBuckets, sizes)
+
+	storeMu.RLock()
+	s := db
+	storeMu.RUnlock()
+	m.StoreDriver = s.Name()
+	if sp, ok := s.(store.StatsProvider); ok {
+		m.StoreStats = sp.Stats()
+	}
+}
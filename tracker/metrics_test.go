@@ -0,0 +1,43 @@
+package tracker
+
+import (
+	"github.com/leighmacdonald/mika/metrics"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+func TestCollectMetrics(t *testing.T) {
+	active := 0
+	for _, tor := range Torrents() {
+		if !tor.IsDeleted {
+			active++
+		}
+	}
+	m := metrics.Get()
+	require.Equal(t, "memory", m.StoreDriver)
+	require.EqualValues(t, active, m.TorrentsTotalCached)
+	require.EqualValues(t, active, m.SwarmSizes.Count)
+}
+
+func TestCollectMetricsConcurrent(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			tor := store.GenerateTestTorrent()
+			require.NoError(t, TorrentAdd(&tor))
+			usr := store.GenerateTestUser()
+			usr.RoleID = testRoles[0].RoleID
+			require.NoError(t, UserAdd(&usr))
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		metrics.Get()
+	}
+	wg.Wait()
+	m := metrics.Get()
+	require.EqualValues(t, len(Users()), m.UsersTotalCached)
+}
@@ -0,0 +1,62 @@
+package tracker
+
+import (
+	"github.com/leighmacdonald/mika/store"
+	log "github.com/sirupsen/logrus"
+)
+
+// SwarmPeers returns the peers currently participating in the swarm of the torrent
+func SwarmPeers(hash store.InfoHash) ([]*store.Peer, error) {
+	tor, err := TorrentGet(hash, true)
+	if err != nil {
+		return nil, err
+	}
+	tor.Peers.RLock()
+	defer tor.Peers.RUnlock()
+	peers := make([]*store.Peer, 0, len(tor.Peers.Peers))
+	for _, peer := range tor.Peers.Peers {
+		peers = append(peers, peer)
+	}
+	return peers, nil
+}
+
+// PeersByUser returns the active peers of a user across all swarms keyed by the
+// PeerHash, which contains the infohash of the swarm they belong to
+func PeersByUser(userID uint32) map[store.PeerHash]*store.Peer {
+	peers := make(map[store.PeerHash]*store.Peer)
+	for _, ph := range UserPeers(userID) {
+		tor, err := TorrentGet(ph.InfoHash(), true)
+		if err != nil {
+			continue
+		}
+		peer, err := tor.Peers.Get(ph.PeerID())
+		if err != nil {
+			continue
+		}
+		peers[ph] = peer
+	}
+	return peers
+}
+
+// PeerKick removes the peer from the torrents swarm, updating the seeder/leecher counts
+// it was contributing to. Nothing prevents the peer from rejoining on its next announce.
+func PeerKick(hash store.InfoHash, peerID store.PeerID) error {
+	tor, err := TorrentGet(hash, true)
+	if err != nil {
+		return err
+	}
+	peer, err := tor.Peers.Get(peerID)
+	if err != nil {
+		return err
+	}
+	removePeer(tor, peer)
+	if peer.WebRTC {
+		// Stop relaying signalling messages to the kicked peer
+		ph := store.NewPeerHash(tor.InfoHash, peerID)
+		wsPeersMu.Lock()
+		delete(wsPeers, ph)
+		wsPeersMu.Unlock()
+	}
+	log.Infof("Kicked peer %s from swarm %s", peerID.String(), tor.InfoHash.String())
+	return nil
+}
@@ -0,0 +1,54 @@
+package tracker
+
+import (
+	"fmt"
+	"github.com/leighmacdonald/mika/consts"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPeerKick(t *testing.T) {
+	rh := NewBitTorrentHandler()
+	torA := store.GenerateTestTorrent()
+	require.NoError(t, TorrentAdd(&torA))
+	torB := store.GenerateTestTorrent()
+	require.NoError(t, TorrentAdd(&torB))
+	usr := store.GenerateTestUser()
+	usr.RoleID = testRoles[0].RoleID
+	require.NoError(t, UserAdd(&usr))
+
+	for _, ih := range []store.InfoHash{torA.InfoHash, torB.InfoHash} {
+		req := testReq{Ih: ih, PID: testLeechers[0].PeerID, IP: "12.34.56.78", event: string(consts.STARTED),
+			Port: "4000", Uploaded: "0", Downloaded: "0", left: "5000", PK: usr.Passkey}
+		w := performRequest(rh, "GET", fmt.Sprintf("/announce/%s?%s", req.PK, req.ToValues().Encode()), nil, nil)
+		require.EqualValues(t, msgOk, errCode(w.Code))
+	}
+	peers, err := SwarmPeers(torA.InfoHash)
+	require.NoError(t, err)
+	require.Len(t, peers, 1)
+	require.Equal(t, usr.UserID, peers[0].UserID)
+	require.Equal(t, "qB", peers[0].ClientCode)
+	_, err = SwarmPeers(store.GenerateTestTorrent().InfoHash)
+	require.Error(t, err)
+
+	userPeers := PeersByUser(usr.UserID)
+	require.Len(t, userPeers, 2)
+	for ph, peer := range userPeers {
+		require.Equal(t, testLeechers[0].PeerID, ph.PeerID())
+		require.Equal(t, usr.UserID, peer.UserID)
+	}
+
+	require.NoError(t, PeerKick(torA.InfoHash, testLeechers[0].PeerID))
+	require.Equal(t, 0, int(torA.Leechers))
+	require.Equal(t, 1, int(torB.Leechers))
+	peers, err = SwarmPeers(torA.InfoHash)
+	require.NoError(t, err)
+	require.Len(t, peers, 0)
+	userPeers = PeersByUser(usr.UserID)
+	require.Len(t, userPeers, 1)
+	_, found := userPeers[store.NewPeerHash(torB.InfoHash, testLeechers[0].PeerID)]
+	require.True(t, found)
+
+	require.Equal(t, consts.ErrInvalidPeerID, PeerKick(torA.InfoHash, testLeechers[0].PeerID))
+}
@@ -58,6 +58,9 @@ const (
 	// libtorrent based clients (qbt/deluge) will only send supportcrypto=1 even when
 	// requirecrypto is set in the client interfaces.
 	paramRequireCrypto announceParam = "requirecrypto"
+	paramCompact       announceParam = "compact"
+	paramNoPeerID      announceParam = "no_peer_id"
+	paramTrackerID     announceParam = "trackerid"
 )
 
 type query struct {
@@ -162,6 +165,22 @@ func getUint16Key(q *query, key announceParam, def uint16) uint16 {
 	return util.UMax16(0, left)
 }
 
+// getNumWant returns the numwant value of the query, negative values are treated as 0
+func getNumWant(q *query, def uint) uint {
+	str, exists := q.Params[paramNumWant]
+	if !exists {
+		return def
+	}
+	v, err := strconv.ParseInt(str, 10, 32)
+	if err != nil {
+		return def
+	}
+	if v < 0 {
+		return 0
+	}
+	return uint(v)
+}
+
 func getUintKey(q *query, key announceParam, def uint) uint {
 	left, err := q.Uint(key)
 	if err != nil {
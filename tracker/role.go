@@ -1,12 +1,15 @@
 package tracker
 
 import (
+	"github.com/leighmacdonald/mika/consts"
 	"github.com/leighmacdonald/mika/store"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
 func RoleAll() []*store.Role {
+	rolesMu.RLock()
+	defer rolesMu.RUnlock()
 	var roleSet []*store.Role
 	for _, r := range roles {
 		roleSet = append(roleSet, r)
@@ -14,21 +17,52 @@ func RoleAll() []*store.Role {
 	return roleSet
 }
 
-func RoleDelete(roleID uint32) error {
-	// TODO check user for dangling role references
-	if err := db.RoleDelete(roleID); err != nil {
-		return errors.Wrapf(err, "Failed to delete role")
+// RoleDelete removes a role from the tracker and backing store. Deletion is refused with
+// consts.ErrRoleInUse while users still reference the role, unless a reassignTo role is
+// provided, in which case those users are moved to it. The number of users moved is returned.
+func RoleDelete(roleID uint32, reassignTo uint32) (int, error) {
+	if _, found := roleGet(roleID); !found {
+		return 0, consts.ErrInvalidRole
 	}
+	if reassignTo > 0 {
+		if _, found := roleGet(reassignTo); !found || reassignTo == roleID {
+			return 0, consts.ErrInvalidRole
+		}
+	}
+	var assigned []*store.User
+	usersMu.RLock()
+	for _, u := range users {
+		if u.RoleID == roleID {
+			assigned = append(assigned, u)
+		}
+	}
+	usersMu.RUnlock()
+	if len(assigned) > 0 && reassignTo == 0 {
+		return 0, errors.Wrapf(consts.ErrRoleInUse, "Role has %d users assigned", len(assigned))
+	}
+	moved, err := db.RoleDelete(roleID, reassignTo)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to delete role")
+	}
+	for _, u := range assigned {
+		u.RoleID = reassignTo
+		mapRoleToUser(u)
+	}
+	rolesMu.Lock()
 	delete(roles, roleID)
-	log.WithField("role_id", roleID).Debug("Role deleted successfully")
-	return nil
+	rolesMu.Unlock()
+	log.WithFields(log.Fields{"role_id": roleID, "reassign_to": reassignTo, "moved": moved}).
+		Debug("Role deleted successfully")
+	return moved, nil
 }
 
 func RoleAdd(role *store.Role) error {
 	if err := db.RoleSave(role); err != nil {
 		return errors.Wrap(err, "Failed to save role")
 	}
+	rolesMu.Lock()
 	roles[role.RoleID] = role
+	rolesMu.Unlock()
 	role.Log().Debug("Role saved successfully")
 	return nil
 }
@@ -0,0 +1,61 @@
+package tracker
+
+import (
+	"github.com/leighmacdonald/mika/consts"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+func TestRoleDelete(t *testing.T) {
+	roleA := store.GenerateTestRole()
+	roleA.Priority = 1000
+	require.NoError(t, RoleAdd(&roleA))
+	roleB := store.GenerateTestRole()
+	roleB.Priority = 1001
+	require.NoError(t, RoleAdd(&roleB))
+	usr := store.GenerateTestUser()
+	usr.RoleID = roleA.RoleID
+	require.NoError(t, UserAdd(&usr))
+
+	_, err := RoleDelete(roleA.RoleID, 0)
+	require.True(t, errors.Is(err, consts.ErrRoleInUse))
+	_, err = RoleDelete(roleA.RoleID, roleA.RoleID)
+	require.True(t, errors.Is(err, consts.ErrInvalidRole))
+
+	moved, err := RoleDelete(roleA.RoleID, roleB.RoleID)
+	require.NoError(t, err)
+	require.Equal(t, 1, moved)
+	require.Equal(t, roleB.RoleID, usr.RoleID)
+	require.Equal(t, &roleB, usr.Role)
+	_, found := roles[roleA.RoleID]
+	require.False(t, found)
+
+	moved, err = RoleDelete(roleB.RoleID, 0)
+	require.True(t, errors.Is(err, consts.ErrRoleInUse))
+	require.Equal(t, 0, moved)
+}
+
+func TestRoleConcurrent(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			r := store.GenerateTestRole()
+			require.NoError(t, RoleAdd(&r))
+			_, err := RoleDelete(r.RoleID, 0)
+			require.NoError(t, err)
+		}
+	}()
+	usr := store.GenerateTestUser()
+	usr.RoleID = testRoles[0].RoleID
+	for i := 0; i < 20; i++ {
+		RoleAll()
+		mapRoleToUser(&usr)
+	}
+	wg.Wait()
+	require.Equal(t, testRoles[0].RoleID, usr.Role.RoleID)
+}
@@ -4,15 +4,23 @@ import (
 	"bytes"
 	"github.com/chihaya/bencode"
 	"github.com/gin-gonic/gin"
+	"github.com/leighmacdonald/mika/config"
+	"github.com/leighmacdonald/mika/metrics"
 	"github.com/leighmacdonald/mika/store"
 	log "github.com/sirupsen/logrus"
+	"net"
 	"net/http"
+	"sync/atomic"
+	"time"
 )
 
 // scrape handles the bittorrent scrape protocol for
 func scrape(c *gin.Context) {
-	var user store.User
-	if !preFlightChecks(&user, c.Param("passkey"), c) {
+	start := time.Now()
+	atomic.AddInt64(&metrics.ScrapeTotal, 1)
+	usr, valid := preFlightChecks(c.Param("passkey"))
+	if !valid {
+		oops(c, msgInvalidAuth)
 		return
 	}
 	q, err := queryStringParser(c.Request.URL.RawQuery)
@@ -21,38 +29,146 @@ func scrape(c *gin.Context) {
 		oops(c, msgMalformedRequest)
 		return
 	}
-	// Technically no info hashes means we are supposed to send data for all known db.
-	// This is something we do NOT want to do in a private tracker scenario (or really public for that matter)
-	// TODO Add a config toggle for this?
-	// TODO Its not technically malformed, should we return a empty file set instead?
-	if len(q.InfoHashes) == 0 {
-		log.Errorf("No infohash supplied")
+	ip, _, err := getIP(nil, false, c)
+	if err != nil {
+		log.Errorf("Failed to parse client ip: %s", c.Request.RemoteAddr)
 		oops(c, msgMalformedRequest)
 		return
 	}
-	// Todo limit scrape to N db
-	resp := make(bencode.Dict, len(q.InfoHashes))
+	infoHashes := make([]store.InfoHash, 0, len(q.InfoHashes))
 	var ih store.InfoHash
 	for _, ihStr := range q.InfoHashes {
 		if err := store.InfoHashFromString(&ih, ihStr); err != nil {
 			log.Errorf("Failed to decode info hash in scrape: %s", ihStr)
 			continue
 		}
-		torrent, err2 := TorrentGet(ih, false)
-		if err2 != nil {
-			log.Debugf("Scrape request for invalid torrent: %s", ih)
-			continue
-		}
-		resp[ih.String()] = bencode.Dict{
-			"complete":   torrent.Seeders,
-			"downloaded": torrent.Snatches,
-			"incomplete": torrent.Leechers,
+		infoHashes = append(infoHashes, ih)
+	}
+	if len(q.InfoHashes) > 0 && len(infoHashes) == 0 {
+		oops(c, msgInvalidInfoHash)
+		return
+	}
+	results, code := scrapeTorrents(usr, ip, infoHashes)
+	if code != msgOk {
+		oops(c, code)
+		return
+	}
+	files := make(bencode.Dict, len(results))
+	for infoHash, torrent := range results {
+		files[string(infoHash.Bytes())] = bencode.Dict{
+			"complete":   atomic.LoadUint32(&torrent.Seeders),
+			"downloaded": atomic.LoadUint32(&torrent.Snatches),
+			"incomplete": atomic.LoadUint32(&torrent.Leechers),
 		}
 	}
 	var buf bytes.Buffer
-	if err := bencode.NewEncoder(&buf).Encode(resp); err != nil {
+	if err := bencode.NewEncoder(&buf).Encode(bencode.Dict{
+		"files": files,
+		// BEP48 flags
+		"flags": bencode.Dict{
+			"min_request_interval": int(config.Tracker.ScrapeIntervalParsed.Seconds()),
+		},
+	}); err != nil {
 		log.Errorf("Failed to encode scrape response")
 		return
 	}
 	c.Data(http.StatusOK, gin.MIMEPlain, buf.Bytes())
+	metrics.ScrapeLatency.ObserveSince(start)
+}
+
+// scrapeTorrents resolves the requested info hashes into the torrents which should be included
+// in a scrape response, keyed by the info hash the client requested.
+//
+// Requesting no info hashes is a full scrape, which is only allowed when running as a public
+// tracker with full_scrape enabled. Unknown info hashes are skipped.
+func scrapeTorrents(usr *store.User, ip net.IP, infoHashes []store.InfoHash) (map[store.InfoHash]*store.Torrent, errCode) {
+	if config.Tracker.ScrapeMaxInfoHashes > 0 && len(infoHashes) > config.Tracker.ScrapeMaxInfoHashes {
+		return nil, msgTooManyInfoHashes
+	}
+	if len(infoHashes) == 0 && !(config.Tracker.Public && config.Tracker.FullScrape) {
+		return nil, msgFullScrapeDisabled
+	}
+	if !scrapeAllowed(usr, ip, infoHashes) {
+		return nil, msgClientRequestTooFast
+	}
+	if len(infoHashes) == 0 {
+		results := make(map[store.InfoHash]*store.Torrent)
+		for infoHash, torrent := range Torrents() {
+			if torrent.IsDeleted {
+				continue
+			}
+			results[infoHash] = torrent
+		}
+		return results, msgOk
+	}
+	results := make(map[store.InfoHash]*store.Torrent, len(infoHashes))
+	for _, infoHash := range infoHashes {
+		torrent, err := TorrentGet(infoHash, false)
+		if err != nil {
+			log.Debugf("Scrape request for invalid torrent: %s", infoHash)
+			continue
+		}
+		results[infoHash] = torrent
+	}
+	return results, msgOk
+}
+
+// scrapeKey identifies the client a scrape budget belongs to, the user or the ip address of the
+// client when running as a public tracker
+type scrapeKey struct {
+	userID uint32
+	ip     string
+}
+
+func newScrapeKey(usr *store.User, ip net.IP) scrapeKey {
+	if config.Tracker.Public {
+		return scrapeKey{ip: ip.String()}
+	}
+	return scrapeKey{userID: usr.UserID}
+}
+
+// scrapeBudget counts the info hashes scraped by a client since the start of the current
+// scrape_interval
+type scrapeBudget struct {
+	start time.Time
+	used  int
+}
+
+// scrapeAllowed enforces the scrape_interval, each client may scrape up to scrape_max_info_hashes
+// info hashes within each interval whether they are requested together or one at a time. A full
+// scrape uses the whole budget. When scrape_max_info_hashes is unlimited each client may make
+// a single scrape within each interval. The info hashes are only counted when the scrape is allowed.
+func scrapeAllowed(usr *store.User, ip net.IP, infoHashes []store.InfoHash) bool {
+	interval := config.Tracker.ScrapeIntervalParsed
+	if interval <= 0 {
+		return true
+	}
+	limit, cost := config.Tracker.ScrapeMaxInfoHashes, len(infoHashes)
+	if limit <= 0 {
+		limit, cost = 1, 1
+	} else if cost == 0 {
+		cost = limit
+	}
+	now := time.Now()
+	key := newScrapeKey(usr, ip)
+	scrapeBudgetsMu.Lock()
+	defer scrapeBudgetsMu.Unlock()
+	if now.Sub(scrapeBudgetsSwept) >= interval {
+		for k, b := range scrapeBudgets {
+			if now.Sub(b.start) >= interval {
+				delete(scrapeBudgets, k)
+			}
+		}
+		scrapeBudgetsSwept = now
+	}
+	b, found := scrapeBudgets[key]
+	if !found || now.Sub(b.start) >= interval {
+		b = &scrapeBudget{start: now}
+		scrapeBudgets[key] = b
+	}
+	if b.used+cost > limit {
+		return false
+	}
+	b.used += cost
+	return true
 }
@@ -3,9 +3,14 @@ package tracker
 import (
 	"fmt"
 	"github.com/chihaya/bencode"
+	"github.com/leighmacdonald/mika/config"
 	"github.com/leighmacdonald/mika/store"
 	"github.com/stretchr/testify/require"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestBitTorrentHandler_Scrape(t *testing.T) {
@@ -26,8 +31,103 @@ func TestBitTorrentHandler_Scrape(t *testing.T) {
 		v, err := bencode.NewDecoder(w.Body).Decode()
 		require.NoError(t, err, "Failed to decode scrape: (%d)", i)
 		d := v.(bencode.Dict)
-		require.Equal(t, int64(2), d[testTorrents[0].InfoHash.String()].(bencode.Dict)["complete"].(int64))
-		require.Equal(t, int64(0), d[testTorrents[0].InfoHash.String()].(bencode.Dict)["incomplete"].(int64))
-		require.Equal(t, int64(1), d[testTorrents[0].InfoHash.String()].(bencode.Dict)["downloaded"].(int64))
+		files := d["files"].(bencode.Dict)
+		stats := files[string(testTorrents[0].InfoHash.Bytes())].(bencode.Dict)
+		require.Equal(t, int64(2), stats["complete"].(int64))
+		require.Equal(t, int64(0), stats["incomplete"].(int64))
+		require.Equal(t, int64(1), stats["downloaded"].(int64))
+		require.Equal(t, int64(config.Tracker.ScrapeIntervalParsed.Seconds()),
+			d["flags"].(bencode.Dict)["min_request_interval"].(int64))
 	}
 }
+
+func TestScrapeLimits(t *testing.T) {
+	rh := NewBitTorrentHandler()
+	newUser := func() store.User {
+		usr := store.GenerateTestUser()
+		usr.RoleID = testRoles[0].RoleID
+		require.NoError(t, UserAdd(&usr))
+		return usr
+	}
+	doScrape := func(pk string, infoHashes ...store.InfoHash) *httptest.ResponseRecorder {
+		req := scrapeReq{PK: pk, InfoHashes: infoHashes}
+		if pk == "" {
+			return performRequest(rh, "GET", "/scrape", nil, nil)
+		}
+		return performRequest(rh, "GET", fmt.Sprintf("/scrape/%s?%s", pk, req.ToValues().Encode()), nil, nil)
+	}
+
+	// Too many info hashes
+	oldMax := config.Tracker.ScrapeMaxInfoHashes
+	config.Tracker.ScrapeMaxInfoHashes = 1
+	usr := newUser()
+	w := doScrape(usr.Passkey, testTorrents[0].InfoHash, store.GenerateTestTorrent().InfoHash)
+	config.Tracker.ScrapeMaxInfoHashes = oldMax
+	require.EqualValues(t, msgTooManyInfoHashes, errCode(w.Code))
+
+	// Full scrape is not available to private trackers
+	config.Tracker.FullScrape = true
+	w = doScrape(usr.Passkey)
+	config.Tracker.FullScrape = false
+	require.EqualValues(t, msgFullScrapeDisabled, errCode(w.Code))
+
+	// Rejected requests do not count towards the budget. Each user may scrape up to
+	// scrape_max_info_hashes info hashes within the scrape_interval, however they are requested
+	config.Tracker.ScrapeMaxInfoHashes = 2
+	defer func() { config.Tracker.ScrapeMaxInfoHashes = oldMax }()
+	other := store.GenerateTestTorrent()
+	require.NoError(t, TorrentAdd(&other))
+	w = doScrape(usr.Passkey, testTorrents[0].InfoHash)
+	require.EqualValues(t, msgOk, errCode(w.Code))
+	w = doScrape(usr.Passkey, other.InfoHash)
+	require.EqualValues(t, msgOk, errCode(w.Code))
+	w = doScrape(usr.Passkey, testTorrents[0].InfoHash)
+	require.EqualValues(t, msgClientRequestTooFast, errCode(w.Code))
+	w = doScrape(newUser().Passkey, testTorrents[0].InfoHash, other.InfoHash)
+	require.EqualValues(t, msgOk, errCode(w.Code))
+
+	// Expired budgets are reset and removed
+	scrapeBudgetsMu.Lock()
+	for _, b := range scrapeBudgets {
+		b.start = b.start.Add(-config.Tracker.ScrapeIntervalParsed)
+	}
+	scrapeBudgetsSwept = time.Time{}
+	scrapeBudgetsMu.Unlock()
+	w = doScrape(usr.Passkey, testTorrents[0].InfoHash, other.InfoHash)
+	require.EqualValues(t, msgOk, errCode(w.Code))
+	scrapeBudgetsMu.Lock()
+	require.Len(t, scrapeBudgets, 1)
+	scrapeBudgetsMu.Unlock()
+
+	// Without a limit on the number of info hashes a single scrape is allowed per interval
+	config.Tracker.ScrapeMaxInfoHashes = 0
+	unlimited := newUser()
+	require.True(t, scrapeAllowed(&unlimited, nil, []store.InfoHash{testTorrents[0].InfoHash, other.InfoHash}))
+	require.False(t, scrapeAllowed(&unlimited, nil, []store.InfoHash{testTorrents[0].InfoHash}))
+	config.Tracker.ScrapeMaxInfoHashes = 2
+
+	// Full scrapes are opt-in for public trackers
+	config.Tracker.Public = true
+	defer func() { config.Tracker.Public = false }()
+	w = doScrape("")
+	require.EqualValues(t, msgFullScrapeDisabled, errCode(w.Code))
+	config.Tracker.FullScrape = true
+	defer func() { config.Tracker.FullScrape = false }()
+	w = doScrape("")
+	require.EqualValues(t, http.StatusOK, w.Code)
+	// Public trackers limit each ip address, a full scrape uses the whole budget
+	require.EqualValues(t, msgClientRequestTooFast, errCode(doScrape("").Code))
+	publicUser, _ := preFlightChecks("")
+	require.True(t, scrapeAllowed(publicUser, net.ParseIP("12.34.56.78"), []store.InfoHash{other.InfoHash}))
+	v, err := bencode.NewDecoder(w.Body).Decode()
+	require.NoError(t, err)
+	files := v.(bencode.Dict)["files"].(bencode.Dict)
+	active := 0
+	for _, tor := range Torrents() {
+		if !tor.IsDeleted {
+			active++
+		}
+	}
+	require.Len(t, files, active)
+	require.Contains(t, files, string(testTorrents[0].InfoHash.Bytes()))
+}
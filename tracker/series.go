@@ -0,0 +1,251 @@
+package tracker
+
+import (
+	"github.com/leighmacdonald/mika/config"
+	"github.com/leighmacdonald/mika/consts"
+	"github.com/leighmacdonald/mika/metrics"
+	"github.com/leighmacdonald/mika/store"
+	log "github.com/sirupsen/logrus"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// torrentSeries and userSeries hold the rolling activity of each torrent and user which
+	// has announced, they are written to the store with the other stats by the StatWorker
+	torrentSeries map[store.InfoHash]*store.Series
+	userSeries    map[uint32]*store.Series
+	seriesMu      *sync.RWMutex
+)
+
+func init() {
+	torrentSeries = make(map[store.InfoHash]*store.Series)
+	userSeries = make(map[uint32]*store.Series)
+	seriesMu = &sync.RWMutex{}
+}
+
+// seriesSizes returns the configured number of buckets of each resolution
+func seriesSizes() map[store.Resolution]int {
+	return map[store.Resolution]int{
+		store.Minute: config.Tracker.SeriesMinutes,
+		store.Hour:   config.Tracker.SeriesHours,
+		store.Day:    config.Tracker.SeriesDays,
+	}
+}
+
+// seriesEnabled returns true if any resolution is being recorded
+func seriesEnabled() bool {
+	return config.Tracker.SeriesMinutes > 0 || config.Tracker.SeriesHours > 0 || config.Tracker.SeriesDays > 0
+}
+
+// loadSeries fetches the stored series, resizing them if the configured sizes have changed
+func loadSeries() {
+	stored, err := db.Series()
+	if err != nil {
+		log.Fatalf("Failed to load series: %v", err)
+	}
+	sizes := seriesSizes()
+	tSeries := make(map[store.InfoHash]*store.Series)
+	uSeries := make(map[uint32]*store.Series)
+	for _, s := range stored {
+		s.Resize(sizes)
+		switch s.Kind {
+		case store.SeriesTorrent:
+			var ih store.InfoHash
+			if err := store.InfoHashFromHex(&ih, s.Key); err != nil {
+				log.Warnf("Skipping series with invalid info_hash: %s", s.Key)
+				continue
+			}
+			tSeries[ih] = s
+		case store.SeriesUser:
+			userID, err := strconv.ParseUint(s.Key, 10, 32)
+			if err != nil {
+				log.Warnf("Skipping series with invalid user_id: %s", s.Key)
+				continue
+			}
+			uSeries[uint32(userID)] = s
+		}
+	}
+	seriesMu.Lock()
+	torrentSeries = tSeries
+	userSeries = uSeries
+	seriesMu.Unlock()
+}
+
+// getTorrentSeries returns the series of the torrent, creating it if create is true
+func getTorrentSeries(ih store.InfoHash, create bool) *store.Series {
+	seriesMu.RLock()
+	s, found := torrentSeries[ih]
+	seriesMu.RUnlock()
+	if found || !create {
+		return s
+	}
+	seriesMu.Lock()
+	defer seriesMu.Unlock()
+	if s, found = torrentSeries[ih]; !found {
+		s = store.NewSeries(store.SeriesTorrent, store.TorrentSeriesKey(ih), seriesSizes())
+		torrentSeries[ih] = s
+	}
+	return s
+}
+
+// getUserSeries returns the series of the user, creating it if create is true
+func getUserSeries(userID uint32, create bool) *store.Series {
+	seriesMu.RLock()
+	s, found := userSeries[userID]
+	seriesMu.RUnlock()
+	if found || !create {
+		return s
+	}
+	seriesMu.Lock()
+	defer seriesMu.Unlock()
+	if s, found = userSeries[userID]; !found {
+		s = store.NewSeries(store.SeriesUser, store.UserSeriesKey(userID), seriesSizes())
+		userSeries[userID] = s
+	}
+	return s
+}
+
+// torrentSeriesMerge merges the series of the v2 only torrent src into the series of the hybrid
+// torrent dst, removing the series of src
+func torrentSeriesMerge(dst store.InfoHash, src store.InfoHash) {
+	seriesMu.Lock()
+	s, found := torrentSeries[src]
+	delete(torrentSeries, src)
+	seriesMu.Unlock()
+	if !found {
+		return
+	}
+	getTorrentSeries(dst, true).Merge(s)
+	if err := db.SeriesDelete(store.SeriesTorrent, store.TorrentSeriesKey(src)); err != nil {
+		log.Errorf("Failed to remove merged series %s: %v", src, err)
+	}
+}
+
+// torrentSeriesDelete removes the series of the torrent from the tracker and the store
+func torrentSeriesDelete(ih store.InfoHash) {
+	seriesMu.Lock()
+	delete(torrentSeries, ih)
+	seriesMu.Unlock()
+	if err := db.SeriesDelete(store.SeriesTorrent, store.TorrentSeriesKey(ih)); err != nil {
+		log.Errorf("Failed to remove series of torrent %s: %v", ih, err)
+	}
+}
+
+// userSeriesDelete removes the series of the user from the tracker and the store
+func userSeriesDelete(userID uint32) {
+	seriesMu.Lock()
+	delete(userSeries, userID)
+	seriesMu.Unlock()
+	if err := db.SeriesDelete(store.SeriesUser, store.UserSeriesKey(userID)); err != nil {
+		log.Errorf("Failed to remove series of user %d: %v", userID, err)
+	}
+}
+
+// recordSeries adds a successful announce to the series of the torrent and user. uploaded and
+// downloaded are the transfer since the previous announce of the peer. Torrents record the actual
+// transfer while users record the transfer credited after multipliers.
+func recordSeries(req *announceRequest, tor *store.Torrent, user *store.User, uploaded uint64, downloaded uint64) {
+	if !seriesEnabled() {
+		return
+	}
+	now := time.Now()
+	var snatches uint64
+	if req.Event == consts.COMPLETED {
+		snatches = 1
+	}
+	getTorrentSeries(tor.InfoHash, true).Add(now, store.SeriesDelta{
+		Announces:  1,
+		Snatches:   snatches,
+		Uploaded:   uploaded,
+		Downloaded: downloaded,
+		Swarm:      true,
+		Seeders:    atomic.LoadUint32(&tor.Seeders),
+		Leechers:   atomic.LoadUint32(&tor.Leechers),
+	})
+	getUserSeries(user.UserID, true).Add(now, store.SeriesDelta{
+		Announces:  1,
+		Snatches:   snatches,
+		Uploaded:   uint64(float64(uploaded) * tor.MultiUp),
+		Downloaded: uint64(float64(downloaded) * tor.MultiDn),
+	})
+}
+
+// findDirtySeries returns the series which have changed since they were last synced
+func findDirtySeries() []*store.Series {
+	seriesMu.RLock()
+	defer seriesMu.RUnlock()
+	var dirty []*store.Series
+	for _, s := range torrentSeries {
+		if atomic.LoadUint32(&s.Writes) > 0 {
+			dirty = append(dirty, s)
+		}
+	}
+	for _, s := range userSeries {
+		if atomic.LoadUint32(&s.Writes) > 0 {
+			dirty = append(dirty, s)
+		}
+	}
+	return dirty
+}
+
+// seriesSync writes the batch to the store. Only the writes counted before the batch was written
+// are cleared so series which change during the sync are written again by the next sync.
+func seriesSync(batch []*store.Series) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	writes := make([]uint32, len(batch))
+	for i, s := range batch {
+		writes[i] = atomic.LoadUint32(&s.Writes)
+	}
+	start := time.Now()
+	err := db.SeriesSync(batch)
+	metrics.ObserveStoreSync("series", start, err)
+	if err != nil {
+		return err
+	}
+	for i, s := range batch {
+		for {
+			cur := atomic.LoadUint32(&s.Writes)
+			next := uint32(0)
+			if cur > writes[i] {
+				next = cur - writes[i]
+			}
+			if atomic.CompareAndSwapUint32(&s.Writes, cur, next) {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// TorrentSeries returns the count most recent buckets of the resolution for the torrent, oldest
+// first. Torrents without any recorded activity return empty buckets.
+func TorrentSeries(ih store.InfoHash, res store.Resolution, count int) ([]store.SeriesPoint, error) {
+	tor, err := TorrentGet(ih, false)
+	if err != nil {
+		return nil, err
+	}
+	s := getTorrentSeries(tor.InfoHash, false)
+	if s == nil {
+		s = store.NewSeries(store.SeriesTorrent, store.TorrentSeriesKey(tor.InfoHash), seriesSizes())
+	}
+	return s.Range(res, time.Now(), count)
+}
+
+// UserSeries returns the count most recent buckets of the resolution for the user, oldest
+// first. Users without any recorded activity return empty buckets.
+func UserSeries(userID uint32, res store.Resolution, count int) ([]store.SeriesPoint, error) {
+	usr, err := UserGetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	s := getUserSeries(usr.UserID, false)
+	if s == nil {
+		s = store.NewSeries(store.SeriesUser, store.UserSeriesKey(usr.UserID), seriesSizes())
+	}
+	return s.Range(res, time.Now(), count)
+}
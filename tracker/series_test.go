@@ -0,0 +1,118 @@
+package tracker
+
+import (
+	"fmt"
+	"github.com/leighmacdonald/mika/consts"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func sumSeries(points []store.SeriesPoint) (announces uint64, uploaded uint64) {
+	for _, p := range points {
+		announces += p.Announces
+		uploaded += p.Uploaded
+	}
+	return
+}
+
+func TestSeries(t *testing.T) {
+	rh := NewBitTorrentHandler()
+	tor := store.GenerateTestTorrent()
+	tor.MultiUp = 2
+	require.NoError(t, TorrentAdd(&tor))
+	points, err := TorrentSeries(tor.InfoHash, store.Hour, 3)
+	require.NoError(t, err)
+	require.Len(t, points, 3)
+	announces, _ := sumSeries(points)
+	require.EqualValues(t, 0, announces)
+
+	userPoints, err := UserSeries(testUsers[0].UserID, store.Hour, 2)
+	require.NoError(t, err)
+	_, userUploaded := sumSeries(userPoints)
+
+	req := testReq{Ih: tor.InfoHash, PID: testLeechers[0].PeerID, IP: "12.34.56.78", Port: "4000",
+		Uploaded: "1000", Downloaded: "500", left: "5000", PK: testUsers[0].Passkey, event: string(consts.STARTED)}
+	w := performRequest(rh, "GET", fmt.Sprintf("/announce/%s?%s", req.PK, req.ToValues().Encode()), nil, nil)
+	require.EqualValues(t, msgOk, errCode(w.Code))
+
+	points, err = TorrentSeries(tor.InfoHash, store.Minute, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, points[0].Announces)
+	require.EqualValues(t, 1000, points[0].Uploaded)
+	require.EqualValues(t, 500, points[0].Downloaded)
+	require.EqualValues(t, 1, points[0].Leechers)
+
+	// Users are credited the transfer after multipliers
+	userPoints, err = UserSeries(testUsers[0].UserID, store.Hour, 2)
+	require.NoError(t, err)
+	_, uploaded := sumSeries(userPoints)
+	require.EqualValues(t, userUploaded+2000, uploaded)
+
+	// Clients send the session totals, only the change since the last announce is recorded
+	req.event = string(consts.ANNOUNCE)
+	req.Uploaded, req.Downloaded = "3000", "1500"
+	w = performRequest(rh, "GET", fmt.Sprintf("/announce/%s?%s", req.PK, req.ToValues().Encode()), nil, nil)
+	require.EqualValues(t, msgOk, errCode(w.Code))
+	points, err = TorrentSeries(tor.InfoHash, store.Hour, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, points[0].Announces)
+	require.EqualValues(t, 3000, points[0].Uploaded)
+	require.EqualValues(t, 1500, points[0].Downloaded)
+	userPoints, err = UserSeries(testUsers[0].UserID, store.Hour, 2)
+	require.NoError(t, err)
+	_, uploaded = sumSeries(userPoints)
+	require.EqualValues(t, userUploaded+6000, uploaded)
+
+	_, err = TorrentSeries(store.GenerateTestTorrent().InfoHash, store.Hour, 1)
+	require.Equal(t, consts.ErrInvalidInfoHash, err)
+	_, err = UserSeries(0, store.Hour, 1)
+	require.Error(t, err)
+
+	s := getTorrentSeries(tor.InfoHash, false)
+	require.Contains(t, findDirtySeries(), s)
+	require.NoError(t, seriesSync(findDirtySeries()))
+	require.EqualValues(t, 0, atomic.LoadUint32(&s.Writes))
+	require.NotContains(t, findDirtySeries(), s)
+	stored, err := db.Series()
+	require.NoError(t, err)
+	found := false
+	for _, ss := range stored {
+		if ss.Kind == store.SeriesTorrent && ss.Key == store.TorrentSeriesKey(tor.InfoHash) {
+			found = true
+		}
+	}
+	require.True(t, found)
+}
+
+// writingStore records another write to each series while it is being synced
+type writingStore struct {
+	store.Store
+}
+
+func (w writingStore) SeriesSync(b []*store.Series) error {
+	for _, s := range b {
+		s.Add(time.Now(), store.SeriesDelta{Announces: 1})
+	}
+	return w.Store.SeriesSync(b)
+}
+
+func TestSeriesSyncConcurrentWrite(t *testing.T) {
+	s := store.NewSeries(store.SeriesUser, store.UserSeriesKey(1000), seriesSizes())
+	s.Add(time.Now(), store.SeriesDelta{Announces: 1})
+	s.Add(time.Now(), store.SeriesDelta{Announces: 1})
+	storeMu.Lock()
+	prev := db
+	db = writingStore{prev}
+	storeMu.Unlock()
+	defer func() {
+		storeMu.Lock()
+		db = prev
+		storeMu.Unlock()
+	}()
+	require.NoError(t, seriesSync([]*store.Series{s}))
+	require.EqualValues(t, 1, atomic.LoadUint32(&s.Writes), "writes made during the sync should be kept")
+	require.NoError(t, prev.SeriesDelete(store.SeriesUser, s.Key))
+}
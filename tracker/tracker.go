@@ -5,6 +5,7 @@ import (
 	"github.com/leighmacdonald/mika/config"
 	"github.com/leighmacdonald/mika/consts"
 	"github.com/leighmacdonald/mika/geo"
+	"github.com/leighmacdonald/mika/metrics"
 	"github.com/leighmacdonald/mika/store"
 	"github.com/leighmacdonald/mika/util"
 	"github.com/pkg/errors"
@@ -22,11 +23,30 @@ var (
 	storeMu     *sync.RWMutex
 	db          store.Store
 	users       store.Users
+	usersMu     *sync.RWMutex
 	roles       store.Roles
+	rolesMu     *sync.RWMutex
 	whitelist   store.WhiteList
 	torrents    store.Torrents
+	torrentsMu  *sync.RWMutex
 	geodb       geo.Provider
 	whitelistMu *sync.RWMutex
+	// userPeers indexes the active peers of each user by user_id so that they can be
+	// located without scanning every swarm
+	userPeers   map[uint32]map[store.PeerHash]struct{}
+	userPeersMu *sync.RWMutex
+	// infoHashAliases maps the truncated v2 infohash of hybrid torrents to the v1 infohash
+	// which is used as the key for the shared swarm
+	infoHashAliases   map[store.InfoHash]store.InfoHash
+	infoHashAliasesMu *sync.RWMutex
+	// scrapeBudgets counts the info hashes scraped by each user, or ip in public mode, within the
+	// current scrape_interval
+	scrapeBudgets   map[scrapeKey]*scrapeBudget
+	scrapeBudgetsMu *sync.Mutex
+	// scrapeBudgetsSwept is the last time expired entries were removed from scrapeBudgets
+	scrapeBudgetsSwept time.Time
+	// connChecker performs the connectable checks of new peers
+	connChecker *connectChecker
 )
 
 func init() {
@@ -37,8 +57,19 @@ func init() {
 	ts, _ := store.NewStore(memCfg)
 	db = ts
 	torrents = make(store.Torrents)
+	torrentsMu = &sync.RWMutex{}
 	users = make(store.Users)
+	usersMu = &sync.RWMutex{}
 	roles = make(store.Roles)
+	rolesMu = &sync.RWMutex{}
+	userPeers = make(map[uint32]map[store.PeerHash]struct{})
+	userPeersMu = &sync.RWMutex{}
+	infoHashAliases = make(map[store.InfoHash]store.InfoHash)
+	infoHashAliasesMu = &sync.RWMutex{}
+	scrapeBudgets = make(map[scrapeKey]*scrapeBudget)
+	scrapeBudgetsMu = &sync.Mutex{}
+	connChecker = newConnectChecker(1000)
+	metrics.RegisterCollector(collectMetrics)
 }
 
 func Init() {
@@ -65,30 +96,91 @@ func Init() {
 	}
 
 	whitelist = loadWhitelist()
-	roles = loadRoles()
-	users = loadUsers()
-	torrents = loadTorrents()
+	loadedRoles := loadRoles()
+	rolesMu.Lock()
+	roles = loadedRoles
+	rolesMu.Unlock()
+	loadedUsers := loadUsers()
+	usersMu.Lock()
+	users = loadedUsers
+	usersMu.Unlock()
+	loadedTorrents := loadTorrents()
+	torrentsMu.Lock()
+	torrents = loadedTorrents
+	torrentsMu.Unlock()
+	loadSeries()
 }
 
 func mapRoleToUser(u *store.User) {
-	u.Role = roles[u.RoleID]
+	u.Role, _ = roleGet(u.RoleID)
 }
 
+// roleGet returns the role matching the role_id
+func roleGet(roleID uint32) (*store.Role, bool) {
+	rolesMu.RLock()
+	defer rolesMu.RUnlock()
+	r, found := roles[roleID]
+	return r, found
+}
+
+// legacyWhitelistPrefixLen is the length of the peer_id prefixes older versions stored as the
+// client code of whitelist entries
+const legacyWhitelistPrefixLen = 8
+
 // loadWhitelist will read the client white list from the tracker store and
 // load it into memory for quick lookups.
 func loadWhitelist() store.WhiteList {
 	newWhitelist := make(store.WhiteList)
 	wl, err4 := db.WhiteListGetAll()
 	if err4 != nil {
-		log.Warn("whitelist empty, all clients are allowed")
-	} else {
-		for _, cw := range wl {
-			newWhitelist[cw.ClientPrefix] = cw
+		log.Errorf("Failed to load whitelist: %v", err4)
+	}
+	for _, cw := range wl {
+		// Client codes are at most 4 chars, only the full 8 char peer_id prefixes of older
+		// versions need converting
+		if len(cw.ClientCode) == legacyWhitelistPrefixLen {
+			migrated, err := migrateWhitelistPrefix(cw)
+			if err != nil {
+				log.Errorf("Failed to convert whitelist prefix %s, it must be added again "+
+					"using its client code: %v", cw.ClientCode, err)
+				continue
+			}
+			cw = migrated
 		}
+		newWhitelist[cw.Key()] = cw
+	}
+	if len(newWhitelist) == 0 && config.Tracker.WhitelistMode == config.WhitelistDenyAll {
+		log.Warnf("Whitelist empty and whitelist_mode is %s, all clients will be rejected",
+			config.Tracker.WhitelistMode)
 	}
 	return newWhitelist
 }
 
+// migrateWhitelistPrefix replaces a whitelist entry created using the exact 8 char peer_id
+// prefix of older versions with an entry matching the client code and the single version
+// that the prefix encoded.
+func migrateWhitelistPrefix(wl *store.WhiteListClient) (*store.WhiteListClient, error) {
+	cl := store.ClientString(store.PeerIDFromString(wl.ClientCode))
+	if cl.Name == store.ClientUnknown {
+		return nil, consts.ErrInvalidClient
+	}
+	migrated := &store.WhiteListClient{
+		ClientCode: cl.Code,
+		ClientName: wl.ClientName,
+		MinVersion: cl.Version(),
+		MaxVersion: cl.Version(),
+		Blacklist:  wl.Blacklist,
+	}
+	if err := db.WhiteListAdd(migrated); err != nil {
+		return nil, errors.Wrap(err, "Failed to add converted whitelist")
+	}
+	if err := db.WhiteListDelete(wl); err != nil {
+		return nil, errors.Wrap(err, "Failed to remove old whitelist")
+	}
+	log.Infof("Converted whitelist prefix %s to client code %s", wl.ClientCode, migrated.Key())
+	return migrated, nil
+}
+
 func loadRoles() store.Roles {
 	roleSet, err := db.Roles()
 	if err != nil {
@@ -113,9 +205,16 @@ func loadTorrents() store.Torrents {
 	if err != nil {
 		log.Fatalf("Failed to load torrents")
 	}
+	aliases := make(map[store.InfoHash]store.InfoHash)
 	for _, t := range torrentSet {
 		t.Peers = store.NewSwarm()
+		if t.IsHybrid() {
+			aliases[t.InfoHashV2.Truncated()] = t.InfoHash
+		}
 	}
+	infoHashAliasesMu.Lock()
+	infoHashAliases = aliases
+	infoHashAliasesMu.Unlock()
 	return torrentSet
 }
 
@@ -144,9 +243,11 @@ func PeerReaper(ctx context.Context) {
 
 func findDirtyUsers(n int) ([]*store.User, error) {
 	var sorted []*store.User
+	usersMu.RLock()
 	for _, t := range users {
 		sorted = append(sorted, t)
 	}
+	usersMu.RUnlock()
 	sort.Slice(sorted, func(i, j int) bool {
 		return sorted[i].Writes < sorted[j].Writes
 	})
@@ -155,9 +256,11 @@ func findDirtyUsers(n int) ([]*store.User, error) {
 
 func findDirtyTorrents(n int) ([]*store.Torrent, error) {
 	var sorted []*store.Torrent
+	torrentsMu.RLock()
 	for _, t := range torrents {
 		sorted = append(sorted, t)
 	}
+	torrentsMu.RUnlock()
 	sort.Slice(sorted, func(i, j int) bool {
 		return sorted[i].Writes < sorted[j].Writes
 	})
@@ -191,6 +294,9 @@ func StatWorker(ctx context.Context) {
 				log.Errorf("Failed to sync dirty torrents: %v", err4)
 				continue
 			}
+			if err5 := seriesSync(findDirtySeries()); err5 != nil {
+				log.Errorf("Failed to sync series: %v", err5)
+			}
 			syncTimer.Reset(config.Tracker.BatchUpdateIntervalParsed)
 		case <-ctx.Done():
 			log.Debugf("Batch context closed")
@@ -240,25 +346,44 @@ func Migrate() error {
 	return db.Migrate()
 }
 
+// ClientWhitelisted returns false if the client decoded from the peer_id matches any blacklist
+// entries. Otherwise clients matching a whitelist entry are allowed and the rest are allowed or
+// denied depending on the whitelist_mode.
 func ClientWhitelisted(peerID store.PeerID) bool {
+	cl := store.ClientString(peerID)
 	whitelistMu.RLock()
-	_, found := whitelist[string(peerID[0:8])]
-	whitelistMu.RUnlock()
-	return found
+	defer whitelistMu.RUnlock()
+	allowed := config.Tracker.WhitelistMode == config.WhitelistAllowAll
+	for _, wl := range whitelist {
+		if !wl.Match(cl) {
+			continue
+		}
+		if wl.Blacklist {
+			return false
+		}
+		allowed = true
+	}
+	return allowed
 }
 
 func WhiteListAdd(wl *store.WhiteListClient) error {
+	if err := wl.Validate(); err != nil {
+		return errors.Wrap(err, "Invalid client whitelist")
+	}
 	if err := db.WhiteListAdd(wl); err != nil {
 		return errors.Wrap(err, "Failed to add new client whitelist")
 	}
 	whitelistMu.Lock()
 	defer whitelistMu.Unlock()
-	whitelist[wl.ClientPrefix] = wl
+	whitelist[wl.Key()] = wl
 	return nil
 }
 
-func WhiteListGet(p string) (*store.WhiteListClient, error) {
-	w, found := whitelist[p]
+// WhiteListGet returns the whitelist entry matching the WhiteListClient.Key
+func WhiteListGet(key string) (*store.WhiteListClient, error) {
+	whitelistMu.RLock()
+	defer whitelistMu.RUnlock()
+	w, found := whitelist[key]
 	if !found {
 		return nil, consts.ErrInvalidClient
 	}
@@ -269,7 +394,9 @@ func WhiteListDelete(wl *store.WhiteListClient) error {
 	if err := db.WhiteListDelete(wl); err != nil {
 		return err
 	}
-	delete(whitelist, wl.ClientPrefix)
+	whitelistMu.Lock()
+	delete(whitelist, wl.Key())
+	whitelistMu.Unlock()
 	return nil
 }
 
@@ -277,22 +404,105 @@ func WhiteList() store.WhiteList {
 	return whitelist
 }
 
+// Torrents returns a copy of the torrents known to the tracker keyed by infohash
 func Torrents() store.Torrents {
-	return torrents
+	torrentsMu.RLock()
+	defer torrentsMu.RUnlock()
+	t := make(store.Torrents, len(torrents))
+	for k, v := range torrents {
+		t[k] = v
+	}
+	return t
 }
 
+// TorrentAdd registers a new torrent with the tracker. v2 only torrents may be added with just
+// the InfoHashV2 set, the truncated form will be used as the InfoHash. Hybrid torrents can be
+// found using either the v1 or truncated v2 infohash. If the truncated v2 infohash of a hybrid
+// torrent is already registered on its own, eg: by auto_register, it is merged into the hybrid.
 func TorrentAdd(torrent *store.Torrent) error {
+	if torrent.InfoHash == (store.InfoHash{}) && !torrent.InfoHashV2.IsZero() {
+		torrent.InfoHash = torrent.InfoHashV2.Truncated()
+	}
+	if _, err := TorrentGet(torrent.InfoHash, true); err == nil {
+		return consts.ErrDuplicate
+	}
+	var v2Swarm *store.Torrent
+	if torrent.IsHybrid() {
+		existing, err := TorrentGet(torrent.InfoHashV2.Truncated(), true)
+		if err == nil {
+			if existing.IsDeleted || existing.InfoHash != torrent.InfoHashV2.Truncated() {
+				return consts.ErrDuplicate
+			}
+			v2Swarm = existing
+		}
+	}
 	torrent.CreatedOn = util.Now()
 	torrent.UpdatedOn = util.Now()
 	if err := db.TorrentAdd(torrent); err != nil {
 		return errors.Wrapf(err, "Failed to add torrent")
 	}
+	if torrent.Peers == nil {
+		torrent.Peers = store.NewSwarm()
+	}
+	if v2Swarm != nil {
+		torrentMerge(torrent, v2Swarm)
+	}
+	torrentsMu.Lock()
 	torrents[torrent.InfoHash] = torrent
+	torrentsMu.Unlock()
+	if torrent.IsHybrid() {
+		infoHashAliasesMu.Lock()
+		infoHashAliases[torrent.InfoHashV2.Truncated()] = torrent.InfoHash
+		infoHashAliasesMu.Unlock()
+	}
+	if v2Swarm != nil {
+		torrentsMu.Lock()
+		delete(torrents, v2Swarm.InfoHash)
+		torrentsMu.Unlock()
+		if err := db.TorrentDelete(v2Swarm.InfoHash, true); err != nil {
+			log.Errorf("Failed to remove merged v2 torrent %s: %v", v2Swarm.InfoHash, err)
+		}
+	}
+	publish(Event{Type: EventTorrentAdded, InfoHash: torrent.InfoHash})
 	return nil
 }
 
+// torrentMerge moves the peers, totals and series of the v2 only torrent src into the hybrid torrent dst
+func torrentMerge(dst *store.Torrent, src *store.Torrent) {
+	src.Peers.RLock()
+	var peers []*store.Peer
+	for _, p := range src.Peers.Peers {
+		peers = append(peers, p)
+	}
+	src.Peers.RUnlock()
+	for _, p := range peers {
+		dst.Peers.Add(p)
+		userPeerRemove(p.UserID, src.InfoHash, p.PeerID)
+		userPeerAdd(p.UserID, dst.InfoHash, p.PeerID)
+	}
+	atomic.AddUint32(&dst.Seeders, atomic.LoadUint32(&src.Seeders))
+	atomic.AddUint32(&dst.Leechers, atomic.LoadUint32(&src.Leechers))
+	atomic.AddUint32(&dst.Snatches, atomic.LoadUint32(&src.Snatches))
+	atomic.AddUint64(&dst.Announces, atomic.LoadUint64(&src.Announces))
+	atomic.AddUint64(&dst.Uploaded, atomic.LoadUint64(&src.Uploaded))
+	atomic.AddUint64(&dst.Downloaded, atomic.LoadUint64(&src.Downloaded))
+	atomic.AddUint64(&dst.UploadedReal, atomic.LoadUint64(&src.UploadedReal))
+	atomic.AddUint64(&dst.DownloadedReal, atomic.LoadUint64(&src.DownloadedReal))
+	torrentSeriesMerge(dst.InfoHash, src.InfoHash)
+	dst.Log().WithField("peers", len(peers)).Infof("Merged v2 swarm %s into hybrid torrent", src.InfoHash)
+}
+
+// TorrentGet returns the torrent matching the infohash. The infohash can be either the v1
+// or the truncated v2 infohash of a torrent.
 func TorrentGet(hash store.InfoHash, deletedOk bool) (*store.Torrent, error) {
+	infoHashAliasesMu.RLock()
+	if canonical, found := infoHashAliases[hash]; found {
+		hash = canonical
+	}
+	infoHashAliasesMu.RUnlock()
+	torrentsMu.RLock()
 	t, found := torrents[hash]
+	torrentsMu.RUnlock()
 	if !found {
 		return nil, consts.ErrInvalidInfoHash
 	}
@@ -307,6 +517,13 @@ func TorrentDelete(torrent *store.Torrent) error {
 		return err
 	}
 	torrent.IsDeleted = true
+	if torrent.IsHybrid() {
+		infoHashAliasesMu.Lock()
+		delete(infoHashAliases, torrent.InfoHashV2.Truncated())
+		infoHashAliasesMu.Unlock()
+	}
+	torrentSeriesDelete(torrent.InfoHash)
+	publish(Event{Type: EventTorrentDeleted, InfoHash: torrent.InfoHash})
 	return nil
 }
 
@@ -333,7 +550,10 @@ func TorrentDelete(torrent *store.Torrent) error {
 //}
 
 func torrentSync(batch []*store.Torrent) error {
-	if err := db.TorrentSync(batch); err != nil {
+	start := time.Now()
+	err := db.TorrentSync(batch)
+	metrics.ObserveStoreSync("torrents", start, err)
+	if err != nil {
 		return err
 	}
 	for _, t := range batch {
@@ -3,9 +3,14 @@ package tracker
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"github.com/leighmacdonald/mika/config"
+	"github.com/leighmacdonald/mika/consts"
 	"github.com/leighmacdonald/mika/store"
+	"github.com/leighmacdonald/mika/util"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -109,6 +114,8 @@ func TestMain(m *testing.M) {
 	config.General.RunMode = "test"
 	config.Tracker.AllowNonRoutable = false
 	config.Tracker.AllowClientIP = true
+	// Most tests announce back to back, TestAnnounceThrottle covers the minimum interval
+	config.Tracker.AnnounceIntervalMinimumParsed = 0
 	Init()
 	if err := seedTestTracker(); err != nil {
 		log.Errorf("Failed to seed tracker for test: %v", err)
@@ -133,12 +140,14 @@ func seedTestTracker() error {
 	user1 := store.GenerateTestUser()
 	user1.RoleID = role0.RoleID
 	wl1 := store.WhiteListClient{
-		ClientPrefix: "-qB4330-",
-		ClientName:   "qbittorrent 4.3.3",
+		ClientCode: "qB",
+		ClientName: "qbittorrent 4.x",
+		MinVersion: "4.0",
+		MaxVersion: "4.9.9",
 	}
 	wl2 := store.WhiteListClient{
-		ClientPrefix: "-DE13F0-",
-		ClientName:   "Deluge 1.3",
+		ClientCode: "DE",
+		ClientName: "Deluge",
 	}
 	if err := WhiteListAdd(&wl1); err != nil {
 		return err
@@ -163,22 +172,150 @@ func seedTestTracker() error {
 	testTorrents = append(testTorrents, &torrent0)
 
 	leecher0 := store.GenerateTestPeer()
+	leecher0.PeerID = newTestPeerID("-qB4330-")
 	leecher0.Left = 10000
 	//torrent0.Peers.Add(leecher0)
 	testLeechers = append(testLeechers, leecher0)
 
 	seeder0 := store.GenerateTestPeer()
+	seeder0.PeerID = newTestPeerID("-qB4330-")
 	seeder0.Left = 0
 	//torrent0.Peers.Add(seeder0)
 	testSeeders = append(testSeeders, seeder0)
-
-	_ = WhiteListAdd(&store.WhiteListClient{
-		ClientPrefix: string(leecher0.PeerID.Bytes())[0:8],
-		ClientName:   "test client",
-	})
-	_ = WhiteListAdd(&store.WhiteListClient{
-		ClientPrefix: string(seeder0.PeerID.Bytes())[0:8],
-		ClientName:   "test client",
-	})
 	return nil
 }
+
+// newTestPeerID creates a random peer_id using the client prefix
+func newTestPeerID(prefix string) store.PeerID {
+	b, _ := util.GenRandomBytes(20 - len(prefix))
+	return store.PeerIDFromString(prefix + string(b))
+}
+
+func TestTorrentHybrid(t *testing.T) {
+	rh := NewBitTorrentHandler()
+	hybrid := store.GenerateTestTorrent()
+	ihV2, _ := util.GenRandomBytes(32)
+	require.NoError(t, store.InfoHashV2FromBytes(&hybrid.InfoHashV2, ihV2))
+	require.NoError(t, TorrentAdd(&hybrid))
+	require.True(t, errors.Is(TorrentAdd(&store.Torrent{InfoHashV2: hybrid.InfoHashV2}), consts.ErrDuplicate))
+
+	v2Only := store.Torrent{}
+	ihV2Only, _ := util.GenRandomBytes(32)
+	require.NoError(t, store.InfoHashV2FromBytes(&v2Only.InfoHashV2, ihV2Only))
+	require.NoError(t, TorrentAdd(&v2Only))
+	require.Equal(t, v2Only.InfoHashV2.Truncated(), v2Only.InfoHash)
+
+	for _, ih := range []store.InfoHash{hybrid.InfoHash, hybrid.InfoHashV2.Truncated()} {
+		tor, err := TorrentGet(ih, false)
+		require.NoError(t, err)
+		require.Equal(t, &hybrid, tor)
+	}
+
+	// Peers announcing with either hash share the same swarm
+	reqV1 := testReq{Ih: hybrid.InfoHash, PID: testLeechers[0].PeerID, IP: "12.34.56.78", event: "started",
+		Port: "4000", Uploaded: "0", Downloaded: "0", left: "5000", PK: testUsers[0].Passkey}
+	reqV2 := testReq{Ih: hybrid.InfoHashV2.Truncated(), PID: testSeeders[0].PeerID, IP: "12.34.56.79", event: "started",
+		Port: "4000", Uploaded: "0", Downloaded: "0", left: "0", PK: testUsers[1].Passkey}
+	for _, req := range []testReq{reqV1, reqV2} {
+		w := performRequest(rh, "GET", fmt.Sprintf("/announce/%s?%s", req.PK, req.ToValues().Encode()), nil, nil)
+		require.EqualValues(t, msgOk, errCode(w.Code))
+	}
+	peers, err := hybrid.Peers.GetN(10)
+	require.NoError(t, err)
+	require.Len(t, peers, 2)
+	require.Equal(t, 1, int(hybrid.Seeders))
+	require.Equal(t, 1, int(hybrid.Leechers))
+
+	// A swarm already registered under the truncated v2 hash is merged into the hybrid torrent
+	v2Swarm := store.Torrent{}
+	ihV2Swarm, _ := util.GenRandomBytes(32)
+	require.NoError(t, store.InfoHashV2FromBytes(&v2Swarm.InfoHashV2, ihV2Swarm))
+	require.NoError(t, TorrentAdd(&v2Swarm))
+	reqV2.Ih = v2Swarm.InfoHash
+	w := performRequest(rh, "GET", fmt.Sprintf("/announce/%s?%s", reqV2.PK, reqV2.ToValues().Encode()), nil, nil)
+	require.EqualValues(t, msgOk, errCode(w.Code))
+	merged := store.GenerateTestTorrent()
+	merged.InfoHashV2 = v2Swarm.InfoHashV2
+	require.NoError(t, TorrentAdd(&merged))
+	tor, err := TorrentGet(v2Swarm.InfoHash, false)
+	require.NoError(t, err)
+	require.Equal(t, &merged, tor)
+	require.Equal(t, 1, int(merged.Seeders))
+	_, err = merged.Peers.Get(reqV2.PID)
+	require.NoError(t, err)
+	require.Contains(t, UserPeers(testUsers[1].UserID), store.NewPeerHash(merged.InfoHash, reqV2.PID))
+	require.NotContains(t, UserPeers(testUsers[1].UserID), store.NewPeerHash(v2Swarm.InfoHash, reqV2.PID))
+	require.Nil(t, getTorrentSeries(v2Swarm.InfoHash, false), "v2 series should be merged into the hybrid")
+	points, err := TorrentSeries(merged.InfoHash, store.Hour, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, points[0].Announces)
+
+	// Deleting the hybrid removes the v2 alias and the series
+	require.NoError(t, TorrentDelete(&merged))
+	_, err = TorrentGet(v2Swarm.InfoHash, true)
+	require.Error(t, err)
+	require.Nil(t, getTorrentSeries(merged.InfoHash, false))
+}
+
+func TestClientWhitelisted(t *testing.T) {
+	xl := newTestPeerID("-XL0012-")
+	require.False(t, ClientWhitelisted(xl))
+	allowed := store.WhiteListClient{ClientCode: "XL", ClientName: "Xunlei"}
+	require.NoError(t, WhiteListAdd(&allowed))
+	require.True(t, ClientWhitelisted(xl))
+
+	// Blacklist entries take precedence over whitelist entries
+	denied := store.WhiteListClient{ClientCode: "XL", ClientName: "Xunlei 0.0.x",
+		MaxVersion: "0.0.9", Blacklist: true}
+	require.NoError(t, WhiteListAdd(&denied))
+	require.False(t, ClientWhitelisted(xl))
+	require.True(t, ClientWhitelisted(newTestPeerID("-XL0100-")))
+
+	// Versions outside of the range of a whitelist entry are not allowed
+	require.True(t, ClientWhitelisted(newTestPeerID("-qB4330-")))
+	require.False(t, ClientWhitelisted(newTestPeerID("-qB3360-")))
+
+	require.Error(t, WhiteListAdd(&store.WhiteListClient{ClientCode: "XL", MinVersion: "x"}))
+	require.Error(t, WhiteListAdd(&store.WhiteListClient{ClientCode: "-XL0012-"}))
+	require.NoError(t, WhiteListDelete(&denied))
+	require.NoError(t, WhiteListDelete(&allowed))
+	require.False(t, ClientWhitelisted(xl))
+
+	// Unmatched clients are allowed with allow_all, blacklisted clients are still denied
+	config.Tracker.WhitelistMode = config.WhitelistAllowAll
+	defer func() { config.Tracker.WhitelistMode = config.WhitelistDenyAll }()
+	require.True(t, ClientWhitelisted(xl))
+	require.NoError(t, WhiteListAdd(&denied))
+	require.False(t, ClientWhitelisted(xl))
+	require.NoError(t, WhiteListDelete(&denied))
+}
+
+func TestMigrateWhitelistPrefix(t *testing.T) {
+	legacy := &store.WhiteListClient{ClientCode: "-TR2940-", ClientName: "Transmission 2.94"}
+	require.NoError(t, db.WhiteListAdd(legacy))
+	loaded := loadWhitelist()
+	require.NotContains(t, loaded, legacy.Key())
+	migrated, found := loaded["TR:2.94.0.0-2.94.0.0"]
+	require.True(t, found)
+	require.Equal(t, &store.WhiteListClient{ClientCode: "TR", ClientName: "Transmission 2.94",
+		MinVersion: "2.94.0.0", MaxVersion: "2.94.0.0"}, migrated)
+	stored, err := db.WhiteListGetAll()
+	require.NoError(t, err)
+	for _, wl := range stored {
+		require.NotEqual(t, legacy.ClientCode, wl.ClientCode)
+	}
+	require.NoError(t, db.WhiteListDelete(migrated))
+
+	// Client codes longer than 2 chars are not legacy prefixes and are loaded unchanged
+	for _, code := range []string{"exbc", "XBT"} {
+		wl := &store.WhiteListClient{ClientCode: code, ClientName: code}
+		require.NoError(t, wl.Validate())
+		require.NoError(t, db.WhiteListAdd(wl))
+		loaded = loadWhitelist()
+		require.Equal(t, wl, loaded[wl.Key()])
+		stored, err = db.WhiteListGetAll()
+		require.NoError(t, err)
+		require.Contains(t, stored, wl)
+		require.NoError(t, db.WhiteListDelete(wl))
+	}
+}
@@ -2,12 +2,21 @@ package tracker
 
 import (
 	"github.com/leighmacdonald/mika/consts"
+	"github.com/leighmacdonald/mika/metrics"
 	"github.com/leighmacdonald/mika/store"
 	"github.com/leighmacdonald/mika/util"
+	"time"
 )
 
+// Users returns a copy of the users known to the tracker keyed by passkey
 func Users() store.Users {
-	return users
+	usersMu.RLock()
+	defer usersMu.RUnlock()
+	u := make(store.Users, len(users))
+	for k, v := range users {
+		u[k] = v
+	}
+	return u
 }
 
 func UserAdd(user *store.User) error {
@@ -17,12 +26,18 @@ func UserAdd(user *store.User) error {
 	if err := db.UserAdd(user); err != nil {
 		return err
 	}
+	mapRoleToUser(user)
+	usersMu.Lock()
 	users[user.Passkey] = user
+	usersMu.Unlock()
+	publish(Event{Type: EventUserAdded, UserID: user.UserID})
 	return nil
 }
 
 func UserGetByPasskey(passkey string) (*store.User, error) {
+	usersMu.RLock()
 	u, found := users[passkey]
+	usersMu.RUnlock()
 	if !found {
 		return nil, consts.ErrInvalidUser
 	}
@@ -30,6 +45,8 @@ func UserGetByPasskey(passkey string) (*store.User, error) {
 }
 
 func UserGetByUserID(userID uint32) (*store.User, error) {
+	usersMu.RLock()
+	defer usersMu.RUnlock()
 	for _, u := range users {
 		if u.UserID == userID {
 			return u, nil
@@ -39,6 +56,8 @@ func UserGetByUserID(userID uint32) (*store.User, error) {
 }
 
 func UserGetByRemoteID(remoteID uint64) (*store.User, error) {
+	usersMu.RLock()
+	defer usersMu.RUnlock()
 	for _, u := range users {
 		if u.RemoteID == remoteID {
 			return u, nil
@@ -47,24 +66,115 @@ func UserGetByRemoteID(remoteID uint64) (*store.User, error) {
 	return nil, consts.ErrInvalidUser
 }
 
+// UserSave persists the user
 func UserSave(user *store.User) error {
-	return db.UserSave(user)
+	if err := db.UserSave(user); err != nil {
+		return err
+	}
+	publish(Event{Type: EventUserUpdated, UserID: user.UserID})
+	return nil
 }
 
-func userSync(batch []*store.User) error {
-	if err := db.UserSync(batch); err != nil {
+// UserUpdate applies the update to the user and persists it. If the update disables downloading
+// for the user their leeching peers are removed from the swarms once the change is saved, seeding
+// is still permitted so seeders are kept.
+func UserUpdate(user *store.User, update func(u *store.User)) error {
+	wasEnabled := user.DownloadEnabled
+	update(user)
+	if err := UserSave(user); err != nil {
 		return err
 	}
+	if wasEnabled && !user.DownloadEnabled {
+		evicted := userPeersEvict(user.UserID, true)
+		user.Log().WithField("peers", evicted).Debug("Evicted leechers of download disabled user")
+	}
 	return nil
 }
 
+func userSync(batch []*store.User) error {
+	start := time.Now()
+	err := db.UserSync(batch)
+	metrics.ObserveStoreSync("users", start, err)
+	return err
+}
+
+// UserDelete marks the user as deleted. Once saved the passkey is invalidated, all of the users
+// peers are removed from the swarms they are participating in and their series is removed. The
+// user is left unchanged if the save fails.
 func UserDelete(user *store.User) error {
-	// TODO remove from swarms
-	// TODO updated references to deleted user?
 	user.IsDeleted = true
-	if err := UserSave(user); err != nil {
+	if err := db.UserSave(user); err != nil {
+		user.IsDeleted = false
 		return err
 	}
+	usersMu.Lock()
 	delete(users, user.Passkey)
+	usersMu.Unlock()
+	evicted := userPeersEvict(user.UserID, false)
+	user.Log().WithField("peers", evicted).Debug("Evicted peers of deleted user")
+	userSeriesDelete(user.UserID)
+	publish(Event{Type: EventUserDeleted, UserID: user.UserID})
 	return nil
 }
+
+// userPeerAdd records the peer as belonging to the user in the user peer index
+func userPeerAdd(userID uint32, ih store.InfoHash, peerID store.PeerID) {
+	userPeersMu.Lock()
+	up, found := userPeers[userID]
+	if !found {
+		up = make(map[store.PeerHash]struct{})
+		userPeers[userID] = up
+	}
+	up[store.NewPeerHash(ih, peerID)] = struct{}{}
+	userPeersMu.Unlock()
+}
+
+// userPeerRemove removes the peer from the user peer index
+func userPeerRemove(userID uint32, ih store.InfoHash, peerID store.PeerID) {
+	userPeersMu.Lock()
+	up, found := userPeers[userID]
+	if found {
+		delete(up, store.NewPeerHash(ih, peerID))
+		if len(up) == 0 {
+			delete(userPeers, userID)
+		}
+	}
+	userPeersMu.Unlock()
+}
+
+// UserPeers returns the active peers of a user across all swarms
+func UserPeers(userID uint32) []store.PeerHash {
+	userPeersMu.RLock()
+	defer userPeersMu.RUnlock()
+	var peerHashes []store.PeerHash
+	for ph := range userPeers[userID] {
+		peerHashes = append(peerHashes, ph)
+	}
+	return peerHashes
+}
+
+// userPeersEvict removes the users peers, or only the leeching peers, from every swarm they are in
+// and updates the torrent seeder/leecher counts to match. Returns the number of peers removed.
+func userPeersEvict(userID uint32, leechersOnly bool) int {
+	evicted := 0
+	for _, ph := range UserPeers(userID) {
+		torrentsMu.RLock()
+		tor, found := torrents[ph.InfoHash()]
+		torrentsMu.RUnlock()
+		if !found {
+			userPeerRemove(userID, ph.InfoHash(), ph.PeerID())
+			continue
+		}
+		peer, err := tor.Peers.Get(ph.PeerID())
+		if err != nil {
+			userPeerRemove(userID, ph.InfoHash(), ph.PeerID())
+			continue
+		}
+		if leechersOnly && peer.Left == 0 {
+			continue
+		}
+		removePeer(tor, peer)
+		evicted++
+	}
+	return evicted
+}
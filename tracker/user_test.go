@@ -0,0 +1,121 @@
+package tracker
+
+import (
+	"fmt"
+	"github.com/leighmacdonald/mika/consts"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestUserDelete(t *testing.T) {
+	rh := NewBitTorrentHandler()
+	tor := store.GenerateTestTorrent()
+	require.NoError(t, TorrentAdd(&tor))
+	usr := store.GenerateTestUser()
+	usr.RoleID = testRoles[0].RoleID
+	require.NoError(t, UserAdd(&usr))
+
+	req := testReq{Ih: tor.InfoHash, PID: testLeechers[0].PeerID, IP: "12.34.56.78", event: string(consts.STARTED),
+		Port: "4000", Uploaded: "0", Downloaded: "0", left: "5000", PK: usr.Passkey}
+	u := fmt.Sprintf("/announce/%s?%s", req.PK, req.ToValues().Encode())
+	w := performRequest(rh, "GET", u, nil, nil)
+	require.EqualValues(t, msgOk, errCode(w.Code))
+	require.Equal(t, 1, int(tor.Leechers))
+	require.Len(t, UserPeers(usr.UserID), 1)
+	require.NotNil(t, getUserSeries(usr.UserID, false))
+
+	require.NoError(t, UserDelete(&usr))
+	require.Nil(t, getUserSeries(usr.UserID, false))
+	_, err := tor.Peers.Get(testLeechers[0].PeerID)
+	require.Error(t, err)
+	require.Equal(t, 0, int(tor.Leechers))
+	require.Len(t, UserPeers(usr.UserID), 0)
+	_, err = UserGetByPasskey(usr.Passkey)
+	require.Error(t, err)
+
+	w = performRequest(rh, "GET", u, nil, nil)
+	require.EqualValues(t, msgInvalidAuth, errCode(w.Code))
+}
+
+// failingStore fails to save users
+type failingStore struct {
+	store.Store
+}
+
+func (f failingStore) UserSave(_ *store.User) error {
+	return errors.New("save failed")
+}
+
+func (f failingStore) UserSync(_ []*store.User) error {
+	return errors.New("sync failed")
+}
+
+// withFailingStore replaces the store with a failingStore until the test completes
+func withFailingStore(t *testing.T) {
+	storeMu.Lock()
+	prev := db
+	db = failingStore{prev}
+	storeMu.Unlock()
+	t.Cleanup(func() {
+		storeMu.Lock()
+		db = prev
+		storeMu.Unlock()
+	})
+}
+
+func TestUserDeleteSaveFailed(t *testing.T) {
+	rh := NewBitTorrentHandler()
+	tor := store.GenerateTestTorrent()
+	require.NoError(t, TorrentAdd(&tor))
+	usr := store.GenerateTestUser()
+	usr.RoleID = testRoles[0].RoleID
+	require.NoError(t, UserAdd(&usr))
+	req := testReq{Ih: tor.InfoHash, PID: testLeechers[0].PeerID, IP: "12.34.56.78", event: string(consts.STARTED),
+		Port: "4000", Uploaded: "0", Downloaded: "0", left: "5000", PK: usr.Passkey}
+	w := performRequest(rh, "GET", fmt.Sprintf("/announce/%s?%s", req.PK, req.ToValues().Encode()), nil, nil)
+	require.EqualValues(t, msgOk, errCode(w.Code))
+
+	withFailingStore(t)
+	require.Error(t, UserDelete(&usr))
+	require.False(t, usr.IsDeleted)
+	_, err := UserGetByPasskey(usr.Passkey)
+	require.NoError(t, err, "the passkey must stay valid when the delete was not saved")
+	require.Len(t, UserPeers(usr.UserID), 1, "peers must not be evicted when the delete was not saved")
+}
+
+func TestUserDownloadDisabled(t *testing.T) {
+	rh := NewBitTorrentHandler()
+	tor := store.GenerateTestTorrent()
+	require.NoError(t, TorrentAdd(&tor))
+	usr := store.GenerateTestUser()
+	usr.RoleID = testRoles[0].RoleID
+	require.NoError(t, UserAdd(&usr))
+
+	req := testReq{Ih: tor.InfoHash, PID: testLeechers[0].PeerID, IP: "12.34.56.78", event: string(consts.STARTED),
+		Port: "4000", Uploaded: "0", Downloaded: "0", left: "5000", PK: usr.Passkey}
+	u := fmt.Sprintf("/announce/%s?%s", req.PK, req.ToValues().Encode())
+	w := performRequest(rh, "GET", u, nil, nil)
+	require.EqualValues(t, msgOk, errCode(w.Code))
+
+	seeding := store.GenerateTestTorrent()
+	require.NoError(t, TorrentAdd(&seeding))
+	seedReq := testReq{Ih: seeding.InfoHash, PID: testSeeders[0].PeerID, IP: "12.34.56.78", event: string(consts.STARTED),
+		Port: "4001", Uploaded: "0", Downloaded: "0", left: "0", PK: usr.Passkey}
+	w = performRequest(rh, "GET", fmt.Sprintf("/announce/%s?%s", seedReq.PK, seedReq.ToValues().Encode()), nil, nil)
+	require.EqualValues(t, msgOk, errCode(w.Code))
+
+	// Saving without changing the download state leaves the peers alone
+	require.NoError(t, UserUpdate(&usr, func(u *store.User) { u.UserName = "renamed" }))
+	require.Equal(t, 1, int(tor.Leechers))
+
+	require.NoError(t, UserUpdate(&usr, func(u *store.User) { u.DownloadEnabled = false }))
+	require.Equal(t, 0, int(tor.Leechers))
+	// Seeding is still permitted
+	require.Equal(t, 1, int(seeding.Seeders))
+	require.Len(t, UserPeers(usr.UserID), 1)
+
+	w = performRequest(rh, "GET", u, nil, nil)
+	require.EqualValues(t, msgDownloadDisabled, errCode(w.Code))
+}
@@ -0,0 +1,517 @@
+package tracker
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/leighmacdonald/mika/config"
+	"github.com/leighmacdonald/mika/consts"
+	"github.com/leighmacdonald/mika/metrics"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/leighmacdonald/mika/util"
+	log "github.com/sirupsen/logrus"
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The WebTorrent tracker protocol is used by browser based clients. Since browsers are
+// unable to open plain TCP/UDP connections to other peers, the tracker does not hand out peer
+// addresses. Instead it relays the WebRTC signalling messages (SDP offers & answers) between the
+// peers connected to it so they can establish a direct connection themselves.
+//
+// Messages are JSON encoded. The info_hash, peer_id and to_peer_id fields are "binary strings"
+// where each character represents a single byte of the value.
+
+const (
+	// wsMaxMessageSize is the largest message we will read from a client. Each offer contains
+	// a full SDP description so this needs to be fairly generous.
+	wsMaxMessageSize = 1 << 16
+	// wsMaxOffers limits how many offers a single announce can ask us to relay
+	wsMaxOffers = 10
+	// wsWriteTimeout is the maximum duration to wait for a message to be written to a client
+	wsWriteTimeout = 10 * time.Second
+)
+
+var (
+	wsUpgrader = websocket.Upgrader{
+		ReadBufferSize:  4096,
+		WriteBufferSize: 4096,
+		// Browser clients connect from the web sites own origin. Authentication is
+		// handled by the passkey the same as normal announces.
+		CheckOrigin: func(r *http.Request) bool {
+			return true
+		},
+	}
+	// wsPeers maps the peers announced over websockets to their connection so that
+	// signalling messages can be relayed to them
+	wsPeers   map[store.PeerHash]*wsConn
+	wsPeersMu *sync.RWMutex
+)
+
+func init() {
+	wsPeers = make(map[store.PeerHash]*wsConn)
+	wsPeersMu = &sync.RWMutex{}
+}
+
+// wsOffer is a WebRTC offer that should be relayed to another peer in the swarm
+type wsOffer struct {
+	Offer   json.RawMessage `json:"offer"`
+	OfferID string          `json:"offer_id"`
+}
+
+// wsRequest is a message received from a WebTorrent client
+type wsRequest struct {
+	Action string `json:"action"`
+	// InfoHash is a single binary string for announces, but may be a list of them for scrapes
+	InfoHash   json.RawMessage `json:"info_hash"`
+	PeerID     string          `json:"peer_id"`
+	Uploaded   float64         `json:"uploaded"`
+	Downloaded float64         `json:"downloaded"`
+	// Left is null when the client does not yet have the torrents metadata
+	Left     *float64        `json:"left"`
+	Event    string          `json:"event"`
+	NumWant  int             `json:"numwant"`
+	Offers   []wsOffer       `json:"offers"`
+	Answer   json.RawMessage `json:"answer"`
+	OfferID  string          `json:"offer_id"`
+	ToPeerID string          `json:"to_peer_id"`
+}
+
+// wsConn is a single connected WebTorrent client. A client may announce any number of
+// torrents over the same connection.
+type wsConn struct {
+	conn *websocket.Conn
+	user *store.User
+	ip   net.IP
+	ipv6 bool
+	// peers are the swarm peers that have been announced over this connection. Only accessed
+	// from the read loop so does not require locking.
+	peers   map[store.PeerHash]struct{}
+	writeMu *sync.Mutex
+}
+
+// wsAnnounce handles the upgrade of an announce request to a WebTorrent websocket
+// connection and serves it until the client disconnects
+func wsAnnounce(c *gin.Context) {
+	// Announces are counted as each message is received, a rejected upgrade is counted as a
+	// single rejected announce
+	usr, valid := preFlightChecks(c.Param("passkey"))
+	if !valid {
+		oops(c, msgInvalidAuth)
+		atomic.AddInt64(&metrics.AnnounceTotal, 1)
+		atomic.AddInt64(&metrics.AnnounceStatusUnauthorized, 1)
+		return
+	}
+	ip, ipv6, err := getIP(nil, false, c)
+	if err != nil {
+		log.Errorf("Failed to parse client ip: %s", c.Request.RemoteAddr)
+		oops(c, msgMalformedRequest)
+		atomic.AddInt64(&metrics.AnnounceTotal, 1)
+		atomic.AddInt64(&metrics.AnnounceStatusMalformed, 1)
+		return
+	}
+	if !config.Tracker.AllowNonRoutable && util.IsPrivateIP(ip) {
+		log.Warnf("Attempt to use non-routable IP value: %s", ip.String())
+		oops(c, msgMalformedRequest)
+		atomic.AddInt64(&metrics.AnnounceTotal, 1)
+		atomic.AddInt64(&metrics.AnnounceStatusMalformed, 1)
+		return
+	}
+	conn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already responded to the client with a http error
+		log.Debugf("Failed to upgrade websocket connection: %v", err)
+		return
+	}
+	ws := &wsConn{
+		conn:    conn,
+		user:    usr,
+		ip:      ip,
+		ipv6:    ipv6,
+		peers:   make(map[store.PeerHash]struct{}),
+		writeMu: &sync.Mutex{},
+	}
+	ws.serve()
+}
+
+// serve reads and handles messages from the client until the connection is closed or
+// the client stops announcing
+func (ws *wsConn) serve() {
+	ws.conn.SetReadLimit(wsMaxMessageSize)
+	for {
+		// Clients are expected to re-announce at least once per interval
+		if err := ws.conn.SetReadDeadline(time.Now().Add(config.Tracker.AnnounceIntervalParsed * 2)); err != nil {
+			break
+		}
+		_, msg, err := ws.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				log.Debugf("Websocket closed unexpectedly: %v", err)
+			}
+			break
+		}
+		if !ws.user.Valid() {
+			// User was deleted while connected
+			ws.fail("announce", nil, msgInvalidAuth)
+			break
+		}
+		var req wsRequest
+		if err := json.Unmarshal(msg, &req); err != nil {
+			ws.fail("", nil, msgMalformedRequest)
+			continue
+		}
+		switch req.Action {
+		case "announce":
+			ws.announce(&req)
+		case "scrape":
+			ws.scrape(&req)
+		default:
+			ws.fail(req.Action, nil, msgInvalidReqType)
+		}
+	}
+	ws.close()
+}
+
+// close removes all of the peers announced over this connection from their swarms and closes
+// the underlying connection
+func (ws *wsConn) close() {
+	for ph := range ws.peers {
+		ws.unregister(ph)
+		tor, err := TorrentGet(ph.InfoHash(), false)
+		if err != nil {
+			continue
+		}
+		peer, err := tor.Peers.Get(ph.PeerID())
+		if err != nil {
+			continue
+		}
+		removePeer(tor, peer)
+	}
+	if err := ws.conn.Close(); err != nil {
+		log.Debugf("Failed to close websocket cleanly: %v", err)
+	}
+}
+
+// send writes a JSON encoded message to the client. This is safe to call from multiple
+// goroutines as messages are relayed to the client from other peers connections.
+func (ws *wsConn) send(msg gin.H) {
+	ws.writeMu.Lock()
+	defer ws.writeMu.Unlock()
+	if err := ws.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+		return
+	}
+	if err := ws.conn.WriteJSON(msg); err != nil {
+		log.Debugf("Failed to write websocket message: %v", err)
+	}
+}
+
+// fail sends a failure message using a preset message code to the client
+func (ws *wsConn) fail(action string, infoHash *store.InfoHash, code errCode) {
+	msg, exists := responseStringMap[code]
+	if !exists {
+		msg = responseStringMap[msgGenericError]
+	}
+	ws.failReason(action, infoHash, msg.Error())
+}
+
+// failReason sends a failure message with a custom reason to the client
+func (ws *wsConn) failReason(action string, infoHash *store.InfoHash, reason string) {
+	resp := gin.H{
+		"action":         action,
+		"failure reason": reason,
+	}
+	if infoHash != nil {
+		resp["info_hash"] = wsEncodeBinary(infoHash.Bytes())
+	}
+	ws.send(resp)
+}
+
+// register associates the peer with this connection so signalling messages can be
+// relayed to it
+func (ws *wsConn) register(ph store.PeerHash) {
+	ws.peers[ph] = struct{}{}
+	wsPeersMu.Lock()
+	wsPeers[ph] = ws
+	wsPeersMu.Unlock()
+}
+
+// unregister removes the peers association with this connection
+func (ws *wsConn) unregister(ph store.PeerHash) {
+	delete(ws.peers, ph)
+	wsPeersMu.Lock()
+	if wsPeers[ph] == ws {
+		delete(wsPeers, ph)
+	}
+	wsPeersMu.Unlock()
+}
+
+// wsPeerConn returns the connection of a peer announced over a websocket
+func wsPeerConn(ph store.PeerHash) (*wsConn, bool) {
+	wsPeersMu.RLock()
+	ws, found := wsPeers[ph]
+	wsPeersMu.RUnlock()
+	return ws, found
+}
+
+// announce handles both regular announces, which may contain offers to relay to other peers,
+// and answers to offers which are relayed back to the peer that made the offer.
+func (ws *wsConn) announce(r *wsRequest) {
+	atomic.AddInt64(&metrics.AnnounceTotal, 1)
+	var infoHash store.InfoHash
+	if !wsParseInfoHash(r.InfoHash, &infoHash) {
+		ws.fail("announce", nil, msgInvalidInfoHash)
+		atomic.AddInt64(&metrics.AnnounceStatusMalformed, 1)
+		return
+	}
+	peerID, ok := wsParsePeerID(r.PeerID)
+	if !ok {
+		ws.fail("announce", &infoHash, msgInvalidPeerID)
+		atomic.AddInt64(&metrics.AnnounceStatusMalformed, 1)
+		return
+	}
+	if r.Answer != nil {
+		ws.relayAnswer(r, infoHash, peerID)
+		return
+	}
+	left := uint32(math.MaxUint32)
+	if r.Left != nil {
+		left = wsUint32(*r.Left)
+	}
+	req := &announceRequest{
+		Downloaded: wsUint32(r.Downloaded),
+		Event:      consts.ParseAnnounceType(r.Event),
+		IP:         ws.ip,
+		IPv6:       ws.ipv6,
+		InfoHash:   infoHash,
+		Left:       left,
+		NumWant:    uint(r.NumWant),
+		PeerID:     peerID,
+		Uploaded:   wsUint32(r.Uploaded),
+		// WebRTC data channels are always encrypted
+		CryptoLevel: consts.Required,
+		WebRTC:      true,
+	}
+	// Users with downloading disabled may only continue seeding
+	if !ws.user.DownloadEnabled && req.Left > 0 && !config.Tracker.Public {
+		ws.fail("announce", &infoHash, msgDownloadDisabled)
+		atomic.AddInt64(&metrics.AnnounceStatusUnauthorized, 1)
+		return
+	}
+	if !ClientWhitelisted(req.PeerID) {
+		ws.fail("announce", &infoHash, msgBadClient)
+		atomic.AddInt64(&metrics.AnnounceStatusUnauthorized, 1)
+		return
+	}
+	// announceTorrent counts its own failures
+	tor, code := announceTorrent(req.InfoHash)
+	if code != msgOk {
+		ws.fail("announce", &infoHash, code)
+		return
+	}
+	if !tor.IsEnabled && tor.Reason != "" {
+		ws.failReason("announce", &infoHash, tor.Reason)
+		atomic.AddInt64(&metrics.AnnounceStatusInvalidInfoHash, 1)
+		return
+	}
+	peer, code := announcePeer(tor, ws.user, req)
+	if code != msgOk {
+		ws.fail("announce", &infoHash, code)
+		return
+	}
+	// WebTorrent clients re-announce whenever they want more peers, sending fresh offers which
+	// need to be relayed, so the minimum interval is not enforced here
+	peer.AnnounceLast = time.Now()
+	atomic.SwapUint32(&peer.Left, req.Left)
+	updateStates(req, peer, tor, ws.user)
+	ph := store.NewPeerHash(infoHash, peerID)
+	if req.Event == consts.STOPPED {
+		ws.unregister(ph)
+	} else {
+		ws.register(ph)
+	}
+	ws.send(gin.H{
+		"action":       "announce",
+		"info_hash":    wsEncodeBinary(infoHash.Bytes()),
+		"complete":     atomic.LoadUint32(&tor.Seeders),
+		"incomplete":   atomic.LoadUint32(&tor.Leechers),
+		"interval":     int(config.Tracker.AnnounceIntervalParsed.Seconds()),
+		"min interval": int(config.Tracker.AnnounceIntervalMinimumParsed.Seconds()),
+	})
+	if req.Event != consts.STOPPED && len(r.Offers) > 0 {
+		ws.relayOffers(tor, peerID, r)
+	}
+	tor.Log().Debug("Announced (websocket)")
+}
+
+// relayOffers sends each of the offers to a different WebRTC peer in the swarm
+func (ws *wsConn) relayOffers(tor *store.Torrent, peerID store.PeerID, r *wsRequest) {
+	offers := r.Offers
+	if len(offers) > wsMaxOffers {
+		offers = offers[:wsMaxOffers]
+	}
+	if r.NumWant > 0 && r.NumWant < len(offers) {
+		offers = offers[:r.NumWant]
+	}
+	if limit := maxPeers(ws.user, uint(len(offers))); limit < len(offers) {
+		offers = offers[:limit]
+	}
+	peers, err := tor.Peers.Select(len(offers), func(p *store.Peer) bool {
+		return !p.WebRTC || p.PeerID == peerID
+	})
+	if err != nil {
+		log.Errorf("Could not read peers from swarm: %s", err.Error())
+		return
+	}
+	infoHash := wsEncodeBinary(tor.InfoHash.Bytes())
+	fromPeerID := wsEncodeBinary(peerID.Bytes())
+	i := 0
+	for _, p := range peers {
+		if i >= len(offers) {
+			break
+		}
+		dst, found := wsPeerConn(store.NewPeerHash(tor.InfoHash, p.PeerID))
+		if !found {
+			continue
+		}
+		dst.send(gin.H{
+			"action":    "announce",
+			"info_hash": infoHash,
+			"peer_id":   fromPeerID,
+			"offer":     offers[i].Offer,
+			"offer_id":  offers[i].OfferID,
+		})
+		i++
+	}
+}
+
+// relayAnswer sends the answer to an offer back to the peer which made the offer
+func (ws *wsConn) relayAnswer(r *wsRequest, infoHash store.InfoHash, peerID store.PeerID) {
+	// Only allow answering as a peer that was announced over this connection
+	if _, found := ws.peers[store.NewPeerHash(infoHash, peerID)]; !found {
+		ws.fail("announce", &infoHash, msgInvalidPeerID)
+		return
+	}
+	toPeerID, ok := wsParsePeerID(r.ToPeerID)
+	if !ok {
+		ws.fail("announce", &infoHash, msgInvalidPeerID)
+		return
+	}
+	dst, found := wsPeerConn(store.NewPeerHash(infoHash, toPeerID))
+	if !found {
+		log.Debugf("Dropping answer for unknown websocket peer: %s", toPeerID.String())
+		return
+	}
+	dst.send(gin.H{
+		"action":    "announce",
+		"info_hash": wsEncodeBinary(infoHash.Bytes()),
+		"peer_id":   wsEncodeBinary(peerID.Bytes()),
+		"answer":    r.Answer,
+		"offer_id":  r.OfferID,
+	})
+}
+
+// scrape handles scrape requests for one or more info hashes
+func (ws *wsConn) scrape(r *wsRequest) {
+	atomic.AddInt64(&metrics.ScrapeTotal, 1)
+	var infoHashStrs []string
+	if err := json.Unmarshal(r.InfoHash, &infoHashStrs); err != nil {
+		var infoHashStr string
+		if err := json.Unmarshal(r.InfoHash, &infoHashStr); err != nil {
+			ws.fail("scrape", nil, msgMalformedRequest)
+			return
+		}
+		infoHashStrs = []string{infoHashStr}
+	}
+	infoHashes := make([]store.InfoHash, 0, len(infoHashStrs))
+	var ih store.InfoHash
+	for _, ihStr := range infoHashStrs {
+		b, ok := wsDecodeBinary(ihStr)
+		if !ok || store.InfoHashFromBytes(&ih, b) != nil {
+			continue
+		}
+		infoHashes = append(infoHashes, ih)
+	}
+	if len(infoHashStrs) > 0 && len(infoHashes) == 0 {
+		ws.fail("scrape", nil, msgInvalidInfoHash)
+		return
+	}
+	results, code := scrapeTorrents(ws.user, ws.ip, infoHashes)
+	if code != msgOk {
+		ws.fail("scrape", nil, code)
+		return
+	}
+	files := make(gin.H, len(results))
+	for infoHash, torrent := range results {
+		files[wsEncodeBinary(infoHash.Bytes())] = gin.H{
+			"complete":   atomic.LoadUint32(&torrent.Seeders),
+			"downloaded": atomic.LoadUint32(&torrent.Snatches),
+			"incomplete": atomic.LoadUint32(&torrent.Leechers),
+		}
+	}
+	ws.send(gin.H{
+		"action": "scrape",
+		"files":  files,
+		"flags": gin.H{
+			"min_request_interval": int(config.Tracker.ScrapeIntervalParsed.Seconds()),
+		},
+	})
+}
+
+// wsParseInfoHash decodes a binary string info hash
+func wsParseInfoHash(raw json.RawMessage, infoHash *store.InfoHash) bool {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return false
+	}
+	b, ok := wsDecodeBinary(s)
+	if !ok {
+		return false
+	}
+	return store.InfoHashFromBytes(infoHash, b) == nil
+}
+
+// wsParsePeerID decodes a binary string peer id
+func wsParsePeerID(s string) (store.PeerID, bool) {
+	b, ok := wsDecodeBinary(s)
+	if !ok || len(b) != 20 {
+		return store.PeerID{}, false
+	}
+	return store.PeerIDFromString(string(b)), true
+}
+
+// wsDecodeBinary converts a binary string, where each character represents a single byte,
+// into its raw bytes
+func wsDecodeBinary(s string) ([]byte, bool) {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 0xff {
+			return nil, false
+		}
+		b = append(b, byte(r))
+	}
+	return b, true
+}
+
+// wsEncodeBinary converts raw bytes into a binary string
+func wsEncodeBinary(b []byte) string {
+	r := make([]rune, len(b))
+	for i, c := range b {
+		r[i] = rune(c)
+	}
+	return string(r)
+}
+
+// wsUint32 converts a JSON number into a uint32, clamping out of range values
+func wsUint32(f float64) uint32 {
+	if f <= 0 || math.IsNaN(f) {
+		return 0
+	}
+	if f >= math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(f)
+}
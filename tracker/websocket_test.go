@@ -0,0 +1,171 @@
+package tracker
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/leighmacdonald/mika/metrics"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/leighmacdonald/mika/util"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newWebTorrentPeerID(t *testing.T) store.PeerID {
+	b, err := util.GenRandomBytes(12)
+	require.NoError(t, err)
+	return store.PeerIDFromString("-WW0105-" + string(b))
+}
+
+func wsDial(t *testing.T, srv *httptest.Server, passkey string) (*websocket.Conn, *http.Response, error) {
+	u := fmt.Sprintf("ws%s/announce/%s", strings.TrimPrefix(srv.URL, "http"), passkey)
+	header := http.Header{}
+	header.Set("X-Real-IP", "12.34.56.78")
+	return websocket.DefaultDialer.Dial(u, header)
+}
+
+func wsRead(t *testing.T, conn *websocket.Conn) map[string]interface{} {
+	var msg map[string]interface{}
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+func TestWebTorrent(t *testing.T) {
+	require.NoError(t, WhiteListAdd(&store.WhiteListClient{
+		ClientCode: "WW",
+		ClientName: "WebTorrent",
+	}))
+	srv := httptest.NewServer(NewBitTorrentHandler())
+	defer srv.Close()
+	tor := store.GenerateTestTorrent()
+	require.NoError(t, TorrentAdd(&tor))
+	ih := wsEncodeBinary(tor.InfoHash.Bytes())
+
+	_, resp, err := wsDial(t, srv, "XXXXXXXXXXYYYYYYYYYY")
+	require.Error(t, err)
+	require.EqualValues(t, msgInvalidAuth, resp.StatusCode)
+
+	seederID := newWebTorrentPeerID(t)
+	seeder, _, err := wsDial(t, srv, testUsers[0].Passkey)
+	require.NoError(t, err)
+	defer func() { _ = seeder.Close() }()
+	require.NoError(t, seeder.WriteJSON(gin.H{
+		"action":    "announce",
+		"info_hash": ih,
+		"peer_id":   wsEncodeBinary(seederID.Bytes()),
+		"left":      0,
+		"event":     "started",
+	}))
+	msg := wsRead(t, seeder)
+	require.Equal(t, "announce", msg["action"])
+	require.Equal(t, ih, msg["info_hash"])
+	require.EqualValues(t, 1, msg["complete"])
+
+	leecherID := newWebTorrentPeerID(t)
+	leecher, _, err := wsDial(t, srv, testUsers[1].Passkey)
+	require.NoError(t, err)
+	require.NoError(t, leecher.WriteJSON(gin.H{
+		"action":    "announce",
+		"info_hash": ih,
+		"peer_id":   wsEncodeBinary(leecherID.Bytes()),
+		"left":      5000,
+		"event":     "started",
+		"numwant":   5,
+		"offers": []gin.H{
+			{"offer_id": "offer-id-0000000000", "offer": gin.H{"type": "offer", "sdp": "sdp-offer"}},
+		},
+	}))
+	msg = wsRead(t, leecher)
+	require.EqualValues(t, 1, msg["complete"])
+	require.EqualValues(t, 1, msg["incomplete"])
+
+	// The seeder should receive the leechers offer
+	offer := wsRead(t, seeder)
+	require.Equal(t, wsEncodeBinary(leecherID.Bytes()), offer["peer_id"])
+	require.Equal(t, "offer-id-0000000000", offer["offer_id"])
+	require.Equal(t, "sdp-offer", offer["offer"].(map[string]interface{})["sdp"])
+
+	// And the leecher the seeders answer
+	require.NoError(t, seeder.WriteJSON(gin.H{
+		"action":     "announce",
+		"info_hash":  ih,
+		"peer_id":    wsEncodeBinary(seederID.Bytes()),
+		"to_peer_id": offer["peer_id"],
+		"offer_id":   offer["offer_id"],
+		"answer":     gin.H{"type": "answer", "sdp": "sdp-answer"},
+	}))
+	answer := wsRead(t, leecher)
+	require.Equal(t, wsEncodeBinary(seederID.Bytes()), answer["peer_id"])
+	require.Equal(t, "offer-id-0000000000", answer["offer_id"])
+	require.Equal(t, "sdp-answer", answer["answer"].(map[string]interface{})["sdp"])
+
+	require.NoError(t, leecher.WriteJSON(gin.H{"action": "scrape", "info_hash": []string{ih}}))
+	scrape := wsRead(t, leecher)
+	files := scrape["files"].(map[string]interface{})
+	require.EqualValues(t, 1, files[ih].(map[string]interface{})["incomplete"])
+
+	// Browser peers must not be handed out to regular clients
+	peers, err := tor.Peers.GetN(10)
+	require.NoError(t, err)
+	require.Len(t, makeCompactPeers(peers, store.PeerID{}, false, 0), 0)
+
+	// Disconnecting removes the peer from the swarm
+	require.NoError(t, leecher.Close())
+	require.Eventually(t, func() bool {
+		_, err := tor.Peers.Get(leecherID)
+		return err != nil && atomic.LoadUint32(&tor.Leechers) == 0
+	}, time.Second*5, time.Millisecond*10)
+	require.Equal(t, 1, int(atomic.LoadUint32(&tor.Seeders)))
+}
+
+func TestWebTorrentMetrics(t *testing.T) {
+	require.NoError(t, WhiteListAdd(&store.WhiteListClient{
+		ClientCode: "WW",
+		ClientName: "WebTorrent",
+	}))
+	srv := httptest.NewServer(NewBitTorrentHandler())
+	defer srv.Close()
+	total := atomic.LoadInt64(&metrics.AnnounceTotal)
+	unauthorized := atomic.LoadInt64(&metrics.AnnounceStatusUnauthorized)
+	invalidInfoHash := atomic.LoadInt64(&metrics.AnnounceStatusInvalidInfoHash)
+
+	_, _, err := wsDial(t, srv, "XXXXXXXXXXYYYYYYYYYY")
+	require.Error(t, err)
+	require.Equal(t, total+1, atomic.LoadInt64(&metrics.AnnounceTotal))
+	require.Equal(t, unauthorized+1, atomic.LoadInt64(&metrics.AnnounceStatusUnauthorized))
+
+	// Upgrading the connection is not counted as an announce
+	conn, _, err := wsDial(t, srv, testUsers[0].Passkey)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	require.Equal(t, total+1, atomic.LoadInt64(&metrics.AnnounceTotal))
+
+	tor := store.GenerateTestTorrent()
+	require.NoError(t, TorrentAdd(&tor))
+	require.NoError(t, conn.WriteJSON(gin.H{
+		"action":    "announce",
+		"info_hash": wsEncodeBinary(tor.InfoHash.Bytes()),
+		"peer_id":   wsEncodeBinary(newTestPeerID("-XX0001-").Bytes()),
+		"left":      0,
+	}))
+	require.Equal(t, "announce", wsRead(t, conn)["action"])
+	require.Equal(t, total+2, atomic.LoadInt64(&metrics.AnnounceTotal))
+	require.Equal(t, unauthorized+2, atomic.LoadInt64(&metrics.AnnounceStatusUnauthorized))
+
+	unknown := store.GenerateTestTorrent()
+	require.NoError(t, conn.WriteJSON(gin.H{
+		"action":    "announce",
+		"info_hash": wsEncodeBinary(unknown.InfoHash.Bytes()),
+		"peer_id":   wsEncodeBinary(newWebTorrentPeerID(t).Bytes()),
+		"left":      0,
+	}))
+	require.Equal(t, "announce", wsRead(t, conn)["action"])
+	require.Equal(t, total+3, atomic.LoadInt64(&metrics.AnnounceTotal))
+	require.Equal(t, invalidInfoHash+1, atomic.LoadInt64(&metrics.AnnounceStatusInvalidInfoHash))
+}
@@ -0,0 +1,181 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// batch is a single request body waiting to be delivered to an endpoint
+type batch struct {
+	ID          string          `json:"id"`
+	URL         string          `json:"url"`
+	Attempts    int             `json:"attempts"`
+	NextAttempt time.Time       `json:"next_attempt"`
+	Body        json.RawMessage `json:"body"`
+}
+
+// queue holds the batches which have not been delivered yet. Each batch is written to its own
+// file under the queue path so that pending batches are reloaded after a restart. An empty path
+// keeps the queue in memory only.
+type queue struct {
+	path    string
+	pending []*batch
+	seq     uint64
+	*sync.Mutex
+}
+
+// newQueue opens the queue stored at path, creating it if required, and loads any batches
+// left over from a previous run
+func newQueue(path string) (*queue, error) {
+	q := &queue{path: path, Mutex: &sync.Mutex{}}
+	if path == "" {
+		return q, nil
+	}
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, errors.Wrapf(err, "Failed to create webhook queue path")
+	}
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read webhook queue")
+	}
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(path, f.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to read queued webhook batch")
+		}
+		var bt batch
+		if err := json.Unmarshal(b, &bt); err != nil {
+			log.Warnf("Discarding invalid webhook batch %s: %v", f.Name(), err)
+			_ = os.Remove(filepath.Join(path, f.Name()))
+			continue
+		}
+		q.pending = append(q.pending, &bt)
+	}
+	// IDs are time ordered so batches are delivered in the order they were created
+	sort.Slice(q.pending, func(i, j int) bool {
+		return q.pending[i].ID < q.pending[j].ID
+	})
+	return q, nil
+}
+
+// push adds a new batch for the url to the queue
+func (q *queue) push(url string, body []byte) (*batch, error) {
+	q.Lock()
+	defer q.Unlock()
+	q.seq++
+	bt := &batch{
+		ID:          fmt.Sprintf("%020d-%06d", time.Now().UnixNano(), q.seq%1000000),
+		URL:         url,
+		NextAttempt: time.Now(),
+		Body:        body,
+	}
+	if err := q.write(bt); err != nil {
+		return nil, err
+	}
+	q.pending = append(q.pending, bt)
+	return bt, nil
+}
+
+// retry records a failed delivery attempt, scheduling the next attempt at the time given
+func (q *queue) retry(bt *batch, next time.Time) error {
+	q.Lock()
+	defer q.Unlock()
+	bt.Attempts++
+	bt.NextAttempt = next
+	return q.write(bt)
+}
+
+// remove drops the batch from the queue once it has been delivered or discarded
+func (q *queue) remove(bt *batch) error {
+	q.Lock()
+	defer q.Unlock()
+	for i, p := range q.pending {
+		if p == bt {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			break
+		}
+	}
+	if q.path == "" {
+		return nil
+	}
+	if err := os.Remove(q.file(bt)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "Failed to remove webhook batch")
+	}
+	return nil
+}
+
+// due returns the batches which are ready for a delivery attempt
+func (q *queue) due(now time.Time) []*batch {
+	q.Lock()
+	defer q.Unlock()
+	var ready []*batch
+	for _, bt := range q.pending {
+		if !bt.NextAttempt.After(now) {
+			ready = append(ready, bt)
+		}
+	}
+	return ready
+}
+
+// next returns the time of the earliest scheduled delivery attempt
+func (q *queue) next() (time.Time, bool) {
+	q.Lock()
+	defer q.Unlock()
+	var next time.Time
+	for _, bt := range q.pending {
+		if next.IsZero() || bt.NextAttempt.Before(next) {
+			next = bt.NextAttempt
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// all returns all of the undelivered batches
+func (q *queue) all() []*batch {
+	q.Lock()
+	defer q.Unlock()
+	return append([]*batch(nil), q.pending...)
+}
+
+// len returns the number of undelivered batches
+func (q *queue) len() int {
+	q.Lock()
+	defer q.Unlock()
+	return len(q.pending)
+}
+
+func (q *queue) file(bt *batch) string {
+	return filepath.Join(q.path, bt.ID+".json")
+}
+
+// write persists the batch, the caller must hold the lock. The batch is written to a temporary
+// file first so a crash cannot leave a partially written batch behind.
+func (q *queue) write(bt *batch) error {
+	if q.path == "" {
+		return nil
+	}
+	b, err := json.Marshal(bt)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to encode webhook batch")
+	}
+	tmp := q.file(bt) + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return errors.Wrapf(err, "Failed to write webhook batch")
+	}
+	if err := os.Rename(tmp, q.file(bt)); err != nil {
+		return errors.Wrapf(err, "Failed to write webhook batch")
+	}
+	return nil
+}
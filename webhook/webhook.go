@@ -0,0 +1,328 @@
+// Package webhook delivers batches of tracker events to HTTP endpoints for frontends which
+// cannot hold a gRPC stream open.
+//
+// Each request is a JSON encoded Payload POSTed to the endpoint. The body is signed with the
+// endpoints secret using HMAC-SHA256 and sent in the X-Mika-Signature header as
+// sha256=<hex digest>. Endpoints must respond with a 2xx status, anything else is retried
+// with an exponential backoff.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/leighmacdonald/mika/config"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/leighmacdonald/mika/tracker"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+)
+
+const (
+	// SignatureHeader contains the HMAC-SHA256 signature of the request body
+	SignatureHeader = "X-Mika-Signature"
+	// DeliveryHeader contains the unique ID of the batch, which stays the same across retries
+	DeliveryHeader = "X-Mika-Delivery"
+)
+
+// deliveryInterval is the longest the delivery loop waits before checking the queue again
+const deliveryInterval = time.Second
+
+// DefaultEvents are sent to endpoints which do not configure their own list of events
+var DefaultEvents = []tracker.EventType{
+	tracker.EventPeerCompleted,
+	tracker.EventTorrentAutoRegistered,
+	tracker.EventHitAndRun,
+	tracker.EventCheat,
+}
+
+// Event is the JSON representation of a tracker.Event
+type Event struct {
+	Type     string    `json:"type"`
+	Time     time.Time `json:"time"`
+	InfoHash string    `json:"info_hash,omitempty"`
+	PeerID   string    `json:"peer_id,omitempty"`
+	UserID   uint32    `json:"user_id,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// Payload is the body of each webhook request
+type Payload struct {
+	Events []Event `json:"events"`
+}
+
+// Sign returns the signature of the body as sent in the SignatureHeader
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of the body in constant time
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+func newEvent(e tracker.Event) Event {
+	ev := Event{
+		Type:   e.Type.String(),
+		Time:   e.Time,
+		UserID: e.UserID,
+		Reason: e.Reason,
+	}
+	if e.InfoHash != (store.InfoHash{}) {
+		ev.InfoHash = e.InfoHash.String()
+	}
+	if e.PeerID != (store.PeerID{}) {
+		ev.PeerID = e.PeerID.String()
+	}
+	return ev
+}
+
+type endpoint struct {
+	url     string
+	secret  string
+	filter  tracker.EventFilter
+	pending []Event
+}
+
+// Dispatcher collects events from the tracker event bus into batches and delivers them to
+// the configured endpoints
+type Dispatcher struct {
+	cfg       config.WebhookConfig
+	endpoints []*endpoint
+	queue     *queue
+	client    *http.Client
+	// notify wakes the delivery loop when a new batch is queued
+	notify chan struct{}
+}
+
+// New creates a dispatcher for the endpoints in the config, loading any batches which were
+// not delivered before the last shutdown
+func New(cfg config.WebhookConfig) (*Dispatcher, error) {
+	if cfg.BatchSize <= 0 {
+		return nil, errors.New("webhooks.batch_size must be greater than 0")
+	}
+	d := &Dispatcher{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.TimeoutParsed},
+		notify: make(chan struct{}, 1),
+	}
+	for _, ep := range cfg.Endpoints {
+		if ep.URL == "" {
+			return nil, errors.New("webhook url cannot be empty")
+		}
+		e := &endpoint{url: ep.URL, secret: ep.Secret}
+		for _, name := range ep.Events {
+			t, err := tracker.ParseEventType(name)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Invalid event for webhook %s", ep.URL)
+			}
+			e.filter.Types = append(e.filter.Types, t)
+		}
+		if len(e.filter.Types) == 0 {
+			e.filter.Types = DefaultEvents
+		}
+		d.endpoints = append(d.endpoints, e)
+	}
+	q, err := newQueue(cfg.QueuePath)
+	if err != nil {
+		return nil, err
+	}
+	d.queue = q
+	for _, bt := range q.all() {
+		if d.endpoint(bt.URL) == nil {
+			log.Warnf("Discarding webhook batch %s for removed endpoint %s", bt.ID, bt.URL)
+			if err := q.remove(bt); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return d, nil
+}
+
+func (d *Dispatcher) endpoint(url string) *endpoint {
+	for _, ep := range d.endpoints {
+		if ep.url == url {
+			return ep
+		}
+	}
+	return nil
+}
+
+// Start subscribes to the event bus and delivers batches until the context is cancelled.
+// Events which have not been batched yet are queued on shutdown.
+func (d *Dispatcher) Start(ctx context.Context) {
+	var types []tracker.EventType
+	for _, e := range d.endpoints {
+		types = append(types, e.filter.Types...)
+	}
+	sub := tracker.Subscribe(tracker.EventFilter{Types: types})
+	defer tracker.Unsubscribe(sub)
+	go d.deliver(ctx)
+	batchTimer := time.NewTicker(d.cfg.BatchIntervalParsed)
+	defer batchTimer.Stop()
+	for {
+		select {
+		case e := <-sub.C:
+			if dropped := sub.Dropped(); dropped > 0 {
+				log.Warnf("Webhook dispatcher fell behind, %d events were dropped", dropped)
+			}
+			d.add(e)
+		case <-batchTimer.C:
+			d.flush()
+		case <-ctx.Done():
+			d.flush()
+			return
+		}
+	}
+}
+
+// add appends the event to the pending events of the endpoints it should be sent to
+func (d *Dispatcher) add(e tracker.Event) {
+	for _, ep := range d.endpoints {
+		if !ep.filter.Match(e) {
+			continue
+		}
+		ep.pending = append(ep.pending, newEvent(e))
+		if len(ep.pending) >= d.cfg.BatchSize {
+			d.flushEndpoint(ep)
+		}
+	}
+}
+
+// flush queues the pending events of every endpoint
+func (d *Dispatcher) flush() {
+	for _, ep := range d.endpoints {
+		d.flushEndpoint(ep)
+	}
+}
+
+func (d *Dispatcher) flushEndpoint(ep *endpoint) {
+	if len(ep.pending) == 0 {
+		return
+	}
+	body, err := json.Marshal(Payload{Events: ep.pending})
+	ep.pending = nil
+	if err != nil {
+		log.Errorf("Failed to encode webhook payload: %v", err)
+		return
+	}
+	if _, err := d.queue.push(ep.url, body); err != nil {
+		log.Errorf("Failed to queue webhook batch: %v", err)
+		return
+	}
+	select {
+	case d.notify <- struct{}{}:
+	default:
+	}
+}
+
+// deliver sends queued batches as they become due
+func (d *Dispatcher) deliver(ctx context.Context) {
+	t := time.NewTimer(0)
+	defer t.Stop()
+	for {
+		select {
+		case <-d.notify:
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+		// Once a delivery fails the remaining batches for the endpoint are left until the
+		// next attempt, keeping them in order and not waiting on a broken endpoint repeatedly
+		failed := make(map[string]bool)
+		for _, bt := range d.queue.due(time.Now()) {
+			if ctx.Err() != nil {
+				return
+			}
+			if failed[bt.URL] {
+				continue
+			}
+			if !d.send(ctx, bt) {
+				failed[bt.URL] = true
+			}
+		}
+		wait := deliveryInterval
+		if next, found := d.queue.next(); found && time.Until(next) < wait {
+			wait = time.Until(next)
+		}
+		if !t.Stop() {
+			select {
+			case <-t.C:
+			default:
+			}
+		}
+		t.Reset(wait)
+	}
+}
+
+// send makes a single delivery attempt of the batch, scheduling a retry or discarding the
+// batch on failure. Returns true if the batch was delivered.
+func (d *Dispatcher) send(ctx context.Context, bt *batch) bool {
+	err := d.post(ctx, bt)
+	if err == nil {
+		if errRm := d.queue.remove(bt); errRm != nil {
+			log.Errorf("%v", errRm)
+		}
+		return true
+	}
+	if ctx.Err() != nil {
+		// Shutting down, the attempt is retried after the restart
+		return false
+	}
+	if d.cfg.RetryMax > 0 && bt.Attempts+1 >= d.cfg.RetryMax {
+		log.Errorf("Discarding webhook batch %s for %s after %d attempts: %v", bt.ID, bt.URL, bt.Attempts+1, err)
+		if errRm := d.queue.remove(bt); errRm != nil {
+			log.Errorf("%v", errRm)
+		}
+		return false
+	}
+	next := time.Now().Add(d.backoff(bt.Attempts))
+	log.Warnf("Failed to deliver webhook batch %s to %s, retrying at %s: %v", bt.ID, bt.URL, next, err)
+	if errRetry := d.queue.retry(bt, next); errRetry != nil {
+		log.Errorf("%v", errRetry)
+	}
+	return false
+}
+
+// backoff returns the delay before the next attempt after the number of failed attempts
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.RetryBackoffParsed
+	for i := 0; i < attempts; i++ {
+		delay *= 2
+		if delay >= d.cfg.RetryBackoffMaxParsed {
+			return d.cfg.RetryBackoffMaxParsed
+		}
+	}
+	return delay
+}
+
+func (d *Dispatcher) post(ctx context.Context, bt *batch) error {
+	ep := d.endpoint(bt.URL)
+	if ep == nil {
+		return errors.New("unknown endpoint")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, bt.URL, bytes.NewReader(bt.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(ep.secret, bt.Body))
+	req.Header.Set(DeliveryHeader, bt.ID)
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/leighmacdonald/mika/config"
+	"github.com/leighmacdonald/mika/store"
+	"github.com/leighmacdonald/mika/tracker"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testSecret = "test-secret"
+
+// testServer records the payloads of each request, responding with the next status from statuses
+// and 200 once they have all been used
+type testServer struct {
+	*httptest.Server
+	statuses   []int
+	payloads   []Payload
+	deliveries []string
+	*sync.Mutex
+}
+
+func newTestServer(t *testing.T, statuses ...int) *testServer {
+	ts := &testServer{statuses: statuses, Mutex: &sync.Mutex{}}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.True(t, Verify(testSecret, body, r.Header.Get(SignatureHeader)))
+		ts.Lock()
+		defer ts.Unlock()
+		ts.deliveries = append(ts.deliveries, r.Header.Get(DeliveryHeader))
+		if len(ts.statuses) > 0 {
+			status := ts.statuses[0]
+			ts.statuses = ts.statuses[1:]
+			if status != http.StatusOK {
+				w.WriteHeader(status)
+				return
+			}
+		}
+		var p Payload
+		require.NoError(t, json.Unmarshal(body, &p))
+		ts.payloads = append(ts.payloads, p)
+	}))
+	return ts
+}
+
+func (ts *testServer) events() []Event {
+	ts.Lock()
+	defer ts.Unlock()
+	var events []Event
+	for _, p := range ts.payloads {
+		events = append(events, p.Events...)
+	}
+	return events
+}
+
+func testConfig(url string, queuePath string) config.WebhookConfig {
+	return config.WebhookConfig{
+		Enabled:               true,
+		QueuePath:             queuePath,
+		BatchSize:             2,
+		BatchIntervalParsed:   10 * time.Millisecond,
+		TimeoutParsed:         time.Second,
+		RetryMax:              5,
+		RetryBackoffParsed:    time.Millisecond,
+		RetryBackoffMaxParsed: 4 * time.Millisecond,
+		Endpoints: []config.WebhookEndpoint{
+			{URL: url, Secret: testSecret, Events: []string{"peer_completed", "cheat"}},
+		},
+	}
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"events":[]}`)
+	sig := Sign(testSecret, body)
+	require.Equal(t, "sha256=", sig[0:7])
+	require.Len(t, sig, 7+64)
+	require.True(t, Verify(testSecret, body, sig))
+	require.False(t, Verify("other", body, sig))
+	require.False(t, Verify(testSecret, []byte(`{"events":[{}]}`), sig))
+}
+
+func TestNew(t *testing.T) {
+	cfg := testConfig("http://localhost", "")
+	cfg.Endpoints[0].Events = []string{"peer_completed", "invalid"}
+	_, err := New(cfg)
+	require.Error(t, err)
+
+	cfg.Endpoints[0].Events = nil
+	d, err := New(cfg)
+	require.NoError(t, err)
+	require.Equal(t, DefaultEvents, d.endpoints[0].filter.Types)
+}
+
+func TestDispatcher(t *testing.T) {
+	ts := newTestServer(t)
+	defer ts.Close()
+	cfg := testConfig(ts.URL, "")
+	cfg.Endpoints[0].Events = []string{"torrent_added", "user_added"}
+	d, err := New(cfg)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.Start(ctx)
+	// Let the dispatcher subscribe before publishing
+	time.Sleep(20 * time.Millisecond)
+
+	tor := store.GenerateTestTorrent()
+	require.NoError(t, tracker.TorrentAdd(&tor))
+	// Not sent to the endpoint
+	require.NoError(t, tracker.TorrentDelete(&tor))
+	usr := store.GenerateTestUser()
+	require.NoError(t, tracker.UserAdd(&usr))
+	require.Eventually(t, func() bool {
+		return len(ts.events()) == 2 && d.queue.len() == 0
+	}, time.Second, 5*time.Millisecond)
+	events := ts.events()
+	require.Equal(t, "torrent_added", events[0].Type)
+	require.Equal(t, tor.InfoHash.String(), events[0].InfoHash)
+	require.Empty(t, events[0].PeerID)
+	require.False(t, events[0].Time.IsZero())
+	require.Equal(t, "user_added", events[1].Type)
+	require.Equal(t, usr.UserID, events[1].UserID)
+	require.Empty(t, events[1].InfoHash)
+}
+
+func TestDispatcherRetry(t *testing.T) {
+	ts := newTestServer(t, http.StatusInternalServerError, http.StatusBadGateway)
+	defer ts.Close()
+	d, err := New(testConfig(ts.URL, ""))
+	require.NoError(t, err)
+	d.add(tracker.Event{Type: tracker.EventCheat, UserID: 1})
+	d.flush()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.deliver(ctx)
+	require.Eventually(t, func() bool {
+		return len(ts.events()) == 1 && d.queue.len() == 0
+	}, time.Second, 5*time.Millisecond)
+	ts.Lock()
+	require.Len(t, ts.deliveries, 3)
+	// The delivery id is the same for each attempt
+	require.Equal(t, ts.deliveries[0], ts.deliveries[2])
+	ts.Unlock()
+}
+
+func TestDispatcherDiscard(t *testing.T) {
+	ts := newTestServer(t, 500, 500, 500, 500, 500, 500)
+	defer ts.Close()
+	cfg := testConfig(ts.URL, "")
+	cfg.RetryMax = 2
+	d, err := New(cfg)
+	require.NoError(t, err)
+	d.add(tracker.Event{Type: tracker.EventCheat, UserID: 1})
+	d.flush()
+	bt := d.queue.all()[0]
+	require.False(t, d.send(context.Background(), bt))
+	require.Equal(t, 1, d.queue.len())
+	require.Equal(t, 1, bt.Attempts)
+	require.False(t, d.send(context.Background(), bt))
+	require.Equal(t, 0, d.queue.len())
+}
+
+func TestDispatcherBackoff(t *testing.T) {
+	d := &Dispatcher{cfg: config.WebhookConfig{
+		RetryBackoffParsed:    10 * time.Second,
+		RetryBackoffMaxParsed: time.Minute,
+	}}
+	require.Equal(t, 10*time.Second, d.backoff(0))
+	require.Equal(t, 20*time.Second, d.backoff(1))
+	require.Equal(t, 40*time.Second, d.backoff(2))
+	require.Equal(t, time.Minute, d.backoff(3))
+	require.Equal(t, time.Minute, d.backoff(30))
+}
+
+func TestDispatcherPersistent(t *testing.T) {
+	queuePath, err := ioutil.TempDir("", "mika-webhooks")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(queuePath) }()
+
+	ts := newTestServer(t)
+	defer ts.Close()
+	// The server is unavailable at first, the batch stays in the queue
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	cfg := testConfig(down.URL, queuePath)
+	d, err := New(cfg)
+	require.NoError(t, err)
+	d.add(tracker.Event{Type: tracker.EventPeerCompleted, UserID: 10})
+	d.add(tracker.Event{Type: tracker.EventPeerCompleted, UserID: 11})
+	d.add(tracker.Event{Type: tracker.EventPeerCompleted, UserID: 12})
+	d.flush()
+	require.Equal(t, 2, d.queue.len())
+	for _, bt := range d.queue.all() {
+		require.False(t, d.send(context.Background(), bt))
+	}
+	down.Close()
+
+	// Simulate a restart with the endpoint now available at the same url
+	q, err := newQueue(queuePath)
+	require.NoError(t, err)
+	require.Equal(t, 2, q.len())
+	for _, bt := range q.all() {
+		require.Equal(t, 1, bt.Attempts)
+		bt.URL = ts.URL
+		require.NoError(t, q.write(bt))
+	}
+	cfg.Endpoints[0].URL = ts.URL
+	d2, err := New(cfg)
+	require.NoError(t, err)
+	require.Equal(t, 2, d2.queue.len())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d2.deliver(ctx)
+	require.Eventually(t, func() bool {
+		return len(ts.events()) == 3 && d2.queue.len() == 0
+	}, time.Second, 5*time.Millisecond)
+	// Delivered in the order they were queued
+	for i, e := range ts.events() {
+		require.Equal(t, uint32(10+i), e.UserID)
+	}
+	files, err := ioutil.ReadDir(queuePath)
+	require.NoError(t, err)
+	require.Len(t, files, 0)
+
+	// Batches for endpoints which have been removed from the config are discarded
+	d2.add(tracker.Event{Type: tracker.EventPeerCompleted, UserID: 10})
+	d2.flush()
+	cancel()
+	cfg.Endpoints[0].URL = "http://localhost/removed"
+	d3, err := New(cfg)
+	require.NoError(t, err)
+	require.Equal(t, 0, d3.queue.len())
+}